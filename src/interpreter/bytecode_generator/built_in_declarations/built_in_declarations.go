@@ -179,6 +179,49 @@ var (
 		Name: "__constructor__",
 		Type: parser_types.NormalFunction,
 	}
+
+	StructArgumentsField = &BuiltInField{
+		Name: "__arguments__",
+		Type: nil,
+	}
+
+	StructTraitsField = &BuiltInField{
+		Name: "__traits__",
+		Type: nil,
+	}
+
+	StructCopyMethod = &BuiltInField{
+		Name: "__copy__",
+		Type: parser_types.NormalFunction,
+	}
+)
+
+// Implemented on caught errors
+var (
+	ErrorSectionField = &BuiltInField{
+		Name: "section",
+		Type: nil,
+	}
+
+	ErrorCodeField = &BuiltInField{
+		Name: "code",
+		Type: nil,
+	}
+
+	ErrorNameField = &BuiltInField{
+		Name: "name",
+		Type: nil,
+	}
+
+	ErrorDescriptionField = &BuiltInField{
+		Name: "description",
+		Type: nil,
+	}
+
+	ErrorValueField = &BuiltInField{
+		Name: "value",
+		Type: nil,
+	}
 )
 
 // Implemented on libraries
@@ -187,6 +230,16 @@ var (
 		Name: "get",
 		Type: parser_types.NormalFunction,
 	}
+
+	LibraryHasMethod = &BuiltInField{
+		Name: "has",
+		Type: parser_types.NormalFunction,
+	}
+
+	LibrarySymbolsField = &BuiltInField{
+		Name: "symbols",
+		Type: nil,
+	}
 )
 
 type BuiltInValueID int
@@ -202,4 +255,10 @@ const (
 	ModuleFunctionID
 	TupleFunctionID
 	StructFunctionID
+	DestructureTupleFunctionID
+	DestructureStructFunctionID
+	TraitFunctionID
+	ImplementsFunctionID
+	FailFunctionID
+	TryFunctionID
 )
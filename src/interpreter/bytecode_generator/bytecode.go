@@ -115,15 +115,47 @@
  * - 2: Infix syntax ("foo - bar")
  * - 3: Prefix syntax ("-foo")
  *
- * PUSH_FN (5) (ARG_COUNT):
+ * PUSH_FN (5) (ARG_COUNT, IS_VARIADIC, NAME_CONST_ID, FILENAME_CONST_ID, OFFSET):
  *  Push a function accepting `ARG_COUNT` arguments to the function stack.
  *
+ *  If `IS_VARIADIC` is 1, the function's last parameter is a rest parameter, and the function
+ *  accepts at least `ARG_COUNT - 1` arguments, collecting any remaining ones into a tuple.
+ *
+ *  The remaining arguments describe the function's source, and don't affect its behaviour: the
+ *  string constants referred to by `NAME_CONST_ID` and `FILENAME_CONST_ID` are its qualified name
+ *  (e.g. "Some.map") and the file declaring it, and `OFFSET` is where it's declared in that file.
+ *  `NAME_CONST_ID` is -1 for functions generated while desugaring (e.g. the branches of an `if`
+ *  expression), which have no source of their own.
+ *
  * POP_FN (6):
  *  Pop the current function from the function stack.
  *
  * VAL_COPY (7) (VAL_ID):
  *  Retrieve the value referred to by `VAL_ID` from the value list and push it to the value list
  *  again.
+ *
+ * PUSH_ARGS (8) (VAL_ID):
+ *  Like `PUSH_ARG`, but push every element of the tuple referred to by `VAL_ID` to the argument
+ *  stack.
+ *
+ *  If `VAL_ID` doesn't refer to a tuple, the runtime will panic.
+ *
+ * NAME_VAL (9) (VAL_ID, CONST_ID):
+ *  Name the value referred to by `VAL_ID` after the string constant referred to by `CONST_ID`
+ *  within the current function, for debuggers to look it up by. Like `PUSH_FN`'s description of
+ *  its source, it doesn't affect evaluation.
+ *
+ * BRANCH (10) (FILENAME_CONST_ID, OFFSET, INDEX):
+ *  Mark the current function as a branch of the `if` expression at `OFFSET` in the file named by
+ *  the string constant referred to by `FILENAME_CONST_ID`: the branch taken if its condition is
+ *  true if `INDEX` is 0, and the other otherwise. It doesn't affect evaluation either, but lets
+ *  coverage reports count the branches taken.
+ *
+ * Debugging:
+ *
+ * Each instruction also records the position of the statement it was translated from (if any), so
+ * that debuggers can map lines of source to the instructions evaluating them (and coverage reports
+ * can map instructions evaluated back to lines).
  */
 package bytecode_generator
 
@@ -131,7 +163,9 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/ugorji/go/codec"
 
@@ -140,20 +174,27 @@ import (
 	"project_umbrella/interpreter/errors"
 	"project_umbrella/interpreter/errors/parser_errors"
 	"project_umbrella/interpreter/parser"
+	"project_umbrella/interpreter/parser/parser_types"
 )
 
 const checksumSize = 32
 
 var builtInValues = map[string]built_in_declarations.BuiltInValueID{
-	"__if_else__":    built_in_declarations.IfElseFunctionID,
-	"__module__":     built_in_declarations.ModuleFunctionID,
-	"__struct__":     built_in_declarations.StructFunctionID,
-	"__tuple__":      built_in_declarations.TupleFunctionID,
-	"false":          built_in_declarations.FalseValueID,
-	"import":         built_in_declarations.ImportFunctionID,
-	"import_library": built_in_declarations.ImportLibraryFunctionID,
-	"true":           built_in_declarations.TrueValueID,
-	"unit":           built_in_declarations.UnitValueID,
+	"__destructure_struct__": built_in_declarations.DestructureStructFunctionID,
+	"__destructure_tuple__":  built_in_declarations.DestructureTupleFunctionID,
+	"__if_else__":            built_in_declarations.IfElseFunctionID,
+	"__implements__":         built_in_declarations.ImplementsFunctionID,
+	"__module__":             built_in_declarations.ModuleFunctionID,
+	"__struct__":             built_in_declarations.StructFunctionID,
+	"__trait__":              built_in_declarations.TraitFunctionID,
+	"__tuple__":              built_in_declarations.TupleFunctionID,
+	"fail":                   built_in_declarations.FailFunctionID,
+	"false":                  built_in_declarations.FalseValueID,
+	"import":                 built_in_declarations.ImportFunctionID,
+	"import_library":         built_in_declarations.ImportLibraryFunctionID,
+	"true":                   built_in_declarations.TrueValueID,
+	"try":                    built_in_declarations.TryFunctionID,
+	"unit":                   built_in_declarations.UnitValueID,
 }
 
 func sourceChecksum(fileContent string) [checksumSize]byte {
@@ -197,6 +238,40 @@ type BytecodeTranslator struct {
 	constantIDMap map[Constant]int
 	instructions  []*Instruction
 	scopeStack    []*scope
+
+	/*
+	 * The identifier declaring the value each translated identifier refers to (declaring
+	 * identifiers refer to themselves). Built-in values aren't declared by any identifier.
+	 */
+	declarations map[*parser.Identifier]*parser.Identifier
+
+	/*
+	 * Functions assigned to a single name (e.g. `double = (x): x * 2`) are referred to by that name
+	 * in `PUSH_FN` instructions, like those declared with `fn`.
+	 */
+	assignedFunctionNames map[*parser.Function]string
+
+	// Functions generated while desugaring, which `PUSH_FN` instructions leave unnamed
+	generatedFunctions map[*parser.Function]bool
+
+	// The branches of `if` expressions, which are marked by `BRANCH` instructions
+	branches map[*parser.Function]*branch
+
+	/*
+	 * The qualified names of the functions being translated, innermost last. Generated functions
+	 * repeat the name of the function containing them, so the functions they contain are qualified
+	 * as if they weren't there.
+	 */
+	functionNameStack []string
+
+	/*
+	 * The number of structs whose code is being translated. Within them, selects of private fields
+	 * from values that may be the struct's own instances are checked at runtime instead.
+	 */
+	structDepth int
+
+	// The identifiers bound to imported modules (e.g. `io` in `io = import("io")`)
+	moduleDeclarations map[*parser.Identifier]bool
 }
 
 func NewBytecodeTranslator(fileContent string) *BytecodeTranslator {
@@ -204,17 +279,39 @@ func NewBytecodeTranslator(fileContent string) *BytecodeTranslator {
 		fileContent:   fileContent,
 		constantIDMap: map[Constant]int{},
 		instructions:  []*Instruction{},
-		scopeStack: []*scope{
-			{
-				constantValueIDMap:   map[int]int{},
-				identifierValueIDMap: map[string]int{},
-				functionValueIDMap:   map[*parser.Function]int{},
-				nextValueID:          0,
-			},
-		},
+		scopeStack:    []*scope{newScope(0)},
+		declarations:  map[*parser.Identifier]*parser.Identifier{},
+
+		assignedFunctionNames: map[*parser.Function]string{},
+		generatedFunctions:    map[*parser.Function]bool{},
+		branches:              map[*parser.Function]*branch{},
+		functionNameStack:     []string{""},
+		structDepth:           0,
+		moduleDeclarations:    map[*parser.Identifier]bool{},
 	}
 }
 
+/*
+ * Find the identifier declaring the value an identifier refers to, resolving names the same way
+ * translation does. Only identifiers in expressions that have been translated can be resolved.
+ */
+func (translator *BytecodeTranslator) Declaration(
+	identifier *parser.Identifier,
+) (*parser.Identifier, bool) {
+	result, ok := translator.declarations[identifier]
+
+	return result, ok
+}
+
+/*
+ * Whether a name refers to a built-in value (e.g. `true` or `import`) wherever it isn't declared.
+ */
+func IsBuiltInName(name string) bool {
+	_, ok := builtInValues[name]
+
+	return ok
+}
+
 func (translator *BytecodeTranslator) constantIDForConstant(constant Constant) int {
 	var constantID int
 
@@ -233,6 +330,26 @@ func (translator *BytecodeTranslator) currentScope() *scope {
 	return translator.scopeStack[len(translator.scopeStack)-1]
 }
 
+func (translator *BytecodeTranslator) declareIdentifier(identifier *parser.Identifier, valueID int) {
+	translator.currentScope().identifierValueIDMap[identifier.Value] = valueID
+	translator.currentScope().identifierDeclarationMap[identifier.Value] = identifier
+	translator.declarations[identifier] = identifier
+
+	// Identifiers generated while desugaring (e.g. `__struct__`'s arguments) aren't named in source.
+	if identifier.Position() != nil {
+		translator.instructions = append(translator.instructions, &Instruction{
+			Type: NameValueInstruction,
+			Arguments: []int{
+				valueID,
+				translator.constantIDForConstant(Constant{
+					Type:    StringConstant,
+					Encoded: identifier.Value,
+				}),
+			},
+		})
+	}
+}
+
 func (translator *BytecodeTranslator) ExpressionToBytecode(expression parser.Expression) *Bytecode {
 	expressionList, ok := expression.(*parser.ExpressionList)
 
@@ -276,41 +393,213 @@ func (translator *BytecodeTranslator) generateBytecode() *Bytecode {
 }
 
 func (translator *BytecodeTranslator) valueIDForAssignment(assignment *parser.Assignment) int {
+	if function, ok := assignment.Value.(*parser.Function); ok &&
+		function.Name == nil &&
+		len(assignment.Names_) == 1 &&
+		len(assignment.Patterns) == 0 {
+		translator.assignedFunctionNames[function] = assignment.Names_[0].Value
+	}
+
+	if translator.isImport(assignment.Value) {
+		for _, nameExpression := range assignment.Names_ {
+			translator.moduleDeclarations[nameExpression] = true
+		}
+	}
+
 	valueID := translator.valueIDForExpression(assignment.Value)
 
 	/*
 	 * We check for value overloading in a separate pass because we don't want to leave
 	 * `translator.identifierValueIDMap` in a bad state, in case the caller decides to recover.
+	 *
+	 * Although the same name can be assigned to a value multiple times (e.g. `foo = foo = 1`),
+	 * names within patterns are bound to different values and must therefore be unique.
 	 */
-	for _, nameExpression := range assignment.Names_ {
-		if _, ok := translator.valueIDForNonBuiltInIdentifierInScope(nameExpression); ok {
-			errors.RaisePositionalError(
-				&errors.PositionalError{
-					Error:    parser_errors.ValueReassigned,
-					Position: assignment.Position(),
-				},
-			)
+	for _, nameExpression := range assignment.Names() {
+		isReassigned := false
+
+		if assignment.IsParameter {
+			_, isReassigned =
+				translator.currentScope().identifierValueIDMap[nameExpression.Value]
+		} else {
+			_, isReassigned = translator.valueIDForNonBuiltInIdentifierInScope(nameExpression)
+		}
+
+		if isReassigned {
+			raiseValueReassignedError(assignment)
+		}
+	}
+
+	patternNames := map[string]bool{}
+
+	for _, pattern := range assignment.Patterns {
+		for _, nameExpression := range pattern.Names() {
+			if patternNames[nameExpression.Value] {
+				raiseValueReassignedError(assignment)
+			}
+
+			patternNames[nameExpression.Value] = true
 		}
 	}
 
 	for _, nameExpression := range assignment.Names_ {
-		translator.currentScope().identifierValueIDMap[nameExpression.Value] = valueID
+		translator.declareIdentifier(nameExpression, valueID)
+	}
+
+	for _, pattern := range assignment.Patterns {
+		translator.bindPattern(pattern, valueID)
 	}
 
 	return valueID
 }
 
+func raiseValueReassignedError(assignment *parser.Assignment) {
+	errors.RaisePositionalError(
+		&errors.PositionalError{
+			Error:    parser_errors.ValueReassigned,
+			Position: assignment.Position(),
+		},
+	)
+}
+
+/*
+ * Destructure the value referred to by `valueID` according to a pattern, binding each name in the
+ * pattern to the corresponding element.
+ *
+ * Patterns are desugared into a call to `__destructure_tuple__` or `__destructure_struct__`, which
+ * check that the value has the pattern's shape and return its elements (or the struct's arguments)
+ * as a tuple, followed by a call to that tuple's `get` method for each element of the pattern.
+ */
+func (translator *BytecodeTranslator) bindPattern(pattern *parser.Pattern, valueID int) {
+	destructureFunctionID := builtInValues["__destructure_tuple__"]
+	destructureArgumentValueIDs := []int{
+		valueID,
+		translator.valueIDForConstant(newIntegerConstant(int64(len(pattern.Elements)))),
+	}
+
+	if pattern.Constructor != nil {
+		destructureFunctionID = builtInValues["__destructure_struct__"]
+		destructureArgumentValueIDs = append(
+			destructureArgumentValueIDs,
+			translator.valueIDForIdentifier(pattern.Constructor),
+			translator.valueIDForConstant(
+				Constant{
+					Type:    StringConstant,
+					Encoded: pattern.Constructor.Value,
+				},
+			),
+		)
+	}
+
+	elementsValueID := translator.valueIDForCallWithArguments(
+		int(destructureFunctionID),
+		destructureArgumentValueIDs,
+	)
+
+	getMethodValueID := translator.valueIDForSelectFromValueID(
+		elementsValueID,
+		built_in_declarations.OrderedGetMethod.Name,
+		parser_types.NormalSelect,
+	)
+
+	for i, element := range pattern.Elements {
+		elementValueID := translator.valueIDForCallWithArguments(
+			getMethodValueID,
+			[]int{translator.valueIDForConstant(newIntegerConstant(int64(i)))},
+		)
+
+		switch element := element.(type) {
+		case *parser.Identifier:
+			translator.declareIdentifier(element, elementValueID)
+
+		case *parser.Pattern:
+			translator.bindPattern(element, elementValueID)
+		}
+	}
+}
+
 func (translator *BytecodeTranslator) valueIDForCall(call *parser.Call) int {
+	/*
+	 * Desugaring generates functions called immediately (e.g. for `let` expressions and
+	 * `from ... import` statements) and functions passed to built-in functions (e.g. the branches of
+	 * `if` expressions and the functions creating structs' fields).
+	 */
+	if function, ok := call.Function.(*parser.Function); ok {
+		translator.generatedFunctions[function] = true
+
+		// `from ... import` statements pass the module to such a function.
+		for i, argument := range call.Arguments {
+			if i < len(function.Parameters) && translator.isImport(argument) {
+				translator.moduleDeclarations[function.Parameters[i]] = true
+			}
+		}
+	}
+
+	if identifier, ok := call.Function.(*parser.Identifier); ok &&
+		call.IsBuiltInCall(identifier.Value) {
+		if identifier.Value == "__struct__" {
+			translator.structDepth++
+
+			defer func() {
+				translator.structDepth--
+			}()
+		}
+
+		for i, argument := range call.Arguments {
+			if function, ok := argument.(*parser.Function); ok {
+				translator.generatedFunctions[function] = true
+
+				// `__if_else__`'s arguments are its condition followed by its branches.
+				if identifier.Value == "__if_else__" && call.Position() != nil {
+					translator.branches[function] = &branch{
+						position: call.Position(),
+						index:    i - 1,
+					}
+				}
+			}
+		}
+	}
+
 	functionValueID := translator.valueIDForExpression(call.Function)
 	pushArgumentInstructions := make([]*Instruction, 0, len(call.Arguments))
 
 	for _, argument := range call.Arguments {
+		instructionType := PushArgumentInstruction
+
+		if spread, ok := argument.(*parser.Spread); ok {
+			argument = spread.Value
+			instructionType = PushArgumentsInstruction
+		}
+
 		pushArgumentInstructions = append(pushArgumentInstructions, &Instruction{
-			Type:      PushArgumentInstruction,
+			Type:      instructionType,
 			Arguments: []int{translator.valueIDForExpression(argument)},
 		})
 	}
 
+	return translator.valueIDForCallWithInstructions(functionValueID, pushArgumentInstructions)
+}
+
+func (translator *BytecodeTranslator) valueIDForCallWithArguments(
+	functionValueID int,
+	argumentValueIDs []int,
+) int {
+	pushArgumentInstructions := make([]*Instruction, 0, len(argumentValueIDs))
+
+	for _, argumentValueID := range argumentValueIDs {
+		pushArgumentInstructions = append(pushArgumentInstructions, &Instruction{
+			Type:      PushArgumentInstruction,
+			Arguments: []int{argumentValueID},
+		})
+	}
+
+	return translator.valueIDForCallWithInstructions(functionValueID, pushArgumentInstructions)
+}
+
+func (translator *BytecodeTranslator) valueIDForCallWithInstructions(
+	functionValueID int,
+	pushArgumentInstructions []*Instruction,
+) int {
 	/*
 	 * We don't append the `PUSH_ARG` instructions until after translating each argument because
 	 * that translation could entail more calls, clearing the argument stack before we're able to
@@ -381,16 +670,7 @@ func (translator *BytecodeTranslator) valueIDForExpression(expression parser.Exp
 		result = translator.valueIDForIdentifier(expression)
 
 	case *parser.Integer:
-		var buffer bytes.Buffer
-
-		if err := binary.Write(&buffer, binary.LittleEndian, expression.Value); err != nil {
-			panic(err)
-		}
-
-		result = translator.valueIDForConstant(Constant{
-			Type:    IntegerConstant,
-			Encoded: buffer.String(),
-		})
+		result = translator.valueIDForConstant(newIntegerConstant(expression.Value))
 
 	case *parser.Select:
 		result = translator.valueIDForSelect(expression)
@@ -429,8 +709,7 @@ func (translator *BytecodeTranslator) valueIDForExpressionList(
 					)
 				}
 
-				translator.currentScope().identifierValueIDMap[function.Name.Value] =
-					functionValueID
+				translator.declareIdentifier(function.Name, functionValueID)
 			}
 
 			translator.currentScope().functionValueIDMap[function] = functionValueID
@@ -445,9 +724,28 @@ func (translator *BytecodeTranslator) valueIDForExpressionList(
 	}
 
 	returnValueID := int(builtInValues["unit"])
+	var returnPosition *errors.Position
 
 	for _, subexpression := range expressionList.Children_ {
+		firstInstruction := len(translator.instructions)
 		returnValueID = translator.valueIDForExpression(subexpression)
+
+		/*
+		 * Instructions are attributed to the innermost statement they were translated from, so those
+		 * already attributed to statements nested in this one are left as they are. They share the
+		 * statement's position, so that coverage can tell which were translated from the same one.
+		 */
+		position := subexpression.Position()
+
+		if position != nil {
+			for _, instruction := range translator.instructions[firstInstruction:] {
+				if instruction.Position == nil {
+					instruction.Position = position
+				}
+			}
+		}
+
+		returnPosition = position
 	}
 
 	/*
@@ -464,32 +762,95 @@ func (translator *BytecodeTranslator) valueIDForExpressionList(
 	translator.instructions = append(translator.instructions, &Instruction{
 		Type:      ValueCopyInstruction,
 		Arguments: []int{returnValueID},
+		Position:  returnPosition,
 	})
 
 	return returnValueID
 }
 
 func (translator *BytecodeTranslator) valueIDForFunction(function *parser.Function) int {
+	isVariadic := 0
+
+	if function.IsVariadic {
+		isVariadic = 1
+	}
+
+	qualifiedName := translator.functionNameStack[len(translator.functionNameStack)-1]
+	nameConstantID := -1
+	filenameConstantID := -1
+	offset := 0
+
+	if !translator.generatedFunctions[function] {
+		name := "(function)"
+
+		if function.Name != nil {
+			name = function.Name.Value
+		} else if assignedName, ok := translator.assignedFunctionNames[function]; ok {
+			name = assignedName
+		}
+
+		if qualifiedName == "" {
+			qualifiedName = name
+		} else {
+			qualifiedName += "." + name
+		}
+
+		nameConstantID = translator.constantIDForConstant(Constant{
+			Type:    StringConstant,
+			Encoded: qualifiedName,
+		})
+
+		if position := function.Position(); position != nil {
+			filenameConstantID = translator.constantIDForConstant(Constant{
+				Type:    StringConstant,
+				Encoded: position.Filename,
+			})
+
+			offset = position.Start
+		}
+	}
+
 	translator.instructions = append(translator.instructions, &Instruction{
-		Type:      PushFunctionInstruction,
-		Arguments: []int{len(function.Parameters)},
+		Type: PushFunctionInstruction,
+		Arguments: []int{
+			len(function.Parameters),
+			isVariadic,
+			nameConstantID,
+			filenameConstantID,
+			offset,
+		},
 	})
 
-	scope := &scope{
-		constantValueIDMap:   map[int]int{},
-		identifierValueIDMap: make(map[string]int, len(function.Parameters)),
-		functionValueIDMap:   map[*parser.Function]int{},
-		nextValueID:          translator.currentScope().nextValueID,
+	if branch_, ok := translator.branches[function]; ok {
+		translator.instructions = append(translator.instructions, &Instruction{
+			Type: BranchInstruction,
+			Arguments: []int{
+				translator.constantIDForConstant(Constant{
+					Type:    StringConstant,
+					Encoded: branch_.position.Filename,
+				}),
+
+				branch_.position.Start,
+				branch_.index,
+			},
+		})
 	}
 
+	translator.functionNameStack = append(translator.functionNameStack, qualifiedName)
+
+	translator.scopeStack = append(
+		translator.scopeStack,
+		newScope(translator.currentScope().nextValueID),
+	)
+
 	for _, parameter := range function.Parameters {
-		scope.identifierValueIDMap[parameter.Value] = scope.nextValueID
-		scope.nextValueID++
+		translator.declareIdentifier(parameter, translator.currentScope().nextValueID)
+		translator.currentScope().nextValueID++
 	}
 
-	translator.scopeStack = append(translator.scopeStack, scope)
 	translator.valueIDForExpression(function.Body)
 	translator.scopeStack = translator.scopeStack[:len(translator.scopeStack)-1]
+	translator.functionNameStack = translator.functionNameStack[:len(translator.functionNameStack)-1]
 	translator.instructions = append(translator.instructions, &Instruction{
 		Type: PopFunctionInstruction,
 	})
@@ -508,6 +869,8 @@ func (translator *BytecodeTranslator) valueIDForFunction(function *parser.Functi
 
 func (translator *BytecodeTranslator) valueIDForIdentifier(identifier *parser.Identifier) int {
 	if valueID, ok := translator.valueIDForNonBuiltInIdentifierInScope(identifier); ok {
+		translator.declarations[identifier] = translator.declarationInScope(identifier)
+
 		return valueID
 	}
 
@@ -547,21 +910,102 @@ func (translator *BytecodeTranslator) valueIDForNonBuiltInIdentifierInScope(
 	return 0, false
 }
 
+func (translator *BytecodeTranslator) declarationInScope(
+	identifier *parser.Identifier,
+) *parser.Identifier {
+	for i := len(translator.scopeStack) - 1; i >= 0; i-- {
+		if result, ok := translator.scopeStack[i].identifierDeclarationMap[identifier.Value]; ok {
+			return result
+		}
+	}
+
+	return nil
+}
+
 func (translator *BytecodeTranslator) valueIDForSelect(select_ *parser.Select) int {
+	if parser_types.IsPrivateName(select_.Field.Value) &&
+		(translator.structDepth == 0 || translator.isNonInstance(select_.Value)) {
+		errors.RaisePositionalError(
+			&errors.PositionalError{
+				Error:    parser_errors.PrivateFieldSelected(select_.Field.Value),
+				Position: select_.Field.Position(),
+			},
+		)
+	}
+
+	return translator.valueIDForSelectFromValueID(
+		translator.valueIDForExpression(select_.Value),
+		select_.Field.Value,
+		select_.Type,
+	)
+}
+
+/*
+ * Whether an expression is a call to the built-in `import` or `import_library`, evaluating to a
+ * module.
+ */
+func (translator *BytecodeTranslator) isImport(expression parser.Expression) bool {
+	call, ok := expression.(*parser.Call)
+
+	if !ok {
+		return false
+	}
+
+	identifier, ok := call.Function.(*parser.Identifier)
+
+	if !ok || (identifier.Value != "import" && identifier.Value != "import_library") {
+		return false
+	}
+
+	_, isShadowed := translator.valueIDForNonBuiltInIdentifierInScope(identifier)
+
+	return !isShadowed
+}
+
+/*
+ * Whether an expression is known not to evaluate to a struct instance (e.g. because it's a literal,
+ * a built-in or a module), so a struct's code can't select its private fields either.
+ */
+func (translator *BytecodeTranslator) isNonInstance(expression parser.Expression) bool {
+	switch expression := expression.(type) {
+	case *parser.Float, *parser.Function, *parser.Integer, *parser.String:
+		return true
+
+	case *parser.Call:
+		return translator.isImport(expression)
+
+	case *parser.Identifier:
+		if declaration := translator.declarationInScope(expression); declaration != nil {
+			return translator.moduleDeclarations[declaration]
+		}
+
+		_, isBuiltIn := builtInValues[expression.Value]
+
+		return isBuiltIn
+	}
+
+	return false
+}
+
+func (translator *BytecodeTranslator) valueIDForSelectFromValueID(
+	valueID int,
+	fieldName string,
+	selectType parser_types.SelectType,
+) int {
 	translator.instructions = append(
 		translator.instructions,
 		&Instruction{
 			Type: ValueFromStructValueInstruction,
 			Arguments: []int{
-				translator.valueIDForExpression(select_.Value),
+				valueID,
 				translator.constantIDForConstant(
 					Constant{
 						Type:    StringConstant,
-						Encoded: select_.Field.Value,
+						Encoded: fieldName,
 					},
 				),
 
-				int(select_.Type),
+				int(selectType),
 			},
 		},
 	)
@@ -578,6 +1022,19 @@ type Constant struct {
 	Encoded string
 }
 
+func newIntegerConstant(value int64) Constant {
+	var buffer bytes.Buffer
+
+	if err := binary.Write(&buffer, binary.LittleEndian, value); err != nil {
+		panic(err)
+	}
+
+	return Constant{
+		Type:    IntegerConstant,
+		Encoded: buffer.String(),
+	}
+}
+
 type ConstantType int
 
 const (
@@ -589,6 +1046,9 @@ const (
 type Instruction struct {
 	Type      InstructionType
 	Arguments []int
+
+	// The statement the instruction was translated from, or nil if it was generated without one
+	Position *errors.Position
 }
 
 type InstructionType int
@@ -601,11 +1061,78 @@ const (
 	PushFunctionInstruction
 	PopFunctionInstruction
 	ValueCopyInstruction
+	PushArgumentsInstruction
+	NameValueInstruction
+	BranchInstruction
 )
 
+// The instruction's name as documented above, followed by its arguments (e.g. "VAL_FROM_CALL 3")
+func (instruction *Instruction) String() string {
+	parts := []string{instruction.Type.String()}
+
+	for _, argument := range instruction.Arguments {
+		parts = append(parts, fmt.Sprint(argument))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (instructionType InstructionType) String() string {
+	switch instructionType {
+	case PushArgumentInstruction:
+		return "PUSH_ARG"
+
+	case ValueFromCallInstruction:
+		return "VAL_FROM_CALL"
+
+	case ValueFromConstantInstruction:
+		return "VAL_FROM_CONST"
+
+	case ValueFromStructValueInstruction:
+		return "VAL_FROM_STRUCT_VAL"
+
+	case PushFunctionInstruction:
+		return "PUSH_FN"
+
+	case PopFunctionInstruction:
+		return "POP_FN"
+
+	case ValueCopyInstruction:
+		return "VAL_COPY"
+
+	case PushArgumentsInstruction:
+		return "PUSH_ARGS"
+
+	case NameValueInstruction:
+		return "NAME_VAL"
+
+	case BranchInstruction:
+		return "BRANCH"
+	}
+
+	return fmt.Sprintf("UNKNOWN (%d)", int(instructionType))
+}
+
+// A branch of an `if` expression: its condition being true (0) or false (1)
+type branch struct {
+	position *errors.Position // That of the `if` expression
+	index    int
+}
+
 type scope struct {
-	constantValueIDMap   map[int]int
-	identifierValueIDMap map[string]int
-	functionValueIDMap   map[*parser.Function]int
-	nextValueID          int
+	constantValueIDMap       map[int]int
+	identifierValueIDMap     map[string]int
+	identifierDeclarationMap map[string]*parser.Identifier
+	functionValueIDMap       map[*parser.Function]int
+	nextValueID              int
+}
+
+func newScope(nextValueID int) *scope {
+	return &scope{
+		constantValueIDMap:       map[int]int{},
+		identifierValueIDMap:     map[string]int{},
+		identifierDeclarationMap: map[string]*parser.Identifier{},
+		functionValueIDMap:       map[*parser.Function]int{},
+		nextValueID:              nextValueID,
+	}
 }
@@ -1,5 +1,9 @@
 package common
 
+import (
+	"slices"
+)
+
 type BinaryTree[T any] struct {
 	Left  *BinaryTree[T]
 	Right *BinaryTree[T]
@@ -216,10 +220,43 @@ func (graph *ConsolidatedGraph[T]) Consolidate() {
 func (graph *ConsolidatedGraph[T]) Evaluate(
 	evaluator func(consolidatedNode *ConsolidatedGraphNode[T]),
 ) bool {
-	return graph.dependencies.Evaluate(
+	return graph.EvaluateWithReadiness(
+		evaluator,
+		func(*ConsolidatedGraphNode[T]) {},
+	)
+}
+
+// Like `DirectedGraph#EvaluateWithReadiness`, but yields consolidated nodes.
+func (graph *ConsolidatedGraph[T]) EvaluateWithReadiness(
+	evaluator func(consolidatedNode *ConsolidatedGraphNode[T]),
+	ready func(consolidatedNode *ConsolidatedGraphNode[T]),
+) bool {
+	return graph.dependencies.EvaluateWithReadiness(
+		func(i int) {
+			evaluator(graph.dependencies.GetNode(i))
+		},
+
+		func(i int) {
+			ready(graph.dependencies.GetNode(i))
+		},
+	)
+}
+
+/*
+ * Like `Evaluate`, but always evaluate the ready consolidated node containing the earliest node
+ * added, so that nodes are evaluated in the order they were added wherever dependencies allow.
+ */
+func (graph *ConsolidatedGraph[T]) EvaluateInOrder(
+	evaluator func(consolidatedNode *ConsolidatedGraphNode[T]),
+) bool {
+	return graph.dependencies.EvaluateInOrder(
 		func(i int) {
 			evaluator(graph.dependencies.GetNode(i))
 		},
+
+		func(i int) int {
+			return slices.Min(graph.dependencies.GetNode(i).Nodes())
+		},
 	)
 }
 
@@ -356,6 +393,18 @@ func (graph *DirectedGraph[T]) AddNode(node T) int {
  * The function returns whether the graph is acyclic (i.e. whether every node was processed).
  */
 func (graph *DirectedGraph[T]) Evaluate(evaluator func(i int)) bool {
+	return graph.EvaluateWithReadiness(evaluator, func(int) {})
+}
+
+/*
+ * Like `Evaluate`, but also call `ready` with the index of each node as soon as it becomes a leaf
+ * (i.e. once every node it depends on has been processed). Nodes that are ready at the same time
+ * could be processed in parallel.
+ */
+func (graph *DirectedGraph[T]) EvaluateWithReadiness(
+	evaluator func(i int),
+	ready func(i int),
+) bool {
 	dependencyCount := make(map[int]int, len(graph.nodes))
 
 	for _, dependents := range graph.edges {
@@ -374,6 +423,7 @@ func (graph *DirectedGraph[T]) Evaluate(evaluator func(i int)) bool {
 
 	for i, n := range dependencyCount {
 		if n == 0 {
+			ready(i)
 			stack = append(stack, i)
 		}
 	}
@@ -391,6 +441,7 @@ func (graph *DirectedGraph[T]) Evaluate(evaluator func(i int)) bool {
 			dependencyCount[j]--
 
 			if dependencyCount[j] == 0 {
+				ready(j)
 				stack = append(stack, j)
 			}
 		}
@@ -401,6 +452,60 @@ func (graph *DirectedGraph[T]) Evaluate(evaluator func(i int)) bool {
 	return processed == len(graph.nodes)
 }
 
+/*
+ * Like `Evaluate`, but always evaluate the ready node of the lowest `priority` rather than any
+ * ready node, so that the order of evaluation is deterministic.
+ */
+func (graph *DirectedGraph[T]) EvaluateInOrder(
+	evaluator func(i int),
+	priority func(i int) int,
+) bool {
+	dependencyCount := make(map[int]int, len(graph.nodes))
+
+	for _, dependents := range graph.edges {
+		for dependent := range dependents {
+			dependencyCount[dependent]++
+		}
+	}
+
+	ready := []int{}
+
+	for i := range graph.nodes {
+		if dependencyCount[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	processed := 0
+
+	for len(ready) > 0 {
+		next := 0
+
+		for j := range ready {
+			if priority(ready[j]) < priority(ready[next]) {
+				next = j
+			}
+		}
+
+		i := ready[next]
+		ready = append(ready[:next], ready[next+1:]...)
+
+		evaluator(i)
+
+		for j := range graph.edges[i] {
+			dependencyCount[j]--
+
+			if dependencyCount[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+
+		processed++
+	}
+
+	return processed == len(graph.nodes)
+}
+
 func (graph *DirectedGraph[T]) GetEdgesFrom(i int) []int {
 	if edges, ok := graph.edges[i]; ok {
 		result := []int{}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"project_umbrella/interpreter/coverage"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/runtime"
+	"project_umbrella/interpreter/standard_library"
+)
+
+/*
+ * `interpreter run --coverage <data> <file>` runs the given file, then writes the statements it
+ * executed and the branches it took to the data file, merged with the data already there (if any).
+ * The data is written even if the program fails.
+ */
+func coverRun(instruments *runtime.Instruments, dataPath string) instrumentOutput {
+	coverage_ := coverage.NewCoverage()
+	instruments.Coverage = coverage_
+
+	if _, err := os.Stat(dataPath); err == nil {
+		coverage_.Merge(readCoverageData(dataPath))
+	}
+
+	return instrumentOutput{
+		path: dataPath,
+		write: func(writer io.Writer) error {
+			return coverage_.Data().Write(writer)
+		},
+	}
+}
+
+/*
+ * `interpreter coverage lcov <data>...` reports the merged coverage data of the given files in the
+ * LCOV format, and `interpreter coverage annotate <data>...` reports it as annotated source.
+ */
+func runCoverageCommand(arguments []string) {
+	if len(arguments) < 2 {
+		errors.RaiseError(entry_errors.CoverageReportNotSpecified)
+	}
+
+	data := coverage.NewData()
+
+	for _, path := range arguments[1:] {
+		data.Merge(readCoverageData(path))
+	}
+
+	sources := map[string]string{}
+
+	for _, filename := range data.Filenames() {
+		source, err := standard_library.ReadFile(filename)
+
+		if err != nil {
+			errors.RaiseError(entry_errors.FileNotOpened(filename))
+		}
+
+		sources[filename] = string(source)
+	}
+
+	var err error
+
+	switch arguments[0] {
+	case "lcov":
+		err = data.WriteLCOV(os.Stdout, sources)
+
+	case "annotate":
+		err = data.WriteAnnotatedSource(os.Stdout, sources)
+
+	default:
+		errors.RaiseError(entry_errors.UnknownCoverageReportFormat(arguments[0]))
+	}
+
+	if err != nil {
+		errors.RaiseError(entry_errors.FileNotWritten(os.Stdout.Name()))
+	}
+}
+
+func readCoverageData(path string) *coverage.Data {
+	file, err := os.Open(path)
+
+	if err != nil {
+		errors.RaiseError(entry_errors.FileNotOpened(path))
+	}
+
+	defer file.Close()
+
+	result, err := coverage.ReadData(file)
+
+	if err != nil {
+		errors.RaiseError(entry_errors.CoverageDataNotRead(path))
+	}
+
+	return result
+}
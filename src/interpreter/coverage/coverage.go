@@ -0,0 +1,149 @@
+/*
+ * Coverage:
+ *
+ * When measuring coverage, the runtime counts how many times each instruction translated from a
+ * statement is evaluated, and how many times each branch of each `if` expression is taken. A
+ * statement is considered executed as many times as the most evaluated of its instructions, so
+ * statements translated to no instructions of their own (e.g. function declarations) aren't
+ * counted at all.
+ *
+ * The counts are saved as coverage data, which can be merged with that of other runs (e.g. of the
+ * tests of a library's modules) and reported in the LCOV format or as annotated source.
+ */
+package coverage
+
+import (
+	"path/filepath"
+	"sync"
+
+	"project_umbrella/interpreter/bytecode_generator"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/standard_library"
+)
+
+type Coverage struct {
+	mutex sync.Mutex
+
+	// The number of times each instruction has been evaluated, keyed by those with positions
+	instructions map[*bytecode_generator.Instruction]int
+	branches     map[Branch]int
+
+	// That of previous runs, merged into this run's
+	previous *Data
+}
+
+func NewCoverage() *Coverage {
+	return &Coverage{
+		instructions: map[*bytecode_generator.Instruction]int{},
+		branches:     map[Branch]int{},
+		previous:     NewData(),
+	}
+}
+
+// Start counting the evaluations of an instruction, unless it wasn't translated from a statement.
+func (coverage *Coverage) AddInstruction(instruction *bytecode_generator.Instruction) {
+	if instruction.Position == nil {
+		return
+	}
+
+	coverage.mutex.Lock()
+	defer coverage.mutex.Unlock()
+
+	if _, ok := coverage.instructions[instruction]; !ok {
+		coverage.instructions[instruction] = 0
+	}
+}
+
+func (coverage *Coverage) InstructionEvaluated(instruction *bytecode_generator.Instruction) {
+	if instruction.Position == nil {
+		return
+	}
+
+	coverage.mutex.Lock()
+	defer coverage.mutex.Unlock()
+
+	coverage.instructions[instruction]++
+}
+
+// Start counting the times a branch is taken.
+func (coverage *Coverage) AddBranch(branch Branch) {
+	coverage.mutex.Lock()
+	defer coverage.mutex.Unlock()
+
+	if _, ok := coverage.branches[branch]; !ok {
+		coverage.branches[branch] = 0
+	}
+}
+
+func (coverage *Coverage) BranchTaken(branch Branch) {
+	coverage.mutex.Lock()
+	defer coverage.mutex.Unlock()
+
+	coverage.branches[branch]++
+}
+
+// Add the data of previous runs to this run's.
+func (coverage *Coverage) Merge(data *Data) {
+	coverage.mutex.Lock()
+	defer coverage.mutex.Unlock()
+
+	coverage.previous.Merge(data)
+}
+
+/*
+ * The counts of this run, merged with those of previous runs. Files are named by their absolute
+ * paths, so that the data of runs from different directories (or importing the same files from
+ * different modules) can be merged.
+ */
+func (coverage *Coverage) Data() *Data {
+	coverage.mutex.Lock()
+	defer coverage.mutex.Unlock()
+
+	result := NewData()
+	result.Merge(coverage.previous)
+
+	/*
+	 * The instructions translated from a statement share its position, while those of a statement
+	 * translated more than once (e.g. those of the startup file, which is translated with every
+	 * module) don't, so their counts are added together.
+	 */
+	positionCounts := map[*errors.Position]int{}
+
+	for instruction, count := range coverage.instructions {
+		positionCounts[instruction.Position] = max(positionCounts[instruction.Position], count)
+	}
+
+	statements := map[Statement]int{}
+	branches := map[Branch]int{}
+
+	for position, count := range positionCounts {
+		statements[Statement{
+			Filename: absolutePath(position.Filename),
+			Start:    position.Start,
+			End:      position.End,
+		}] += count
+	}
+
+	for branch, count := range coverage.branches {
+		branch.Filename = absolutePath(branch.Filename)
+		branches[branch] += count
+	}
+
+	result.Merge(&Data{
+		Statements: statements,
+		Branches:   branches,
+	})
+
+	return result
+}
+
+// Embedded files' paths are left as they are, since they're already unambiguous.
+func absolutePath(path string) string {
+	result, err := filepath.Abs(path)
+
+	if err != nil || standard_library.IsEmbeddedPath(path) {
+		return path
+	}
+
+	return result
+}
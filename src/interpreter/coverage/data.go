@@ -0,0 +1,174 @@
+package coverage
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+/*
+ * The number of times each statement was executed and each branch was taken. Statements and
+ * branches are identified by where they are in their files, so data can only be merged with (or
+ * reported against) files that haven't changed since.
+ */
+type Data struct {
+	Statements map[Statement]int
+	Branches   map[Branch]int
+}
+
+type Statement struct {
+	Filename string
+	Start    int
+	End      int
+}
+
+type Branch struct {
+	Filename string
+	Offset   int // Where the `if` expression starts
+	Index    int // 0 for the branch taken if the condition is true, 1 for the other
+}
+
+// Coverage data is saved as JSON, with statements and branches listed in the order of their files.
+type encodedData struct {
+	Statements []*encodedStatement `json:"statements"`
+	Branches   []*encodedBranch    `json:"branches"`
+}
+
+type encodedStatement struct {
+	Filename string `json:"filename"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Count    int    `json:"count"`
+}
+
+type encodedBranch struct {
+	Filename string `json:"filename"`
+	Offset   int    `json:"offset"`
+	Index    int    `json:"index"`
+	Count    int    `json:"count"`
+}
+
+func NewData() *Data {
+	return &Data{
+		Statements: map[Statement]int{},
+		Branches:   map[Branch]int{},
+	}
+}
+
+// Add the counts of `other` to the data's.
+func (data *Data) Merge(other *Data) {
+	for statement, count := range other.Statements {
+		data.Statements[statement] += count
+	}
+
+	for branch, count := range other.Branches {
+		data.Branches[branch] += count
+	}
+}
+
+func ReadData(reader io.Reader) (*Data, error) {
+	encoded := &encodedData{}
+
+	if err := json.NewDecoder(reader).Decode(encoded); err != nil {
+		return nil, err
+	}
+
+	result := NewData()
+
+	for _, statement := range encoded.Statements {
+		result.Statements[Statement{
+			Filename: statement.Filename,
+			Start:    statement.Start,
+			End:      statement.End,
+		}] += statement.Count
+	}
+
+	for _, branch := range encoded.Branches {
+		result.Branches[Branch{
+			Filename: branch.Filename,
+			Offset:   branch.Offset,
+			Index:    branch.Index,
+		}] += branch.Count
+	}
+
+	return result, nil
+}
+
+func (data *Data) Write(writer io.Writer) error {
+	encoded := &encodedData{
+		Statements: make([]*encodedStatement, 0, len(data.Statements)),
+		Branches:   make([]*encodedBranch, 0, len(data.Branches)),
+	}
+
+	for _, statement := range data.sortedStatements() {
+		encoded.Statements = append(encoded.Statements, &encodedStatement{
+			Filename: statement.Filename,
+			Start:    statement.Start,
+			End:      statement.End,
+			Count:    data.Statements[statement],
+		})
+	}
+
+	for _, branch := range data.sortedBranches() {
+		encoded.Branches = append(encoded.Branches, &encodedBranch{
+			Filename: branch.Filename,
+			Offset:   branch.Offset,
+			Index:    branch.Index,
+			Count:    data.Branches[branch],
+		})
+	}
+
+	result, err := json.MarshalIndent(encoded, "", "\t")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(append(result, '\n'))
+
+	return err
+}
+
+func (data *Data) sortedStatements() []Statement {
+	result := make([]Statement, 0, len(data.Statements))
+
+	for statement := range data.Statements {
+		result = append(result, statement)
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		if result[i].Filename != result[j].Filename {
+			return result[i].Filename < result[j].Filename
+		}
+
+		if result[i].Start != result[j].Start {
+			return result[i].Start < result[j].Start
+		}
+
+		return result[i].End < result[j].End
+	})
+
+	return result
+}
+
+func (data *Data) sortedBranches() []Branch {
+	result := make([]Branch, 0, len(data.Branches))
+
+	for branch := range data.Branches {
+		result = append(result, branch)
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		if result[i].Filename != result[j].Filename {
+			return result[i].Filename < result[j].Filename
+		}
+
+		if result[i].Offset != result[j].Offset {
+			return result[i].Offset < result[j].Offset
+		}
+
+		return result[i].Index < result[j].Index
+	})
+
+	return result
+}
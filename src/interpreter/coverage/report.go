@@ -0,0 +1,249 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// The counts of a file's lines and branches, as reported
+type fileReport struct {
+	filename string
+	lines    []string
+
+	// The number of times the statements starting on each line were executed, at most
+	lineCounts map[int]int
+
+	ifs []*ifReport // In the order of their positions
+}
+
+type ifReport struct {
+	line         int
+	branchCounts []int
+}
+
+// Whether any of the `if` expression's branches were taken (i.e. whether it was evaluated at all)
+func (if_ *ifReport) isEvaluated() bool {
+	for _, count := range if_.branchCounts {
+		if count > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The files covered by the data, which are named as they were when it was recorded
+func (data *Data) Filenames() []string {
+	filenameSet := map[string]bool{}
+
+	for statement := range data.Statements {
+		filenameSet[statement.Filename] = true
+	}
+
+	for branch := range data.Branches {
+		filenameSet[branch.Filename] = true
+	}
+
+	result := make([]string, 0, len(filenameSet))
+
+	for filename := range filenameSet {
+		result = append(result, filename)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Report each file's counts, locating them in `sources` (the files' contents, keyed by filename).
+func (data *Data) fileReports(sources map[string]string) []*fileReport {
+	result := []*fileReport{}
+	resultMap := map[string]*fileReport{}
+	lineStartMap := map[string][]int{}
+
+	for _, filename := range data.Filenames() {
+		report := &fileReport{
+			filename:   filename,
+			lines:      strings.Split(strings.TrimSuffix(sources[filename], "\n"), "\n"),
+			lineCounts: map[int]int{},
+			ifs:        []*ifReport{},
+		}
+
+		lineStarts := []int{0}
+
+		for i, character := range sources[filename] {
+			if character == '\n' {
+				lineStarts = append(lineStarts, i+1)
+			}
+		}
+
+		result = append(result, report)
+		resultMap[filename] = report
+		lineStartMap[filename] = lineStarts
+	}
+
+	// Lines are numbered from 1, so an offset's line is the number of lines starting at or before it.
+	lineOfOffset := func(filename string, offset int) int {
+		lineStarts := lineStartMap[filename]
+
+		return sort.Search(len(lineStarts), func(i int) bool {
+			return lineStarts[i] > offset
+		})
+	}
+
+	for statement, count := range data.Statements {
+		report := resultMap[statement.Filename]
+		line := lineOfOffset(statement.Filename, statement.Start)
+
+		report.lineCounts[line] = max(report.lineCounts[line], count)
+	}
+
+	lastIf := Branch{
+		Filename: "",
+		Offset:   -1,
+		Index:    0,
+	}
+
+	for _, branch := range data.sortedBranches() {
+		report := resultMap[branch.Filename]
+
+		if branch.Filename != lastIf.Filename || branch.Offset != lastIf.Offset {
+			report.ifs = append(report.ifs, &ifReport{
+				line:         lineOfOffset(branch.Filename, branch.Offset),
+				branchCounts: []int{},
+			})
+
+			lastIf = branch
+		}
+
+		if_ := report.ifs[len(report.ifs)-1]
+		if_.branchCounts = append(if_.branchCounts, data.Branches[branch])
+	}
+
+	return result
+}
+
+/*
+ * Write the data in the LCOV tracefile format, read by tools like `genhtml` and most coverage
+ * services. Each `if` expression is a block of two branches.
+ */
+func (data *Data) WriteLCOV(writer io.Writer, sources map[string]string) error {
+	var builder strings.Builder
+
+	for _, report := range data.fileReports(sources) {
+		fmt.Fprintf(&builder, "TN:\nSF:%s\n", report.filename)
+
+		branchCount := 0
+		branchesTakenCount := 0
+
+		for i, if_ := range report.ifs {
+			for j, count := range if_.branchCounts {
+				taken := "-"
+
+				if if_.isEvaluated() {
+					taken = fmt.Sprint(count)
+				}
+
+				if count > 0 {
+					branchesTakenCount++
+				}
+
+				branchCount++
+				fmt.Fprintf(&builder, "BRDA:%d,%d,%d,%s\n", if_.line, i, j, taken)
+			}
+		}
+
+		fmt.Fprintf(&builder, "BRF:%d\nBRH:%d\n", branchCount, branchesTakenCount)
+
+		linesHitCount := 0
+
+		for _, line := range report.sortedLines() {
+			if report.lineCounts[line] > 0 {
+				linesHitCount++
+			}
+
+			fmt.Fprintf(&builder, "DA:%d,%d\n", line, report.lineCounts[line])
+		}
+
+		fmt.Fprintf(
+			&builder,
+			"LF:%d\nLH:%d\nend_of_record\n",
+			len(report.lineCounts),
+			linesHitCount,
+		)
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
+}
+
+/*
+ * Write each file's source annotated like `gcov`'s output: each line is preceded by the number of
+ * times the statements starting on it were executed ("-" if none do, and "#####" if they never
+ * were) and followed by the number of times each branch of the `if` expressions on it was taken.
+ */
+func (data *Data) WriteAnnotatedSource(writer io.Writer, sources map[string]string) error {
+	var builder strings.Builder
+
+	for i, report := range data.fileReports(sources) {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		fmt.Fprintf(&builder, "%9s:%5d:Source:%s\n", "-", 0, report.filename)
+
+		ifLineMap := map[int][]*ifReport{}
+
+		for _, if_ := range report.ifs {
+			ifLineMap[if_.line] = append(ifLineMap[if_.line], if_)
+		}
+
+		for j, line := range report.lines {
+			lineNumber := j + 1
+			count := "-"
+
+			if lineCount, ok := report.lineCounts[lineNumber]; ok {
+				count = "#####"
+
+				if lineCount > 0 {
+					count = fmt.Sprint(lineCount)
+				}
+			}
+
+			fmt.Fprintf(&builder, "%9s:%5d:%s\n", count, lineNumber, line)
+
+			branchNumber := 0
+
+			for _, if_ := range ifLineMap[lineNumber] {
+				for _, branchCount := range if_.branchCounts {
+					if if_.isEvaluated() {
+						fmt.Fprintf(&builder, "branch %2d taken %d\n", branchNumber, branchCount)
+					} else {
+						fmt.Fprintf(&builder, "branch %2d never executed\n", branchNumber)
+					}
+
+					branchNumber++
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
+}
+
+func (report *fileReport) sortedLines() []int {
+	result := make([]int, 0, len(report.lineCounts))
+
+	for line := range report.lineCounts {
+		result = append(result, line)
+	}
+
+	sort.Ints(result)
+
+	return result
+}
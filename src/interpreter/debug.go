@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"project_umbrella/interpreter/debugger_frontend"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+)
+
+/*
+ * `interpreter debug <file>` debugs the given file with commands read from stdin, and
+ * `interpreter debug --dap` runs a Debug Adapter Protocol server over stdin and stdout, debugging
+ * the file the client launches.
+ */
+func runDebugCommand(arguments []string) {
+	if len(arguments) != 1 {
+		errors.RaiseError(entry_errors.DebugFileNotSpecified)
+	}
+
+	if arguments[0] == "--dap" {
+		debugger_frontend.ServeDebugAdapter(os.Stdin, os.Stdout)
+
+		return
+	}
+
+	if err := debugger_frontend.RunConsole(arguments[0], os.Stdin, os.Stdout); err != nil {
+		errors.RaiseError(err)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * The Debugger:
+ *
+ * When debugging, the runtime reports each function it calls and each block it's about to evaluate
+ * to the debugger, which pauses it there whenever a breakpoint is hit, a step completes or a pause
+ * is requested. Each block is attributed to the line of the statement its instructions were
+ * translated from, so breakpoints set on a line are hit before the first block evaluating it.
+ *
+ * Each module is evaluated by its own runtime (and goroutine), which the debugger refers to as a
+ * thread; threads are paused and resumed independently.
+ *
+ * Functions generated while desugaring (e.g. the branches of `if` expressions) aren't shown as
+ * frames of their own, nor do they count as calls when stepping; their blocks are attributed to the
+ * frame of the function containing them.
+ *
+ * Frontends (i.e. the CLI and the Debug Adapter Protocol server) control the debugger from their
+ * own goroutines, receiving what happens through `Events`.
+ */
+package debugger
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"project_umbrella/interpreter/standard_library"
+)
+
+type Debugger struct {
+	mutex sync.Mutex
+
+	// The lines to pause at, keyed by the absolute paths of their files
+	breakpoints   map[string]map[int]bool
+	absolutePaths map[string]string
+
+	threads      map[int]*Thread
+	nextThreadID int
+
+	// Whether the first thread started should pause before evaluating anything
+	stopOnEntry bool
+
+	Events chan *Event
+}
+
+func NewDebugger() *Debugger {
+	return &Debugger{
+		breakpoints:   map[string]map[int]bool{},
+		absolutePaths: map[string]string{},
+		threads:       map[int]*Thread{},
+		nextThreadID:  1,
+		stopOnEntry:   false,
+
+		// Threads block while sending events, so frontends must keep receiving them.
+		Events: make(chan *Event, 64),
+	}
+}
+
+type EventType int
+
+const (
+	ThreadStartedEvent EventType = iota + 1
+	ThreadExitedEvent
+	StoppedEvent
+)
+
+type StopReason string
+
+const (
+	EntryStop      StopReason = "entry"
+	BreakpointStop StopReason = "breakpoint"
+	StepStop       StopReason = "step"
+	PauseStop      StopReason = "pause"
+)
+
+type Event struct {
+	Type   EventType
+	Thread *Thread
+	Reason StopReason // Only set for `StoppedEvent`s
+}
+
+/*
+ * The values a function can refer to by name, as implemented by the runtime. Each scope's parent is
+ * that of the function the function was declared in.
+ */
+type Scope interface {
+	Function() string    // The qualified name of the function the scope belongs to
+	Variables() []*Value // Sorted by name, excluding values not yet evaluated
+	Parent() Scope       // Nil for the scope of a module
+}
+
+type Value struct {
+	Name  string
+	Value string // As displayed by `__to_str__`, with strings quoted
+}
+
+// A function being evaluated, as shown to frontends
+type Frame struct {
+	Function string
+	Filename string
+	Line     int // Starting from 1, or 0 if no block of the function has been evaluated yet
+	Column   int
+
+	// The scope of the innermost function generated within the frame's function, if any
+	scope Scope
+}
+
+// Pause the first thread started before it evaluates anything.
+func (debugger *Debugger) StopOnEntry() {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+
+	debugger.stopOnEntry = true
+}
+
+/*
+ * Replace the breakpoints set in the file at `path` with breakpoints at `lines` (starting from 1).
+ */
+func (debugger *Debugger) SetBreakpoints(path string, lines []int) {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+
+	fileBreakpoints := map[int]bool{}
+
+	for _, line := range lines {
+		fileBreakpoints[line] = true
+	}
+
+	debugger.breakpoints[debugger.absolutePath(path)] = fileBreakpoints
+}
+
+// Embedded files' paths are left as they are, since they're already unambiguous.
+func (debugger *Debugger) absolutePath(path string) string {
+	if result, ok := debugger.absolutePaths[path]; ok {
+		return result
+	}
+
+	result, err := filepath.Abs(path)
+
+	if err != nil || standard_library.IsEmbeddedPath(path) {
+		result = path
+	}
+
+	debugger.absolutePaths[path] = result
+
+	return result
+}
+
+// The threads currently running or paused, ordered by ID
+func (debugger *Debugger) Threads() []*Thread {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+
+	result := make([]*Thread, 0, len(debugger.threads))
+
+	for _, thread := range debugger.threads {
+		result = append(result, thread)
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+func (debugger *Debugger) Thread(id int) (*Thread, bool) {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+
+	result, ok := debugger.threads[id]
+
+	return result, ok
+}
+
+// The values named in the functions whose scopes a frame's function can refer to
+type FunctionValues struct {
+	Function string
+	Values   []*Value // Sorted by name, excluding those shadowed by inner functions' values
+}
+
+/*
+ * The values a frame's function can refer to by name, innermost function first. The scopes of
+ * functions generated within another (e.g. the branches of `if` expressions) are merged into that
+ * function's.
+ */
+func (frame *Frame) Values() []*FunctionValues {
+	result := []*FunctionValues{}
+	isShadowed := map[string]bool{}
+
+	for scope := frame.scope; scope != nil; scope = scope.Parent() {
+		if len(result) == 0 || result[len(result)-1].Function != scope.Function() {
+			result = append(result, &FunctionValues{
+				Function: scope.Function(),
+				Values:   []*Value{},
+			})
+		}
+
+		functionValues := result[len(result)-1]
+
+		for _, value := range scope.Variables() {
+			if !isShadowed[value.Name] {
+				isShadowed[value.Name] = true
+				functionValues.Values = append(functionValues.Values, value)
+			}
+		}
+	}
+
+	for _, functionValues := range result {
+		sort.Slice(functionValues.Values, func(i int, j int) bool {
+			return functionValues.Values[i].Name < functionValues.Values[j].Name
+		})
+	}
+
+	return result
+}
+
+// Find the value `name` refers to within a frame, the same way the function would.
+func (frame *Frame) Lookup(name string) (*Value, bool) {
+	for _, functionValues := range frame.Values() {
+		for _, value := range functionValues.Values {
+			if value.Name == name {
+				return value, true
+			}
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,307 @@
+package debugger
+
+import (
+	"project_umbrella/interpreter/errors"
+)
+
+// The runtime evaluating a module, as observed by the debugger
+type Thread struct {
+	ID   int
+	Name string // That of the module
+
+	debugger *Debugger
+	resume   chan struct{}
+
+	// The remaining fields are guarded by the debugger's mutex.
+
+	frames         []*frame // Innermost last
+	isStopped      bool
+	stopOnEntry    bool
+	pauseRequested bool
+
+	// How the thread was last resumed, and where it was at the time
+	step         stepType
+	stepDepth    int
+	stepFilename string
+	stepLine     int
+}
+
+type stepType int
+
+const (
+	noStep stepType = iota
+	stepIn
+	stepOver
+	stepOut
+)
+
+type frame struct {
+	function    string
+	isGenerated bool
+	scope       Scope
+
+	// Where the block most recently evaluated in the frame starts (unset for generated frames)
+	filename string
+	line     int
+	column   int
+}
+
+// Start observing a runtime named `name`, which must call `Exited` once it has finished.
+func (debugger *Debugger) NewThread(name string) *Thread {
+	debugger.mutex.Lock()
+
+	result := &Thread{
+		ID:          debugger.nextThreadID,
+		Name:        name,
+		debugger:    debugger,
+		resume:      make(chan struct{}),
+		frames:      []*frame{},
+		stopOnEntry: debugger.stopOnEntry,
+	}
+
+	debugger.threads[result.ID] = result
+	debugger.nextThreadID++
+	debugger.stopOnEntry = false
+	debugger.mutex.Unlock()
+
+	debugger.Events <- &Event{
+		Type:   ThreadStartedEvent,
+		Thread: result,
+	}
+
+	return result
+}
+
+func (thread *Thread) Exited() {
+	thread.debugger.mutex.Lock()
+	delete(thread.debugger.threads, thread.ID)
+	thread.debugger.mutex.Unlock()
+
+	thread.debugger.Events <- &Event{
+		Type:   ThreadExitedEvent,
+		Thread: thread,
+	}
+}
+
+// Record a call to `function`, which must be followed by a call to `Exit`.
+func (thread *Thread) Enter(function string, isGenerated bool, scope Scope) {
+	thread.debugger.mutex.Lock()
+	defer thread.debugger.mutex.Unlock()
+
+	thread.frames = append(thread.frames, &frame{
+		function:    function,
+		isGenerated: isGenerated,
+		scope:       scope,
+	})
+}
+
+// Record the end of the call most recently entered.
+func (thread *Thread) Exit() {
+	thread.debugger.mutex.Lock()
+	defer thread.debugger.mutex.Unlock()
+
+	thread.frames = thread.frames[:len(thread.frames)-1]
+}
+
+/*
+ * Report that a block starting at `position` is about to be evaluated by the call most recently
+ * entered, blocking until the thread is resumed if it should stop there. Blocks without positions
+ * never stop the thread.
+ */
+func (thread *Thread) BeforeBlock(position *errors.Position) {
+	if position == nil {
+		return
+	}
+
+	debugger := thread.debugger
+	debugger.mutex.Lock()
+
+	frame_ := thread.sourceFrame()
+
+	if frame_ == nil {
+		debugger.mutex.Unlock()
+
+		return
+	}
+
+	filename := debugger.absolutePath(position.Filename)
+	line, column := position.StartLineAndColumn()
+	isNewLine := filename != frame_.filename || line != frame_.line
+
+	frame_.filename = filename
+	frame_.line = line
+	frame_.column = column
+
+	var reason StopReason
+
+	switch {
+	case thread.stopOnEntry:
+		reason = EntryStop
+
+	case thread.pauseRequested:
+		reason = PauseStop
+
+	case isNewLine && debugger.breakpoints[filename][line]:
+		reason = BreakpointStop
+
+	case thread.isStepComplete(filename, line):
+		reason = StepStop
+
+	default:
+		debugger.mutex.Unlock()
+
+		return
+	}
+
+	thread.isStopped = true
+	thread.stopOnEntry = false
+	thread.pauseRequested = false
+	thread.step = noStep
+	debugger.mutex.Unlock()
+
+	debugger.Events <- &Event{
+		Type:   StoppedEvent,
+		Thread: thread,
+		Reason: reason,
+	}
+
+	<-thread.resume
+}
+
+/*
+ * Stepping in completes on reaching another line or call, stepping over on reaching another line
+ * without making a call or on returning, and stepping out on returning.
+ */
+func (thread *Thread) isStepComplete(filename string, line int) bool {
+	depth := thread.depth()
+	isOtherLine := filename != thread.stepFilename || line != thread.stepLine
+
+	switch thread.step {
+	case stepIn:
+		return depth != thread.stepDepth || isOtherLine
+
+	case stepOver:
+		return depth < thread.stepDepth || depth == thread.stepDepth && isOtherLine
+
+	case stepOut:
+		return depth < thread.stepDepth
+	}
+
+	return false
+}
+
+// The innermost frame that wasn't generated while desugaring, or nil if there isn't one
+func (thread *Thread) sourceFrame() *frame {
+	for i := len(thread.frames) - 1; i >= 0; i-- {
+		if !thread.frames[i].isGenerated {
+			return thread.frames[i]
+		}
+	}
+
+	return nil
+}
+
+// The number of frames that weren't generated while desugaring
+func (thread *Thread) depth() int {
+	result := 0
+
+	for _, frame_ := range thread.frames {
+		if !frame_.isGenerated {
+			result++
+		}
+	}
+
+	return result
+}
+
+func (thread *Thread) IsStopped() bool {
+	thread.debugger.mutex.Lock()
+	defer thread.debugger.mutex.Unlock()
+
+	return thread.isStopped
+}
+
+/*
+ * The thread's frames, innermost first. Frames should only be inspected while the thread is
+ * stopped, since their values are otherwise being evaluated.
+ */
+func (thread *Thread) StackTrace() []*Frame {
+	thread.debugger.mutex.Lock()
+	defer thread.debugger.mutex.Unlock()
+
+	result := []*Frame{}
+	var innermostScope Scope
+
+	for i := len(thread.frames) - 1; i >= 0; i-- {
+		frame_ := thread.frames[i]
+
+		if innermostScope == nil {
+			innermostScope = frame_.scope
+		}
+
+		if !frame_.isGenerated {
+			result = append(result, &Frame{
+				Function: frame_.function,
+				Filename: frame_.filename,
+				Line:     frame_.line,
+				Column:   frame_.column,
+				scope:    innermostScope,
+			})
+
+			innermostScope = nil
+		}
+	}
+
+	return result
+}
+
+// Resume the thread, returning whether it was stopped.
+func (thread *Thread) Continue() bool {
+	return thread.resumeWithStep(noStep)
+}
+
+func (thread *Thread) StepIn() bool {
+	return thread.resumeWithStep(stepIn)
+}
+
+func (thread *Thread) StepOver() bool {
+	return thread.resumeWithStep(stepOver)
+}
+
+func (thread *Thread) StepOut() bool {
+	return thread.resumeWithStep(stepOut)
+}
+
+func (thread *Thread) resumeWithStep(step stepType) bool {
+	thread.debugger.mutex.Lock()
+
+	if !thread.isStopped {
+		thread.debugger.mutex.Unlock()
+
+		return false
+	}
+
+	thread.isStopped = false
+	thread.step = step
+	thread.stepDepth = thread.depth()
+
+	if frame_ := thread.sourceFrame(); frame_ != nil {
+		thread.stepFilename = frame_.filename
+		thread.stepLine = frame_.line
+	}
+
+	thread.debugger.mutex.Unlock()
+	thread.resume <- struct{}{}
+
+	return true
+}
+
+// Stop the thread before the next block it evaluates, unless it's already stopped.
+func (thread *Thread) Pause() {
+	thread.debugger.mutex.Lock()
+	defer thread.debugger.mutex.Unlock()
+
+	if !thread.isStopped {
+		thread.pauseRequested = true
+	}
+}
@@ -0,0 +1,487 @@
+package debugger_frontend
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"project_umbrella/interpreter/debugger"
+	"project_umbrella/interpreter/standard_library"
+)
+
+type adapter struct {
+	writer      io.Writer
+	writerMutex sync.Mutex
+	nextSeq     int
+
+	debugger *debugger.Debugger
+
+	// The program launched, which is started once the client has finished configuring breakpoints
+	program        string
+	isLaunched     bool
+	isConfigured   bool
+	programStarted bool
+
+	/*
+	 * The frames and values shown to the client, referred to by their indices plus one. Handles are
+	 * only valid while threads are stopped, so they're discarded whenever a thread is resumed.
+	 */
+	frames         []*debugger.Frame
+	functionValues []*debugger.FunctionValues
+}
+
+type requestHandler func(adapter_ *adapter, arguments json.RawMessage) (any, error)
+
+var requestHandlers = map[string]requestHandler{
+	"initialize":        handleRequest((*adapter).initialize),
+	"launch":            handleRequest((*adapter).launch),
+	"setBreakpoints":    handleRequest((*adapter).setBreakpoints),
+	"configurationDone": handleRequest((*adapter).configurationDone),
+	"threads":           handleRequest((*adapter).threads),
+	"stackTrace":        handleRequest((*adapter).stackTrace),
+	"scopes":            handleRequest((*adapter).scopes),
+	"variables":         handleRequest((*adapter).variables),
+	"evaluate":          handleRequest((*adapter).evaluate),
+	"continue":          resumingRequest((*debugger.Thread).Continue),
+	"next":              resumingRequest((*debugger.Thread).StepOver),
+	"stepIn":            resumingRequest((*debugger.Thread).StepIn),
+	"stepOut":           resumingRequest((*debugger.Thread).StepOut),
+	"pause":             handleRequest((*adapter).pause),
+}
+
+func handleRequest[Arguments any](
+	handler func(*adapter, *Arguments) (any, error),
+) requestHandler {
+	return func(adapter_ *adapter, rawArguments json.RawMessage) (any, error) {
+		arguments := new(Arguments)
+
+		if len(rawArguments) > 0 {
+			if err := json.Unmarshal(rawArguments, arguments); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(adapter_, arguments)
+	}
+}
+
+/*
+ * Serve a Debug Adapter Protocol client over `reader` and `writer` until it disconnects. The
+ * program's standard output is redirected into output events, since `writer` is usually standard
+ * output itself.
+ */
+func ServeDebugAdapter(reader io.Reader, writer io.Writer) {
+	adapter_ := &adapter{
+		writer:         writer,
+		nextSeq:        1,
+		debugger:       debugger.NewDebugger(),
+		frames:         []*debugger.Frame{},
+		functionValues: []*debugger.FunctionValues{},
+	}
+
+	go adapter_.forwardDebuggerEvents()
+
+	bufferedReader := bufio.NewReader(reader)
+
+	for {
+		message, err := readMessage(bufferedReader)
+
+		if err != nil {
+			return
+		}
+
+		request_ := &request{}
+
+		if err := json.Unmarshal(message, request_); err != nil {
+			continue
+		}
+
+		if request_.Command == "disconnect" {
+			adapter_.respond(request_, nil, nil)
+
+			return
+		}
+
+		handler, ok := requestHandlers[request_.Command]
+
+		if !ok {
+			adapter_.respond(request_, nil, fmt.Errorf("unsupported command: %s", request_.Command))
+
+			continue
+		}
+
+		body, err := handler(adapter_, request_.Arguments)
+
+		adapter_.respond(request_, body, err)
+
+		/*
+		 * The client may only configure breakpoints once it has been told the adapter's
+		 * capabilities, and events about the program should follow the response to the request
+		 * starting it.
+		 */
+		if request_.Command == "initialize" && err == nil {
+			adapter_.sendEvent("initialized", nil)
+		}
+
+		adapter_.startProgramIfReady()
+	}
+}
+
+// Failures to write to the client are ignored; the adapter exits once it fails to read from it too.
+func (adapter_ *adapter) send(message func(seq int) any) {
+	adapter_.writerMutex.Lock()
+	defer adapter_.writerMutex.Unlock()
+
+	_ = writeMessage(adapter_.writer, message(adapter_.nextSeq))
+	adapter_.nextSeq++
+}
+
+func (adapter_ *adapter) respond(request_ *request, body any, err error) {
+	adapter_.send(func(seq int) any {
+		result := &response{
+			Seq:        seq,
+			Type:       "response",
+			RequestSeq: request_.Seq,
+			Success:    err == nil,
+			Command:    request_.Command,
+			Body:       body,
+		}
+
+		if err != nil {
+			result.Message = err.Error()
+		}
+
+		return result
+	})
+}
+
+func (adapter_ *adapter) sendEvent(name string, body any) {
+	adapter_.send(func(seq int) any {
+		return &event{
+			Seq:   seq,
+			Type:  "event",
+			Event: name,
+			Body:  body,
+		}
+	})
+}
+
+func (adapter_ *adapter) forwardDebuggerEvents() {
+	for event_ := range adapter_.debugger.Events {
+		switch event_.Type {
+		case debugger.ThreadStartedEvent:
+			adapter_.sendEvent("thread", &threadEventBody{
+				Reason:   "started",
+				ThreadID: event_.Thread.ID,
+			})
+
+		case debugger.ThreadExitedEvent:
+			adapter_.sendEvent("thread", &threadEventBody{
+				Reason:   "exited",
+				ThreadID: event_.Thread.ID,
+			})
+
+		case debugger.StoppedEvent:
+			adapter_.sendEvent("stopped", &stoppedEventBody{
+				Reason:            string(event_.Reason),
+				ThreadID:          event_.Thread.ID,
+				AllThreadsStopped: false,
+			})
+		}
+	}
+}
+
+func (*adapter) initialize(*struct{}) (any, error) {
+	return &capabilities{
+		SupportsConfigurationDoneRequest: true,
+		SupportsEvaluateForHovers:        true,
+	}, nil
+}
+
+func (adapter_ *adapter) launch(arguments *launchArguments) (any, error) {
+	if _, err := standard_library.ReadFile(arguments.Program); err != nil {
+		return nil, fmt.Errorf("the program %q couldn't be opened", arguments.Program)
+	}
+
+	adapter_.program = arguments.Program
+	adapter_.isLaunched = true
+
+	if arguments.StopOnEntry {
+		adapter_.debugger.StopOnEntry()
+	}
+
+	return nil, nil
+}
+
+func (adapter_ *adapter) configurationDone(*struct{}) (any, error) {
+	adapter_.isConfigured = true
+
+	return nil, nil
+}
+
+/*
+ * Start the program once it has been launched and configured (in either order), reporting its
+ * output and how it exited with events.
+ */
+func (adapter_ *adapter) startProgramIfReady() {
+	if !adapter_.isLaunched || !adapter_.isConfigured || adapter_.programStarted {
+		return
+	}
+
+	adapter_.programStarted = true
+
+	outputReader, outputWriter, err := os.Pipe()
+
+	if err != nil {
+		adapter_.sendOutput("stderr", err.Error()+"\n")
+		adapter_.sendEvent("terminated", nil)
+
+		return
+	}
+
+	os.Stdout = outputWriter
+	outputForwarded := make(chan struct{})
+
+	go func() {
+		buffer := make([]byte, 4096)
+
+		for {
+			n, err := outputReader.Read(buffer)
+
+			if n > 0 {
+				adapter_.sendOutput("stdout", string(buffer[:n]))
+			}
+
+			if err != nil {
+				close(outputForwarded)
+
+				return
+			}
+		}
+	}()
+
+	exited := launch(adapter_.program, adapter_.debugger)
+
+	go func() {
+		result := <-exited
+		exitCode := 0
+
+		outputWriter.Close()
+		<-outputForwarded
+
+		if result != nil {
+			adapter_.sendOutput("stderr", result.String())
+			exitCode = 1
+		}
+
+		adapter_.sendEvent("exited", &exitedEventBody{
+			ExitCode: exitCode,
+		})
+
+		adapter_.sendEvent("terminated", nil)
+	}()
+}
+
+func (adapter_ *adapter) sendOutput(category string, output string) {
+	adapter_.sendEvent("output", &outputEventBody{
+		Category: category,
+		Output:   output,
+	})
+}
+
+func (adapter_ *adapter) setBreakpoints(arguments *setBreakpointsArguments) (any, error) {
+	lines := []int{}
+	result := &setBreakpointsResponseBody{
+		Breakpoints: []*breakpoint{},
+	}
+
+	for _, sourceBreakpoint_ := range arguments.Breakpoints {
+		lines = append(lines, sourceBreakpoint_.Line)
+		result.Breakpoints = append(result.Breakpoints, &breakpoint{
+			Verified: true,
+			Line:     sourceBreakpoint_.Line,
+		})
+	}
+
+	adapter_.debugger.SetBreakpoints(arguments.Source.Path, lines)
+
+	return result, nil
+}
+
+func (adapter_ *adapter) threads(*struct{}) (any, error) {
+	result := &threadsResponseBody{
+		Threads: []*thread{},
+	}
+
+	for _, thread_ := range adapter_.debugger.Threads() {
+		result.Threads = append(result.Threads, &thread{
+			ID:   thread_.ID,
+			Name: thread_.Name,
+		})
+	}
+
+	return result, nil
+}
+
+func (adapter_ *adapter) stoppedThread(id int) (*debugger.Thread, error) {
+	result, ok := adapter_.debugger.Thread(id)
+
+	if !ok || !result.IsStopped() {
+		return nil, fmt.Errorf("thread %d isn't stopped", id)
+	}
+
+	return result, nil
+}
+
+func (adapter_ *adapter) stackTrace(arguments *stackTraceArguments) (any, error) {
+	thread_, err := adapter_.stoppedThread(arguments.ThreadID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	frames := thread_.StackTrace()
+	result := &stackTraceResponseBody{
+		StackFrames: []*stackFrame{},
+		TotalFrames: len(frames),
+	}
+
+	end := len(frames)
+
+	if arguments.Levels > 0 {
+		end = min(end, arguments.StartFrame+arguments.Levels)
+	}
+
+	for i := arguments.StartFrame; i < end; i++ {
+		adapter_.frames = append(adapter_.frames, frames[i])
+		stackFrame_ := &stackFrame{
+			ID:     len(adapter_.frames),
+			Name:   frames[i].Function,
+			Source: nil,
+			Line:   frames[i].Line,
+			Column: frames[i].Column,
+		}
+
+		if frames[i].Filename != "" {
+			stackFrame_.Source = &source{
+				Name: filepath.Base(frames[i].Filename),
+				Path: frames[i].Filename,
+			}
+
+			if standard_library.IsEmbeddedPath(frames[i].Filename) {
+				stackFrame_.Source.Path = ""
+			}
+		}
+
+		result.StackFrames = append(result.StackFrames, stackFrame_)
+	}
+
+	return result, nil
+}
+
+func (adapter_ *adapter) frame(id int) (*debugger.Frame, error) {
+	if id < 1 || id > len(adapter_.frames) {
+		return nil, fmt.Errorf("unknown frame: %d", id)
+	}
+
+	return adapter_.frames[id-1], nil
+}
+
+// Each function whose scope the frame's function can refer to values in is shown as a scope.
+func (adapter_ *adapter) scopes(arguments *scopesArguments) (any, error) {
+	frame, err := adapter_.frame(arguments.FrameID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &scopesResponseBody{
+		Scopes: []*scope{},
+	}
+
+	for _, functionValues := range frame.Values() {
+		adapter_.functionValues = append(adapter_.functionValues, functionValues)
+		result.Scopes = append(result.Scopes, &scope{
+			Name:               functionValues.Function,
+			VariablesReference: len(adapter_.functionValues),
+			Expensive:          false,
+		})
+	}
+
+	return result, nil
+}
+
+func (adapter_ *adapter) variables(arguments *variablesArguments) (any, error) {
+	if arguments.VariablesReference < 1 ||
+		arguments.VariablesReference > len(adapter_.functionValues) {
+		return nil, fmt.Errorf("unknown variables reference: %d", arguments.VariablesReference)
+	}
+
+	result := &variablesResponseBody{
+		Variables: []*variable{},
+	}
+
+	for _, value := range adapter_.functionValues[arguments.VariablesReference-1].Values {
+		result.Variables = append(result.Variables, &variable{
+			Name:               value.Name,
+			Value:              value.Value,
+			VariablesReference: 0,
+		})
+	}
+
+	return result, nil
+}
+
+// Only names can be evaluated, by looking up the values they refer to.
+func (adapter_ *adapter) evaluate(arguments *evaluateArguments) (any, error) {
+	frame, err := adapter_.frame(arguments.FrameID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := frame.Lookup(arguments.Expression)
+
+	if !ok {
+		return nil, fmt.Errorf("no value named %s in %s", arguments.Expression, frame.Function)
+	}
+
+	return &evaluateResponseBody{
+		Result:             value.Value,
+		VariablesReference: 0,
+	}, nil
+}
+
+func resumingRequest(resume func(thread *debugger.Thread) bool) requestHandler {
+	return handleRequest(func(adapter_ *adapter, arguments *threadArguments) (any, error) {
+		thread_, err := adapter_.stoppedThread(arguments.ThreadID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		adapter_.frames = []*debugger.Frame{}
+		adapter_.functionValues = []*debugger.FunctionValues{}
+		resume(thread_)
+
+		return &continueResponseBody{
+			AllThreadsContinued: false,
+		}, nil
+	})
+}
+
+func (adapter_ *adapter) pause(arguments *threadArguments) (any, error) {
+	thread_, ok := adapter_.debugger.Thread(arguments.ThreadID)
+
+	if !ok {
+		return nil, fmt.Errorf("unknown thread: %d", arguments.ThreadID)
+	}
+
+	thread_.Pause()
+
+	return nil, nil
+}
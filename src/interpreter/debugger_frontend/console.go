@@ -0,0 +1,401 @@
+package debugger_frontend
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"project_umbrella/interpreter/debugger"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/standard_library"
+)
+
+type console struct {
+	path     string
+	writer   io.Writer
+	debugger *debugger.Debugger
+
+	// The breakpoints set, keyed by their files' paths as given
+	breakpoints map[string][]int
+
+	// Receives the program's result once it has finished, or nil if it hasn't been started
+	exited     <-chan *errors.Error
+	isFinished bool
+	result     *errors.Error
+
+	// The thread last stopped, if any, and the frame selected within it (0 being the innermost)
+	thread *debugger.Thread
+	frame  int
+}
+
+type consoleCommand func(console_ *console, argument string)
+
+var consoleCommands = map[string]consoleCommand{
+	"break":     (*console).setBreakpoint,
+	"clear":     (*console).clearBreakpoint,
+	"run":       (*console).run,
+	"continue":  resumingCommand((*debugger.Thread).Continue),
+	"step":      resumingCommand((*debugger.Thread).StepIn),
+	"next":      resumingCommand((*debugger.Thread).StepOver),
+	"finish":    resumingCommand((*debugger.Thread).StepOut),
+	"print":     (*console).print,
+	"locals":    (*console).printLocals,
+	"backtrace": (*console).printBacktrace,
+	"frame":     (*console).selectFrame,
+	"help":      (*console).printHelp,
+}
+
+var consoleCommandAliases = map[string]string{
+	"b":  "break",
+	"r":  "run",
+	"c":  "continue",
+	"s":  "step",
+	"n":  "next",
+	"f":  "finish",
+	"p":  "print",
+	"bt": "backtrace",
+}
+
+const consoleHelp = `Commands:
+  break [file:]line   Pause before evaluating the line (in the program's file by default)
+  clear [file:]line   Remove a breakpoint
+  run                 Start the program
+  continue            Resume the program until the next breakpoint
+  step                Resume until another line is reached, stepping into calls
+  next                Resume until another line is reached, stepping over calls
+  finish              Resume until the current function returns
+  print <name>        Show the value a name refers to in the selected frame
+  locals              Show the values named in the selected frame's function
+  backtrace           Show the functions being called
+  frame <number>      Select a frame shown by backtrace
+  quit                Exit, ending the program
+`
+
+/*
+ * Debug the file at `path` with commands read from `reader`, until the program finishes or the user
+ * quits. Returns the error the program failed with, if any.
+ */
+func RunConsole(path string, reader io.Reader, writer io.Writer) *errors.Error {
+	console_ := &console{
+		path:        path,
+		writer:      writer,
+		debugger:    debugger.NewDebugger(),
+		breakpoints: map[string][]int{},
+		exited:      nil,
+		isFinished:  false,
+		result:      nil,
+		thread:      nil,
+		frame:       0,
+	}
+
+	scanner := bufio.NewScanner(reader)
+
+	for {
+		fmt.Fprint(writer, "(krait) ")
+
+		if !scanner.Scan() {
+			return nil
+		}
+
+		name, argument, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		argument = strings.TrimSpace(argument)
+
+		if alias, ok := consoleCommandAliases[name]; ok {
+			name = alias
+		}
+
+		switch name {
+		case "":
+			continue
+
+		case "quit", "q":
+			return nil
+		}
+
+		command, ok := consoleCommands[name]
+
+		if !ok {
+			fmt.Fprintf(writer, "Unknown command: %s (see help)\n", name)
+
+			continue
+		}
+
+		command(console_, argument)
+
+		if console_.isFinished {
+			return console_.result
+		}
+	}
+}
+
+func (console_ *console) setBreakpoint(argument string) {
+	path, line, ok := console_.parseLocation(argument)
+
+	if !ok {
+		return
+	}
+
+	if !containsLine(console_.breakpoints[path], line) {
+		console_.breakpoints[path] = append(console_.breakpoints[path], line)
+	}
+
+	console_.debugger.SetBreakpoints(path, console_.breakpoints[path])
+	fmt.Fprintf(console_.writer, "Breakpoint set at %s:%d\n", path, line)
+}
+
+func (console_ *console) clearBreakpoint(argument string) {
+	path, line, ok := console_.parseLocation(argument)
+
+	if !ok {
+		return
+	}
+
+	if !containsLine(console_.breakpoints[path], line) {
+		fmt.Fprintf(console_.writer, "No breakpoint at %s:%d\n", path, line)
+
+		return
+	}
+
+	remaining := []int{}
+
+	for _, breakpoint := range console_.breakpoints[path] {
+		if breakpoint != line {
+			remaining = append(remaining, breakpoint)
+		}
+	}
+
+	console_.breakpoints[path] = remaining
+	console_.debugger.SetBreakpoints(path, remaining)
+	fmt.Fprintf(console_.writer, "Breakpoint cleared at %s:%d\n", path, line)
+}
+
+func containsLine(lines []int, line int) bool {
+	for _, line_ := range lines {
+		if line_ == line {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Locations are given as "[file:]line", in the program's file by default.
+func (console_ *console) parseLocation(argument string) (string, int, bool) {
+	path := console_.path
+	lineString := argument
+
+	if separator := strings.LastIndexByte(argument, ':'); separator != -1 {
+		path = argument[:separator]
+		lineString = argument[separator+1:]
+	}
+
+	line, err := strconv.Atoi(lineString)
+
+	if err != nil || line < 1 {
+		fmt.Fprintf(console_.writer, "Invalid location: %q (expected [file:]line)\n", argument)
+
+		return "", 0, false
+	}
+
+	return path, line, true
+}
+
+func (console_ *console) run(string) {
+	if console_.exited != nil {
+		fmt.Fprintln(console_.writer, "The program is already running")
+
+		return
+	}
+
+	console_.exited = launch(console_.path, console_.debugger)
+	console_.waitForStop()
+}
+
+func resumingCommand(resume func(thread *debugger.Thread) bool) consoleCommand {
+	return func(console_ *console, _ string) {
+		if console_.thread == nil {
+			fmt.Fprintln(console_.writer, "The program isn't running")
+
+			return
+		}
+
+		resume(console_.thread)
+		console_.waitForStop()
+	}
+}
+
+/*
+ * Wait for any thread to stop, selecting its innermost frame, or for the program to finish, in
+ * which case no thread is selected.
+ */
+func (console_ *console) waitForStop() {
+	console_.thread = nil
+	console_.frame = 0
+
+	for {
+		select {
+		case event := <-console_.debugger.Events:
+			if event.Type == debugger.StoppedEvent {
+				console_.thread = event.Thread
+				console_.printStop(event.Reason)
+
+				return
+			}
+
+		case result := <-console_.exited:
+			console_.isFinished = true
+			console_.result = result
+
+			if result == nil {
+				fmt.Fprintln(console_.writer, "Program exited")
+			} else {
+				fmt.Fprintln(console_.writer, "Program failed")
+			}
+
+			return
+		}
+	}
+}
+
+func (console_ *console) printStop(reason debugger.StopReason) {
+	frame, ok := console_.selectedFrame()
+
+	if !ok {
+		return
+	}
+
+	fmt.Fprintf(
+		console_.writer,
+		"Stopped at %s:%d in %s (%s)\n",
+		frame.Filename,
+		frame.Line,
+		frame.Function,
+		reason,
+	)
+
+	console_.printSourceLine(frame)
+}
+
+func (console_ *console) printSourceLine(frame *debugger.Frame) {
+	if source, err := standard_library.ReadFile(frame.Filename); err == nil {
+		lines := strings.Split(string(source), "\n")
+
+		if frame.Line >= 1 && frame.Line <= len(lines) {
+			fmt.Fprintf(console_.writer, "%d\t%s\n", frame.Line, lines[frame.Line-1])
+		}
+	}
+}
+
+func (console_ *console) selectedFrame() (*debugger.Frame, bool) {
+	if console_.thread == nil {
+		fmt.Fprintln(console_.writer, "The program isn't running")
+
+		return nil, false
+	}
+
+	frames := console_.thread.StackTrace()
+
+	if console_.frame >= len(frames) {
+		return nil, false
+	}
+
+	return frames[console_.frame], true
+}
+
+func (console_ *console) print(name string) {
+	frame, ok := console_.selectedFrame()
+
+	if !ok {
+		return
+	}
+
+	if value, ok := frame.Lookup(name); ok {
+		fmt.Fprintf(console_.writer, "%s = %s\n", value.Name, value.Value)
+	} else {
+		fmt.Fprintf(console_.writer, "No value named %s in %s\n", name, frame.Function)
+	}
+}
+
+/*
+ * Show the values named in the scopes belonging to the selected frame's function, including those
+ * of the functions generated within it (e.g. the branches of `if` expressions).
+ */
+func (console_ *console) printLocals(string) {
+	frame, ok := console_.selectedFrame()
+
+	if !ok {
+		return
+	}
+
+	functionValues := frame.Values()
+
+	if len(functionValues) == 0 {
+		return
+	}
+
+	for _, value := range functionValues[0].Values {
+		fmt.Fprintf(console_.writer, "%s = %s\n", value.Name, value.Value)
+	}
+}
+
+func (console_ *console) printBacktrace(string) {
+	if console_.thread == nil {
+		fmt.Fprintln(console_.writer, "The program isn't running")
+
+		return
+	}
+
+	for i, frame := range console_.thread.StackTrace() {
+		marker := " "
+
+		if i == console_.frame {
+			marker = "*"
+		}
+
+		fmt.Fprintf(
+			console_.writer,
+			"%s#%d %s at %s:%d\n",
+			marker,
+			i,
+			frame.Function,
+			frame.Filename,
+			frame.Line,
+		)
+	}
+}
+
+func (console_ *console) selectFrame(argument string) {
+	if console_.thread == nil {
+		fmt.Fprintln(console_.writer, "The program isn't running")
+
+		return
+	}
+
+	frame, err := strconv.Atoi(argument)
+
+	if err != nil || frame < 0 || frame >= len(console_.thread.StackTrace()) {
+		fmt.Fprintf(console_.writer, "Invalid frame: %q (see backtrace)\n", argument)
+
+		return
+	}
+
+	console_.frame = frame
+	selected, _ := console_.selectedFrame()
+
+	fmt.Fprintf(
+		console_.writer,
+		"#%d %s at %s:%d\n",
+		frame,
+		selected.Function,
+		selected.Filename,
+		selected.Line,
+	)
+
+	console_.printSourceLine(selected)
+}
+
+func (console_ *console) printHelp(string) {
+	fmt.Fprint(console_.writer, consoleHelp)
+}
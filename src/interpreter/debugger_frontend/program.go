@@ -0,0 +1,37 @@
+/*
+ * The debugger's frontends: an interactive console (`interpreter debug <file>`) and a Debug Adapter
+ * Protocol server (`interpreter debug --dap`, see https://microsoft.github.io/debug-adapter-protocol)
+ * for editors to debug programs with.
+ */
+package debugger_frontend
+
+import (
+	"project_umbrella/interpreter/debugger"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/loader/module_loader"
+	"project_umbrella/interpreter/runtime"
+)
+
+/*
+ * Run the file at `path` on a goroutine of its own, observed by `debugger_`. The returned channel
+ * receives the error the program failed with (or nil) once it has finished.
+ */
+func launch(path string, debugger_ *debugger.Debugger) <-chan *errors.Error {
+	result := make(chan *errors.Error, 1)
+
+	go func() {
+		loader := module_loader.NewModuleLoader()
+		loader.Instruments = runtime.Instruments{
+			Profiler: nil,
+			Tracer:   nil,
+			Debugger: debugger_,
+			Coverage: nil,
+		}
+
+		result <- errors.Catch(func() {
+			loader.LoadFile(path)
+		})
+	}()
+
+	return result
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/errors/project_errors"
+	"project_umbrella/interpreter/project"
+)
+
+/*
+ * `interpreter deps lock` resolves the dependencies of the project containing the working
+ * directory into its lockfile, `interpreter deps vendor` copies the locked dependencies into the
+ * project's vendor directory, and `interpreter deps install` does both.
+ */
+func runDepsCommand(arguments []string) {
+	if len(arguments) < 1 {
+		errors.RaiseError(entry_errors.DepsCommandNotSpecified)
+	}
+
+	workingDirectory, err := os.Getwd()
+
+	if err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	project_, ok := project.FindProject(workingDirectory)
+
+	if !ok {
+		errors.RaiseError(project_errors.ManifestNotFound)
+	}
+
+	switch arguments[0] {
+	case "lock":
+		lockDependencies(project_)
+
+	case "vendor":
+		vendorDependencies(project_, project_.ReadLockfile())
+
+	case "install":
+		vendorDependencies(project_, lockDependencies(project_))
+
+	default:
+		errors.RaiseError(entry_errors.UnknownDepsCommand(arguments[0]))
+	}
+}
+
+func lockDependencies(project_ *project.Project) *project.Lockfile {
+	lockfile := project_.Resolve()
+
+	project_.WriteLockfile(lockfile)
+
+	var packageWord string
+
+	if len(lockfile.Packages) == 1 {
+		packageWord = "package"
+	} else {
+		packageWord = "packages"
+	}
+
+	fmt.Printf(
+		"Locked %d %s in %s\n",
+		len(lockfile.Packages),
+		packageWord,
+		project.LockfileFileName,
+	)
+
+	return lockfile
+}
+
+func vendorDependencies(project_ *project.Project, lockfile *project.Lockfile) {
+	project_.Vendor(lockfile, func(locked *project.LockedPackage) {
+		fmt.Printf("Vendored %s %s\n", locked.Name, locked.Version)
+	})
+}
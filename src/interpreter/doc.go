@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"project_umbrella/interpreter/doc_generator"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/loader/module_loader"
+	"project_umbrella/interpreter/standard_library"
+)
+
+const defaultDocumentationDirectory = "documentation"
+
+/*
+ * `interpreter doc [--html] [--output <directory>] <path or module>...` documents the given Krait
+ * files, those in the given directories, and the given modules (e.g. `iterator`), writing a page per
+ * module to the output directory.
+ */
+func runDocCommand(arguments []string) {
+	format := doc_generator.MarkdownFormat
+	outputDirectory := defaultDocumentationDirectory
+	paths := []string{}
+	moduleNames := []string{}
+
+	for i := 0; i < len(arguments); i++ {
+		switch arguments[i] {
+		case "--html":
+			format = doc_generator.HTMLFormat
+
+		case "--output":
+			if i+1 == len(arguments) {
+				errors.RaiseError(entry_errors.DocumentationPathsNotSpecified)
+			}
+
+			outputDirectory = arguments[i+1]
+			i++
+
+		default:
+			if _, err := os.Stat(arguments[i]); err == nil {
+				paths = append(paths, arguments[i])
+			} else {
+				moduleNames = append(moduleNames, arguments[i])
+			}
+		}
+	}
+
+	if len(paths) == 0 && len(moduleNames) == 0 {
+		errors.RaiseError(entry_errors.DocumentationPathsNotSpecified)
+	}
+
+	sources := map[string]string{}
+
+	readSource := func(path string) {
+		source, err := standard_library.ReadFile(path)
+
+		if err != nil {
+			errors.RaiseError(entry_errors.FileNotOpened(path))
+		}
+
+		sources[path] = string(source)
+	}
+
+	for _, path := range kraitFilesInPaths(paths) {
+		readSource(path)
+	}
+
+	// Modules are found as they would be if imported by a file in the working directory.
+	loader := module_loader.NewModuleLoader()
+
+	for _, moduleName := range moduleNames {
+		path, ok := loader.ModulePath(moduleName, ".")
+
+		if !ok {
+			errors.RaiseError(entry_errors.FileNotOpened(moduleName))
+		}
+
+		readSource(path)
+	}
+
+	if err := os.MkdirAll(outputDirectory, 0o755); err != nil {
+		errors.RaiseError(entry_errors.FileNotWritten(outputDirectory))
+	}
+
+	for name, content := range doc_generator.Generate(sources, format) {
+		path := filepath.Join(outputDirectory, name)
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			errors.RaiseError(entry_errors.FileNotWritten(path))
+		}
+	}
+}
@@ -0,0 +1,466 @@
+/*
+ * The doc generator describes the public declarations of Krait modules (their structs,
+ * traits, functions and values, along with the fields and methods of structs and traits) with their
+ * doc comments, as a page per module in Markdown or HTML.
+ *
+ * References to declarations in doc comments, written as code (e.g. `Range.length` or
+ * `iterator.Iterator`), are linked to the declarations' documentation. Like names in code, they're
+ * resolved within the module, or through its imports to the other modules being documented.
+ */
+package doc_generator
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/parser"
+	"project_umbrella/interpreter/parser/parser_types"
+)
+
+type Format int
+
+const (
+	MarkdownFormat Format = iota
+	HTMLFormat
+)
+
+func (format Format) extension() string {
+	if format == HTMLFormat {
+		return ".html"
+	}
+
+	return ".md"
+}
+
+const indexPageName = "index"
+
+var referencePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+/*
+ * A documented declaration. Structs and traits have fields and methods, which are themselves
+ * documented declarations.
+ */
+type item struct {
+	name      string
+	anchor    string
+	signature string
+	doc       string
+
+	// The parameters of structs' constructors, and the fields traits require
+	parameters []string
+
+	// The traits structs implement, as written (e.g. `iterator.Iterator`)
+	traits []string
+
+	fields  []*item
+	methods []*item
+}
+
+type importedValue struct {
+	moduleName string
+	name       string
+}
+
+type module struct {
+	name      string
+	structs   []*item
+	traits    []*item
+	functions []*item
+	values    []*item
+
+	// The anchor of every item in the module, including fields and methods
+	anchors map[string]bool
+
+	// Names bound to imported modules (`iterator = import("iterator")`)
+	moduleImports map[string]string
+
+	// Names bound to values imported from modules (`from option import Some`)
+	valueImports map[string]*importedValue
+}
+
+/*
+ * Document the modules in `sources` (keyed by their paths and named after their files), returning
+ * the content of each module's page and of an index of the modules, keyed by their file names.
+ */
+func Generate(sources map[string]string, format Format) map[string]string {
+	paths := make([]string, 0, len(sources))
+
+	for path := range sources {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	modules := map[string]*module{}
+	moduleNames := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		module_ := newModule(path, sources[path])
+
+		if _, ok := modules[module_.name]; ok {
+			errors.RaiseError(entry_errors.DocumentedModuleNameConflict(module_.name))
+		}
+
+		modules[module_.name] = module_
+		moduleNames = append(moduleNames, module_.name)
+	}
+
+	sort.Strings(moduleNames)
+
+	result := map[string]string{
+		indexPageName + format.extension(): renderIndex(format, moduleNames),
+	}
+
+	for _, moduleName := range moduleNames {
+		module_ := modules[moduleName]
+		generator := &pageGenerator{
+			format:   format,
+			module:   module_,
+			modules:  modules,
+			renderer: newRenderer(format),
+		}
+
+		result[moduleName+format.extension()] = generator.generate()
+	}
+
+	return result
+}
+
+func newModule(path string, source string) *module {
+	result := &module{
+		name:          strings.TrimSuffix(filepath.Base(path), ".krait"),
+		structs:       []*item{},
+		traits:        []*item{},
+		functions:     []*item{},
+		values:        []*item{},
+		anchors:       map[string]bool{},
+		moduleImports: map[string]string{},
+		valueImports:  map[string]*importedValue{},
+	}
+
+	collector_ := &collector{
+		path:        path,
+		source:      source,
+		docComments: parser.NewDocComments(path, source),
+		functions:   map[string]*parser.Function{},
+	}
+
+	expressionList := parser.ParseSource(path, source).AbstractExpressionList()
+
+	for _, expression := range expressionList.Children_ {
+		if function, ok := expression.(*parser.Function); ok && function.Name != nil {
+			collector_.functions[function.Name.Value] = function
+		}
+	}
+
+	for _, expression := range expressionList.Children_ {
+		switch expression := expression.(type) {
+		case *parser.Function:
+			if expression.Name == nil || parser_types.IsPrivateName(expression.Name.Value) {
+				continue
+			}
+
+			if _, ok := expression.StructFieldFactory(); ok {
+				result.structs = append(result.structs, collector_.structItem(expression))
+			} else {
+				result.functions = append(
+					result.functions,
+					collector_.functionItem(expression.Name, expression, ""),
+				)
+			}
+
+		case *parser.Assignment:
+			collector_.collectAssignment(result, expression)
+		}
+	}
+
+	for _, items := range [][]*item{result.structs, result.traits, result.functions, result.values} {
+		for _, item_ := range items {
+			result.anchors[item_.anchor] = true
+
+			for _, member := range append(append([]*item{}, item_.fields...), item_.methods...) {
+				result.anchors[member.anchor] = true
+			}
+		}
+	}
+
+	return result
+}
+
+type collector struct {
+	path        string
+	source      string
+	docComments *parser.DocComments
+
+	// The module's named functions, including private ones, which public names might be bound to
+	functions map[string]*parser.Function
+}
+
+func (collector_ *collector) docComment(name *parser.Identifier) string {
+	if name.Position() == nil {
+		return ""
+	}
+
+	return collector_.docComments.Of(name.Position().Start)
+}
+
+/*
+ * Names bound to functions (e.g. `from_tuple = _from_string_or_tuple`) are documented as functions,
+ * falling back to the functions' doc comments.
+ */
+func (collector_ *collector) collectAssignment(module_ *module, assignment *parser.Assignment) {
+	if assignment.IsFromImport() {
+		call := assignment.Value.(*parser.Call)
+		moduleName, ok := importedModuleName(call.Arguments[0])
+		selects, isTuple := lastExpression(call.Function.(*parser.Function).Body).(*parser.Call)
+
+		if !ok || !isTuple {
+			return
+		}
+
+		for i, element := range assignment.Patterns[0].Elements {
+			name, isName := element.(*parser.Identifier)
+
+			if i < len(selects.Arguments) && isName {
+				if select_, ok := selects.Arguments[i].(*parser.Select); ok {
+					module_.valueImports[name.Value] = &importedValue{
+						moduleName: moduleName,
+						name:       select_.Field.Value,
+					}
+				}
+			}
+		}
+
+		return
+	}
+
+	if moduleName, ok := importedModuleName(assignment.Value); ok {
+		for _, name := range assignment.Names_ {
+			module_.moduleImports[name.Value] = moduleName
+		}
+
+		return
+	}
+
+	if defaultFieldFactory, ok := assignment.TraitDefaultFieldFactory(); ok {
+		if !parser_types.IsPrivateName(assignment.Names_[0].Value) {
+			module_.traits = append(
+				module_.traits,
+				collector_.traitItem(assignment.Names_[0], defaultFieldFactory),
+			)
+		}
+
+		return
+	}
+
+	function, isFunction := assignment.Value.(*parser.Function)
+
+	if identifier, ok := assignment.Value.(*parser.Identifier); ok {
+		function, isFunction = collector_.functions[identifier.Value]
+	}
+
+	for _, name := range assignment.Names() {
+		if parser_types.IsPrivateName(name.Value) {
+			continue
+		}
+
+		if isFunction {
+			module_.functions = append(
+				module_.functions,
+				collector_.functionItem(name, function, ""),
+			)
+		} else {
+			module_.values = append(module_.values, &item{
+				name:      name.Value,
+				anchor:    name.Value,
+				signature: name.Value,
+				doc:       collector_.docComment(name),
+			})
+		}
+	}
+}
+
+func (collector_ *collector) functionItem(
+	name *parser.Identifier,
+	function *parser.Function,
+	anchorPrefix string,
+) *item {
+	doc := collector_.docComment(name)
+
+	if doc == "" && function.Name != nil {
+		doc = collector_.docComment(function.Name)
+	}
+
+	keyword := ""
+
+	if function.Name == name {
+		keyword = "fn "
+	}
+
+	return &item{
+		name:      name.Value,
+		anchor:    anchorPrefix + name.Value,
+		signature: keyword + name.Value + "(" + strings.Join(collector_.parameters(function), ", ") + ")",
+		doc:       doc,
+	}
+}
+
+/*
+ * Destructured parameters are replaced by placeholders, so we print them from the source.
+ */
+func (collector_ *collector) parameters(function *parser.Function) []string {
+	result := make([]string, 0, len(function.Parameters))
+
+	for i, parameter := range function.Parameters {
+		position := parameter.Position()
+		name := parameter.Value
+
+		if position != nil && position.Filename == collector_.path &&
+			collector_.source[position.Start:position.End] != name {
+			name = collector_.source[position.Start:position.End]
+		}
+
+		if function.IsVariadic && i == len(function.Parameters)-1 {
+			name = "..." + name
+		}
+
+		result = append(result, name)
+	}
+
+	return result
+}
+
+func (collector_ *collector) structItem(struct_ *parser.Function) *item {
+	fieldFactory, _ := struct_.StructFieldFactory()
+	call := struct_.Body.Children_[0].(*parser.Call)
+	parameters := collector_.parameters(struct_)
+	traits := []string{}
+
+	if traitTuple, ok := call.Arguments[4].(*parser.Call); ok {
+		for _, trait := range traitTuple.Arguments {
+			if name, ok := referenceName(trait); ok {
+				traits = append(traits, name)
+			}
+		}
+	}
+
+	result := &item{
+		name:   struct_.Name.Value,
+		anchor: struct_.Name.Value,
+		signature: "struct " + struct_.Name.Value + "(" +
+			strings.Join(append([]string{fieldFactory.Parameters[0].Value}, parameters...), ", ") + ")",
+
+		doc:        collector_.docComment(struct_.Name),
+		parameters: parameters,
+		traits:     traits,
+	}
+
+	collector_.collectMembers(result, fieldFactory.Body)
+
+	return result
+}
+
+func (collector_ *collector) traitItem(
+	name *parser.Identifier,
+	defaultFieldFactory *parser.Function,
+) *item {
+	requiredFields := make([]string, 0, len(defaultFieldFactory.Parameters)-1)
+
+	for _, parameter := range defaultFieldFactory.Parameters[1:] {
+		requiredFields = append(requiredFields, parameter.Value)
+	}
+
+	result := &item{
+		name:   name.Value,
+		anchor: name.Value,
+		signature: "trait " + name.Value + "(" +
+			strings.Join(append([]string{defaultFieldFactory.Parameters[0].Value}, requiredFields...), ", ") +
+			")",
+
+		doc:        collector_.docComment(name),
+		parameters: requiredFields,
+	}
+
+	collector_.collectMembers(result, defaultFieldFactory.Body)
+
+	return result
+}
+
+/*
+ * The bodies of structs and traits end with a tuple of their fields, generated by desugaring.
+ */
+func (collector_ *collector) collectMembers(item_ *item, body *parser.ExpressionList) {
+	item_.fields = []*item{}
+	item_.methods = []*item{}
+
+	for _, expression := range body.Children_[:len(body.Children_)-1] {
+		switch expression := expression.(type) {
+		case *parser.Function:
+			if expression.Name != nil && !parser_types.IsPrivateName(expression.Name.Value) {
+				item_.methods = append(
+					item_.methods,
+					collector_.functionItem(expression.Name, expression, item_.name+"."),
+				)
+			}
+
+		case *parser.Assignment:
+			for _, name := range expression.Names() {
+				if !parser_types.IsPrivateName(name.Value) {
+					item_.fields = append(item_.fields, &item{
+						name:      name.Value,
+						anchor:    item_.name + "." + name.Value,
+						signature: name.Value,
+						doc:       collector_.docComment(name),
+					})
+				}
+			}
+		}
+	}
+}
+
+func importedModuleName(expression parser.Expression) (string, bool) {
+	call, ok := expression.(*parser.Call)
+
+	if !ok || len(call.Arguments) != 1 {
+		return "", false
+	}
+
+	if identifier, ok := call.Function.(*parser.Identifier); !ok || identifier.Value != "import" {
+		return "", false
+	}
+
+	moduleName, ok := call.Arguments[0].(*parser.String)
+
+	if !ok {
+		return "", false
+	}
+
+	return moduleName.Value, true
+}
+
+func lastExpression(expressionList *parser.ExpressionList) parser.Expression {
+	if len(expressionList.Children_) == 0 {
+		return nil
+	}
+
+	return expressionList.Children_[len(expressionList.Children_)-1]
+}
+
+// The name an identifier or select (e.g. `iterator.Iterator`) refers to, as written.
+func referenceName(expression parser.Expression) (string, bool) {
+	switch expression := expression.(type) {
+	case *parser.Identifier:
+		return expression.Value, true
+
+	case *parser.Select:
+		if value, ok := referenceName(expression.Value); ok {
+			return value + "." + expression.Field.Value, true
+		}
+	}
+
+	return "", false
+}
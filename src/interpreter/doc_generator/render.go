@@ -0,0 +1,383 @@
+package doc_generator
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// The target of a link to the declaration referred to, if it's documented
+type linkResolver func(reference string) (string, bool)
+
+func noLinks(string) (string, bool) {
+	return "", false
+}
+
+/*
+ * Pages are described as a sequence of blocks, which each format renders in its own markup.
+ * Headings with an anchor can be linked to by it.
+ */
+type renderer interface {
+	heading(level int, anchor string, text string, isCode bool)
+
+	// Doc comment text, in which code (e.g. `Range`) is linked to the declaration it refers to
+	paragraph(text string, resolveLink linkResolver)
+
+	// A labelled, comma-separated list of references (e.g. "Implements: `iterator.Iterator`")
+	references(label string, references []string, resolveLink linkResolver)
+
+	list(references []string, resolveLink linkResolver)
+	page(title string) string
+}
+
+func newRenderer(format Format) renderer {
+	if format == HTMLFormat {
+		return &htmlRenderer{
+			blocks: []string{},
+		}
+	}
+
+	return &markdownRenderer{
+		blocks: []string{},
+	}
+}
+
+type pageGenerator struct {
+	format   Format
+	module   *module
+	modules  map[string]*module
+	renderer renderer
+}
+
+func renderIndex(format Format, moduleNames []string) string {
+	renderer_ := newRenderer(format)
+
+	renderer_.heading(1, "", "Modules", false)
+	renderer_.list(moduleNames, func(moduleName string) (string, bool) {
+		return moduleName + format.extension(), true
+	})
+
+	return renderer_.page("Modules")
+}
+
+func (generator *pageGenerator) generate() string {
+	module_ := generator.module
+
+	generator.renderer.heading(1, "", module_.name, true)
+
+	importedModuleNames := []string{}
+	isImported := map[string]bool{}
+
+	for _, moduleName := range module_.moduleImports {
+		isImported[moduleName] = true
+	}
+
+	for _, imported := range module_.valueImports {
+		isImported[imported.moduleName] = true
+	}
+
+	for moduleName := range isImported {
+		importedModuleNames = append(importedModuleNames, moduleName)
+	}
+
+	sort.Strings(importedModuleNames)
+
+	if len(importedModuleNames) > 0 {
+		generator.renderer.references(
+			"Imports",
+			importedModuleNames,
+			func(moduleName string) (string, bool) {
+				return generator.link(moduleName, "")
+			},
+		)
+	}
+
+	for _, section := range []struct {
+		title string
+		items []*item
+	}{
+		{"Structs", module_.structs},
+		{"Traits", module_.traits},
+		{"Functions", module_.functions},
+		{"Values", module_.values},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+
+		generator.renderer.heading(2, "", section.title, false)
+
+		for _, item_ := range section.items {
+			generator.renderItem(item_)
+		}
+	}
+
+	return generator.renderer.page(module_.name)
+}
+
+func (generator *pageGenerator) renderItem(item_ *item) {
+	generator.renderer.heading(3, item_.anchor, item_.signature, true)
+
+	if item_.doc != "" {
+		generator.renderer.paragraph(item_.doc, generator.resolver(item_.name))
+	}
+
+	if strings.HasPrefix(item_.signature, "struct ") && len(item_.parameters) > 0 {
+		generator.renderer.references("Constructor parameters", item_.parameters, noLinks)
+	} else if strings.HasPrefix(item_.signature, "trait ") && len(item_.parameters) > 0 {
+		generator.renderer.references("Required fields", item_.parameters, noLinks)
+	}
+
+	if len(item_.traits) > 0 {
+		generator.renderer.references("Implements", item_.traits, generator.resolver(""))
+	}
+
+	for _, member := range append(append([]*item{}, item_.fields...), item_.methods...) {
+		generator.renderer.heading(4, member.anchor, member.signature, true)
+
+		if member.doc != "" {
+			generator.renderer.paragraph(member.doc, generator.resolver(item_.name))
+		}
+	}
+}
+
+/*
+ * References are resolved like names in code, except that members of the struct or trait being
+ * documented (`context`) can be referred to directly.
+ */
+func (generator *pageGenerator) resolver(context string) linkResolver {
+	return func(reference string) (string, bool) {
+		if !referencePattern.MatchString(reference) {
+			return "", false
+		}
+
+		module_ := generator.module
+
+		if context != "" && module_.anchors[context+"."+reference] {
+			return "#" + context + "." + reference, true
+		}
+
+		if module_.anchors[reference] {
+			return "#" + reference, true
+		}
+
+		name, rest, hasRest := strings.Cut(reference, ".")
+
+		if moduleName, ok := module_.moduleImports[name]; ok {
+			return generator.link(moduleName, rest)
+		}
+
+		if imported, ok := module_.valueImports[name]; ok {
+			anchor := imported.name
+
+			if hasRest {
+				anchor += "." + rest
+			}
+
+			return generator.link(imported.moduleName, anchor)
+		}
+
+		return "", false
+	}
+}
+
+// Links to modules that aren't being documented, or to undocumented declarations, are omitted.
+func (generator *pageGenerator) link(moduleName string, anchor string) (string, bool) {
+	target, ok := generator.modules[moduleName]
+
+	if !ok {
+		return "", false
+	}
+
+	page := moduleName + generator.format.extension()
+
+	if anchor == "" {
+		return page, true
+	}
+
+	if !target.anchors[anchor] {
+		return "", false
+	}
+
+	if moduleName == generator.module.name {
+		page = ""
+	}
+
+	return page + "#" + anchor, true
+}
+
+type markdownRenderer struct {
+	blocks []string
+}
+
+func (renderer_ *markdownRenderer) heading(level int, anchor string, text string, isCode bool) {
+	if isCode {
+		text = "`" + text + "`"
+	}
+
+	if anchor != "" {
+		text = fmt.Sprintf("<a id=\"%s\"></a>%s", anchor, text)
+	}
+
+	renderer_.blocks = append(renderer_.blocks, strings.Repeat("#", level)+" "+text)
+}
+
+func markdownReference(reference string, resolveLink linkResolver) string {
+	if link, ok := resolveLink(reference); ok {
+		return fmt.Sprintf("[`%s`](%s)", reference, link)
+	}
+
+	return "`" + reference + "`"
+}
+
+/*
+ * Code that's already linked (i.e. enclosed in brackets) is left as it is.
+ */
+func (renderer_ *markdownRenderer) paragraph(text string, resolveLink linkResolver) {
+	parts := strings.Split(text, "`")
+	result := &strings.Builder{}
+
+	for i, part := range parts {
+		switch {
+		// The last part follows an unclosed backtick if it's preceded by an odd number of them.
+		case i%2 == 0 || i == len(parts)-1:
+			if i%2 == 1 {
+				result.WriteString("`")
+			}
+
+			result.WriteString(part)
+
+		case strings.HasSuffix(parts[i-1], "["):
+			result.WriteString("`" + part + "`")
+
+		default:
+			result.WriteString(markdownReference(part, resolveLink))
+		}
+	}
+
+	renderer_.blocks = append(renderer_.blocks, result.String())
+}
+
+func (renderer_ *markdownRenderer) references(
+	label string,
+	references []string,
+	resolveLink linkResolver,
+) {
+	rendered := make([]string, 0, len(references))
+
+	for _, reference := range references {
+		rendered = append(rendered, markdownReference(reference, resolveLink))
+	}
+
+	renderer_.blocks = append(renderer_.blocks, label+": "+strings.Join(rendered, ", "))
+}
+
+func (renderer_ *markdownRenderer) list(references []string, resolveLink linkResolver) {
+	lines := make([]string, 0, len(references))
+
+	for _, reference := range references {
+		lines = append(lines, "- "+markdownReference(reference, resolveLink))
+	}
+
+	renderer_.blocks = append(renderer_.blocks, strings.Join(lines, "\n"))
+}
+
+func (renderer_ *markdownRenderer) page(string) string {
+	return strings.Join(renderer_.blocks, "\n\n") + "\n"
+}
+
+type htmlRenderer struct {
+	blocks []string
+}
+
+func (renderer_ *htmlRenderer) heading(level int, anchor string, text string, isCode bool) {
+	text = html.EscapeString(text)
+
+	if isCode {
+		text = "<code>" + text + "</code>"
+	}
+
+	attributes := ""
+
+	if anchor != "" {
+		attributes = fmt.Sprintf(" id=\"%s\"", html.EscapeString(anchor))
+	}
+
+	renderer_.blocks = append(
+		renderer_.blocks,
+		fmt.Sprintf("<h%d%s>%s</h%d>", level, attributes, text, level),
+	)
+}
+
+func htmlReference(reference string, resolveLink linkResolver) string {
+	code := "<code>" + html.EscapeString(reference) + "</code>"
+
+	if link, ok := resolveLink(reference); ok {
+		return fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(link), code)
+	}
+
+	return code
+}
+
+/*
+ * Doc comments are split into paragraphs at blank lines; other than code, they're escaped as plain
+ * text.
+ */
+func (renderer_ *htmlRenderer) paragraph(text string, resolveLink linkResolver) {
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimSpace(paragraph), "`")
+		result := &strings.Builder{}
+
+		for i, part := range parts {
+			if i%2 == 1 && i < len(parts)-1 {
+				result.WriteString(htmlReference(part, resolveLink))
+			} else if i%2 == 1 {
+				result.WriteString(html.EscapeString("`" + part))
+			} else {
+				result.WriteString(html.EscapeString(part))
+			}
+		}
+
+		renderer_.blocks = append(renderer_.blocks, "<p>"+result.String()+"</p>")
+	}
+}
+
+func (renderer_ *htmlRenderer) references(
+	label string,
+	references []string,
+	resolveLink linkResolver,
+) {
+	rendered := make([]string, 0, len(references))
+
+	for _, reference := range references {
+		rendered = append(rendered, htmlReference(reference, resolveLink))
+	}
+
+	renderer_.blocks = append(
+		renderer_.blocks,
+		fmt.Sprintf("<p>%s: %s</p>", html.EscapeString(label), strings.Join(rendered, ", ")),
+	)
+}
+
+func (renderer_ *htmlRenderer) list(references []string, resolveLink linkResolver) {
+	items := make([]string, 0, len(references))
+
+	for _, reference := range references {
+		items = append(items, "<li>"+htmlReference(reference, resolveLink)+"</li>")
+	}
+
+	renderer_.blocks = append(renderer_.blocks, "<ul>\n"+strings.Join(items, "\n")+"\n</ul>")
+}
+
+func (renderer_ *htmlRenderer) page(title string) string {
+	return fmt.Sprintf(
+		"<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n%s\n</body>\n</html>\n",
+		html.EscapeString(title),
+		strings.Join(renderer_.blocks, "\n"),
+	)
+}
@@ -3,6 +3,8 @@ package environment_variables
 import (
 	"os"
 	"runtime"
+
+	"project_umbrella/interpreter/standard_library"
 )
 
 func getEnvironmentVariable(name string, defaultLinuxValue string) string {
@@ -17,11 +19,19 @@ func getEnvironmentVariable(name string, defaultLinuxValue string) string {
 	return ""
 }
 
+func getEnvironmentVariableOnAnyPlatform(name string, defaultValue string) string {
+	if result, ok := os.LookupEnv(name); ok {
+		return result
+	}
+
+	return defaultValue
+}
+
 var (
 	KRAIT_PATH    = getEnvironmentVariable("KRAIT_PATH", "/usr/lib/krait/standard_library")
-	KRAIT_STARTUP = getEnvironmentVariable(
+	KRAIT_STARTUP = getEnvironmentVariableOnAnyPlatform(
 		"KRAIT_STARTUP",
-		"/usr/lib/krait/startup_file.krait",
+		standard_library.StartupFilePath,
 	)
 
 	KRAIT_STARTUP_EXCLUDE = getEnvironmentVariable(
@@ -3,9 +3,11 @@ package errors
 import (
 	"fmt"
 	"math"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
+
+	"project_umbrella/interpreter/standard_library"
 )
 
 const contextLines = 3
@@ -123,14 +125,67 @@ func tabAdjustedCodeLines(lines []string) []string {
 	return adjustedLines
 }
 
+/*
+ * Sources highlighted in place of the files at their paths, for files whose contents haven't been
+ * saved (e.g. documents being edited through the language server).
+ */
+var sourceOverrides sync.Map
+
+func OverrideSource(path string, source string) {
+	sourceOverrides.Store(path, source)
+}
+
+func RemoveSourceOverride(path string) {
+	sourceOverrides.Delete(path)
+}
+
+// Sources read to locate positions within them, which are assumed not to change
+var locatedSources sync.Map
+
+/*
+ * The line and column (both starting from 1, with columns counted in bytes) at which the position
+ * starts, or 0 and 0 if its file can't be read.
+ */
+func (position *Position) StartLineAndColumn() (int, int) {
+	var source string
+
+	if override, ok := sourceOverrides.Load(position.Filename); ok {
+		source = override.(string)
+	} else if cached, ok := locatedSources.Load(position.Filename); ok {
+		source = cached.(string)
+	} else {
+		sourceByteSlice, err := standard_library.ReadFile(position.Filename)
+
+		if err != nil {
+			return 0, 0
+		}
+
+		source = string(sourceByteSlice)
+		locatedSources.Store(position.Filename, source)
+	}
+
+	beforeStart := source[:min(position.Start, len(source))]
+	line := strings.Count(beforeStart, "\n") + 1
+	column := len(beforeStart) - (strings.LastIndexByte(beforeStart, '\n') + 1) + 1
+
+	return line, column
+}
+
 func highlightedSource(position *Position) string {
-	sourceByteSlice, err := os.ReadFile(position.Filename)
+	var source string
+
+	if override, ok := sourceOverrides.Load(position.Filename); ok {
+		source = override.(string)
+	} else {
+		sourceByteSlice, err := standard_library.ReadFile(position.Filename)
+
+		if err != nil {
+			panic(err)
+		}
 
-	if err != nil {
-		panic(err)
+		source = string(sourceByteSlice)
 	}
 
-	source := string(sourceByteSlice)
 	lines := strings.Split(source, "\n")
 	adjustedLines := tabAdjustedCodeLines(lines)
 	adjustedPosition := newTabAdjustedPosition(lines, position)
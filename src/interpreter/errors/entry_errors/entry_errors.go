@@ -2,6 +2,7 @@ package entry_errors
 
 import (
 	"fmt"
+	"strings"
 
 	"project_umbrella/interpreter/errors"
 )
@@ -27,3 +28,157 @@ func StartupFileNotOpened(path string) *errors.Error {
 		Name:    fmt.Sprintf("Couldn't open the file in $KRAIT_STARTUP: %s", path),
 	}
 }
+
+var DepsCommandNotSpecified = &errors.Error{
+	Section:     "ENTRY",
+	Code:        4,
+	Name:        "Please specify a deps command",
+	Description: "The available commands are `lock`, `vendor` and `install`.",
+}
+
+func UnknownDepsCommand(command string) *errors.Error {
+	return &errors.Error{
+		Section:     "ENTRY",
+		Code:        5,
+		Name:        fmt.Sprintf("Unknown deps command: %s", command),
+		Description: "The available commands are `lock`, `vendor` and `install`.",
+	}
+}
+
+var FormatPathsNotSpecified = &errors.Error{
+	Section: "ENTRY",
+	Code:    6,
+	Name:    "Please specify the files or directories to format",
+}
+
+func FileNotWritten(path string) *errors.Error {
+	return &errors.Error{
+		Section: "ENTRY",
+		Code:    7,
+		Name:    fmt.Sprintf("Couldn't write to the file: %s", path),
+	}
+}
+
+func FilesNotFormatted(paths []string) *errors.Error {
+	return &errors.Error{
+		Section: "ENTRY",
+		Code:    8,
+		Name:    "Some files aren't formatted",
+		Description: fmt.Sprintf(
+			"%s\n\nRun `interpreter fmt` to format them.",
+			strings.Join(paths, "\n"),
+		),
+	}
+}
+
+var LintPathsNotSpecified = &errors.Error{
+	Section: "ENTRY",
+	Code:    9,
+	Name:    "Please specify the files or directories to lint",
+}
+
+func LintProblemsFound(problemCount int) *errors.Error {
+	noun := "problems"
+
+	if problemCount == 1 {
+		noun = "problem"
+	}
+
+	return &errors.Error{
+		Section: "ENTRY",
+		Code:    10,
+		Name:    fmt.Sprintf("Found %d %s", problemCount, noun),
+	}
+}
+
+var DocumentationPathsNotSpecified = &errors.Error{
+	Section: "ENTRY",
+	Code:    11,
+	Name:    "Please specify the files, directories or modules to document",
+}
+
+func DocumentedModuleNameConflict(moduleName string) *errors.Error {
+	return &errors.Error{
+		Section: "ENTRY",
+		Code:    12,
+		Name:    fmt.Sprintf("Multiple modules to document are named %s", moduleName),
+	}
+}
+
+var ProfilePathsNotSpecified = &errors.Error{
+	Section: "ENTRY",
+	Code:    13,
+	Name:    "Please specify the file to write the profile to and the file to run",
+}
+
+var TracePathsNotSpecified = &errors.Error{
+	Section: "ENTRY",
+	Code:    14,
+	Name:    "Please specify the file to write the trace to and the file to run",
+}
+
+var DebugFileNotSpecified = &errors.Error{
+	Section: "ENTRY",
+	Code:    15,
+	Name:    "Please specify the file to debug, or --dap to start a debug adapter",
+}
+
+var CoveragePathsNotSpecified = &errors.Error{
+	Section: "ENTRY",
+	Code:    16,
+	Name:    "Please specify the file to write coverage data to and the file to run",
+}
+
+var CoverageReportNotSpecified = &errors.Error{
+	Section:     "ENTRY",
+	Code:        17,
+	Name:        "Please specify a report format and the coverage data files to report",
+	Description: "The available formats are `lcov` and `annotate`.",
+}
+
+func UnknownCoverageReportFormat(format string) *errors.Error {
+	return &errors.Error{
+		Section:     "ENTRY",
+		Code:        18,
+		Name:        fmt.Sprintf("Unknown coverage report format: %s", format),
+		Description: "The available formats are `lcov` and `annotate`.",
+	}
+}
+
+func CoverageDataNotRead(path string) *errors.Error {
+	return &errors.Error{
+		Section: "ENTRY",
+		Code:    19,
+		Name:    fmt.Sprintf("Couldn't read coverage data from the file: %s", path),
+	}
+}
+
+func TestsFailed(failedCount int) *errors.Error {
+	noun := "tests"
+
+	if failedCount == 1 {
+		noun = "test"
+	}
+
+	return &errors.Error{
+		Section: "ENTRY",
+		Code:    20,
+		Name:    fmt.Sprintf("%d %s failed", failedCount, noun),
+	}
+}
+
+var InterpreterNotRestarted = &errors.Error{
+	Section: "ENTRY",
+	Code:    21,
+	Name:    "Couldn't restart the interpreter to load a changed native library",
+	Description: "Libraries loaded as plugins (`.so` files) can't be unloaded, so they're only " +
+		"loaded again when the interpreter restarts.",
+}
+
+var InstrumentedProgramWatched = &errors.Error{
+	Section: "ENTRY",
+	Code:    22,
+	Name:    "A program run with --watch can't be instrumented",
+	Description: "`--profile`, `--trace` and `--coverage` write their output once the program " +
+		"finishes, which a watched program doesn't.",
+}
@@ -10,6 +10,18 @@ type Error struct {
 	Code        int
 	Name        string
 	Description string
+
+	/*
+	 * Arbitrary data attached by whatever raised the error (e.g. the value passed to `fail`), made
+	 * available to whatever catches it.
+	 */
+	Payload any
+
+	// Where the error occurred, if it was raised by `RaisePositionalError`
+	Position *Position
+
+	// The statements being evaluated when the error was raised by a Krait program, innermost first
+	Trace []*Position
 }
 
 /*
@@ -27,38 +39,107 @@ type PositionalError struct {
 	Position *Position
 }
 
+/*
+ * Raising an error unwinds the current goroutine until the error is caught by `Catch`. Errors that
+ * aren't caught are reported by `ExitOnUncaughtError`.
+ */
 func RaiseError(error_ *Error) {
+	panic(error_)
+}
+
+/*
+ * Call `callback`, returning the error it raised (if any) instead of letting it unwind further.
+ */
+func Catch(callback func()) (caught *Error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			error_, ok := recovered.(*Error)
+
+			if !ok {
+				panic(recovered)
+			}
+
+			caught = error_
+		}
+	}()
+
+	callback()
+
+	return nil
+}
+
+/*
+ * The error, with the statement at `position` added to the end of its trace. Errors are copied
+ * before their traces are started, since they may be shared (e.g. those declared as variables).
+ */
+func (error_ *Error) Traced(position *Position) *Error {
+	if len(error_.Trace) == 0 {
+		copied := *error_
+		error_ = &copied
+	}
+
+	error_.Trace = append(error_.Trace, position)
+
+	return error_
+}
+
+/*
+ * Report an error that unwound the current goroutine and exit. Every goroutine that may raise
+ * errors should defer this.
+ */
+func ExitOnUncaughtError() {
+	if recovered := recover(); recovered != nil {
+		error_, ok := recovered.(*Error)
+
+		if !ok {
+			panic(recovered)
+		}
+
+		fmt.Fprint(os.Stderr, error_.String())
+
+		os.Exit(1)
+	}
+}
+
+/*
+ * Format the error as it's reported to the user.
+ */
+func (error_ *Error) String() string {
 	description := ""
 
 	if error_.Description != "" {
 		description = fmt.Sprintf("\n%s\n", error_.Description)
 	}
 
-	fmt.Fprintf(
-		os.Stderr,
+	return fmt.Sprintf(
 		"Error (%s-%d): %s\n%s",
 		error_.Section,
 		error_.Code,
 		error_.Name,
 		description,
 	)
-
-	os.Exit(1)
 }
 
-func RaisePositionalError(error_ *PositionalError) {
+/*
+ * Convert a positional error into an error whose description begins with the source at its
+ * position, highlighted.
+ */
+func (error_ *PositionalError) Highlighted() *Error {
 	description := ""
 
 	if error_.Error.Description != "" {
 		description = fmt.Sprintf("\n%s", error_.Error.Description)
 	}
 
-	RaiseError(
-		&Error{
-			Section:     error_.Error.Section,
-			Code:        error_.Error.Code,
-			Name:        error_.Error.Name,
-			Description: fmt.Sprintf("%s%s", highlightedSource(error_.Position), description),
-		},
-	)
+	return &Error{
+		Section:     error_.Error.Section,
+		Code:        error_.Error.Code,
+		Name:        error_.Error.Name,
+		Description: fmt.Sprintf("%s%s", highlightedSource(error_.Position), description),
+		Position:    error_.Position,
+	}
+}
+
+func RaisePositionalError(error_ *PositionalError) {
+	RaiseError(error_.Highlighted())
 }
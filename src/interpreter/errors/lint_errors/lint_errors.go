@@ -0,0 +1,81 @@
+package lint_errors
+
+import (
+	"fmt"
+	"strings"
+
+	"project_umbrella/interpreter/errors"
+)
+
+func UnusedValue(valueName string) *errors.Error {
+	return &errors.Error{
+		Section:     "LINT",
+		Code:        1,
+		Name:        fmt.Sprintf("Unused value: `%s`", valueName),
+		Description: "Remove it if it isn't needed.",
+	}
+}
+
+func UnusedParameter(parameterName string) *errors.Error {
+	return &errors.Error{
+		Section:     "LINT",
+		Code:        2,
+		Name:        fmt.Sprintf("Unused parameter: `%s`", parameterName),
+		Description: "Remove it, or prefix its name with an underscore if it's deliberately unused.",
+	}
+}
+
+func ValueShadowed(valueName string) *errors.Error {
+	return &errors.Error{
+		Section: "LINT",
+		Code:    3,
+		Name:    fmt.Sprintf("Built-in or standard library value shadowed: `%s`", valueName),
+		Description: fmt.Sprintf(
+			"`%s` can't be referred to where this value is in scope. Consider renaming it.",
+			valueName,
+		),
+	}
+}
+
+var BranchUnreachableAfterTrueCondition = &errors.Error{
+	Section:     "LINT",
+	Code:        4,
+	Name:        "Unreachable branch",
+	Description: "A previous condition is always true.",
+}
+
+var BranchUnreachableWithFalseCondition = &errors.Error{
+	Section:     "LINT",
+	Code:        4,
+	Name:        "Unreachable branch",
+	Description: "Its condition is always false.",
+}
+
+func ValueCycle(functionName string, valueNames []string) *errors.Error {
+	quotedValueNames := make([]string, 0, len(valueNames))
+
+	for _, valueName := range valueNames {
+		quotedValueNames = append(quotedValueNames, fmt.Sprintf("`%s`", valueName))
+	}
+
+	return &errors.Error{
+		Section: "LINT",
+		Code:    5,
+		Name: fmt.Sprintf(
+			"Function refers to itself through a value: `%s` through %s",
+			functionName,
+			strings.Join(quotedValueNames, ", "),
+		),
+
+		Description: "Evaluating the value calls the function, which requires the value to have been evaluated.",
+	}
+}
+
+func UnusedImport(valueName string) *errors.Error {
+	return &errors.Error{
+		Section:     "LINT",
+		Code:        6,
+		Name:        fmt.Sprintf("Unused import: `%s`", valueName),
+		Description: "Remove it.",
+	}
+}
@@ -48,3 +48,44 @@ func UnknownValue(valueName string) *errors.Error {
 		Name:    fmt.Sprintf("Unknown value: `%s`", valueName),
 	}
 }
+
+var SelfParameterVariadic = &errors.Error{
+	Section: "PARSER",
+	Code:    7,
+	Name:    "A struct's first parameter cannot be a rest parameter",
+	Description: "The first parameter of a struct refers to the struct itself. " +
+		"Consider adding a rest parameter after it.",
+}
+
+var StructParameterDestructured = &errors.Error{
+	Section:     "PARSER",
+	Code:        8,
+	Name:        "A struct's parameters cannot be destructured",
+	Description: "Consider destructuring the parameter in the struct's body instead.",
+}
+
+func PrivateFieldSelected(fieldName string) *errors.Error {
+	return &errors.Error{
+		Section: "PARSER",
+		Code:    9,
+		Name:    fmt.Sprintf("Private field selected: `%s`", fieldName),
+		Description: "Names beginning with an underscore are private to the module or struct " +
+			"declaring them.",
+	}
+}
+
+var RestParameterNotLast = &errors.Error{
+	Section: "PARSER",
+	Code:    11,
+	Name:    "A rest parameter must be the last parameter",
+	Description: "A rest parameter collects all remaining arguments, so no parameter can " +
+		"follow it.",
+}
+
+func CopiedFieldDuplicated(fieldName string) *errors.Error {
+	return &errors.Error{
+		Section: "PARSER",
+		Code:    12,
+		Name:    fmt.Sprintf("Argument replaced more than once by a copy: `%s`", fieldName),
+	}
+}
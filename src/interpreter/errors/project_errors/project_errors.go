@@ -0,0 +1,125 @@
+package project_errors
+
+import (
+	"fmt"
+	"strings"
+
+	"project_umbrella/interpreter/errors"
+)
+
+var ManifestNotFound = &errors.Error{
+	Section:     "PROJECT",
+	Code:        1,
+	Name:        "Couldn't find a krait.toml manifest",
+	Description: "Run this command from within a project directory.",
+}
+
+func ManifestInvalid(path string, err error) *errors.Error {
+	return &errors.Error{
+		Section:     "PROJECT",
+		Code:        2,
+		Name:        fmt.Sprintf("The manifest at %s is invalid", path),
+		Description: fmt.Sprintf("%s.", err),
+	}
+}
+
+func DependencyNotFound(dependencyName string, source string) *errors.Error {
+	return &errors.Error{
+		Section: "PROJECT",
+		Code:    3,
+		Name: fmt.Sprintf(
+			"The dependency \"%s\" wasn't found at %s",
+			dependencyName,
+			source,
+		),
+	}
+}
+
+func DependencyNameMismatch(dependencyName string, packageName string) *errors.Error {
+	return &errors.Error{
+		Section: "PROJECT",
+		Code:    4,
+		Name: fmt.Sprintf(
+			"The dependency \"%s\" refers to a package named \"%s\"",
+			dependencyName,
+			packageName,
+		),
+	}
+}
+
+func DependencyVersionMismatch(
+	dependencyName string,
+	requiredVersion string,
+	foundVersion string,
+) *errors.Error {
+	return &errors.Error{
+		Section: "PROJECT",
+		Code:    5,
+		Name: fmt.Sprintf(
+			"Version %s of \"%s\" was required, but version %s was found",
+			requiredVersion,
+			dependencyName,
+			foundVersion,
+		),
+	}
+}
+
+/*
+ * `requirers` pairs each conflicting version with the name of the package that required it.
+ */
+func DependencyVersionConflict(dependencyName string, requirers [][2]string) *errors.Error {
+	var description strings.Builder
+
+	for _, requirer := range requirers {
+		description.WriteString(
+			fmt.Sprintf("\n  %s requires %s %s", requirer[0], dependencyName, requirer[1]),
+		)
+	}
+
+	return &errors.Error{
+		Section: "PROJECT",
+		Code:    6,
+		Name: fmt.Sprintf(
+			"Conflicting versions of \"%s\" are required",
+			dependencyName,
+		),
+
+		Description: fmt.Sprintf(
+			"Only one version of each package can be vendored:%s",
+			description.String(),
+		),
+	}
+}
+
+func DependencyArchiveInvalid(path string, err error) *errors.Error {
+	return &errors.Error{
+		Section:     "PROJECT",
+		Code:        7,
+		Name:        fmt.Sprintf("Couldn't extract the archive at %s", path),
+		Description: fmt.Sprintf("%s.", err),
+	}
+}
+
+var LockfileNotFound = &errors.Error{
+	Section:     "PROJECT",
+	Code:        8,
+	Name:        "Couldn't find a krait.lock lockfile",
+	Description: "Run `interpreter deps lock` to create one.",
+}
+
+func LockfileOutdated(dependencyName string) *errors.Error {
+	return &errors.Error{
+		Section:     "PROJECT",
+		Code:        9,
+		Name:        fmt.Sprintf("The locked contents of \"%s\" have changed", dependencyName),
+		Description: "Run `interpreter deps lock` to update the lockfile.",
+	}
+}
+
+func ProjectIOFailed(err error) *errors.Error {
+	return &errors.Error{
+		Section: "PROJECT",
+		Code:    10,
+		Name:    fmt.Sprintf("A project file couldn't be read or written: %s", err),
+	}
+}
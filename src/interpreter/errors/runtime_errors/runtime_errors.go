@@ -245,3 +245,186 @@ func CodepointCalledOnNonCharacter(stringValue string) *errors.Error {
 		),
 	}
 }
+
+var NonTupleSpread = &errors.Error{
+	Section: "RUNTIME",
+	Code:    19,
+	Name:    "A non-tuple was spread into a call's arguments",
+}
+
+func DestructuringArityMismatch(
+	value string,
+	elementName string,
+	length int,
+	patternLength int,
+) *errors.Error {
+	if length != 1 {
+		elementName += "s"
+	}
+
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    20,
+		Name:    "A pattern's length doesn't match that of the value it destructures",
+		Description: fmt.Sprintf(
+			"%s has %d %s, but the pattern has %d.",
+			value,
+			length,
+			elementName,
+			patternLength,
+		),
+	}
+}
+
+func DestructuredValueNotTuple(value string) *errors.Error {
+	return &errors.Error{
+		Section:     "RUNTIME",
+		Code:        21,
+		Name:        "A tuple pattern was used to destructure a non-tuple",
+		Description: fmt.Sprintf("Expected a tuple, but got %s.", value),
+	}
+}
+
+func DestructuredValueNotStructInstance(value string, constructorName string) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    22,
+		Name:    "A struct pattern was used to destructure a value of a different type",
+		Description: fmt.Sprintf(
+			"Expected an instance of %s, but got %s.",
+			constructorName,
+			value,
+		),
+	}
+}
+
+func CopiedFieldNotArgument(structName string, fieldName string) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    23,
+		Name:    "Only a struct's arguments can be replaced when copying it",
+		Description: fmt.Sprintf(
+			"`%s` isn't an argument of %s. Its other fields are recomputed from its arguments.",
+			fieldName,
+			structName,
+		),
+	}
+}
+
+func TraitFieldMissing(structName string, traitName string, fieldName string) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    24,
+		Name:    "A struct is missing a field required by a trait it implements",
+		Description: fmt.Sprintf(
+			"%s implements %s, but has no `%s` field.",
+			structName,
+			traitName,
+			fieldName,
+		),
+	}
+}
+
+func Failure(description string, value any) *errors.Error {
+	return &errors.Error{
+		Section:     "RUNTIME",
+		Code:        25,
+		Name:        "A failure was raised",
+		Description: description,
+		Payload:     value,
+	}
+}
+
+func PrivateFieldSelected(fieldName string) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    26,
+		Name:    fmt.Sprintf("Private field selected: `%s`", fieldName),
+		Description: "Fields whose names begin with an underscore can only be referenced by name " +
+			"from within the code declaring them.",
+	}
+}
+
+func ExtensionNotStarted(extensionPath string, err error) *errors.Error {
+	return &errors.Error{
+		Section:     "RUNTIME",
+		Code:        27,
+		Name:        fmt.Sprintf("Couldn't start the extension at \"%s\"", extensionPath),
+		Description: fmt.Sprintf("%s.", err),
+	}
+}
+
+func ExtensionProtocolViolated(extensionPath string, description string) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    28,
+		Name: fmt.Sprintf(
+			"The extension at \"%s\" violated the extension protocol",
+			extensionPath,
+		),
+
+		Description: description,
+	}
+}
+
+func ExtensionExited(extensionPath string) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    29,
+		Name:    fmt.Sprintf("The extension at \"%s\" exited unexpectedly", extensionPath),
+	}
+}
+
+func ExtensionCallFailed(extensionPath string, symbolName string, message string) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    30,
+		Name: fmt.Sprintf(
+			"Calling \"%s\" in the extension at \"%s\" failed",
+			symbolName,
+			extensionPath,
+		),
+
+		Description: message,
+	}
+}
+
+func ExtensionArgumentNotSerializable(symbolName string, i int) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    31,
+		Name:    "An extension function was called with an argument that can't be sent to it",
+		Description: fmt.Sprintf(
+			"Argument #%d to %s isn't an integer, float, string, boolean, unit or tuple thereof.",
+			i+1,
+			symbolName,
+		),
+	}
+}
+
+func LibraryValueNotConvertible(
+	libraryPath string,
+	symbolName string,
+	goTypeName string,
+) *errors.Error {
+	return &errors.Error{
+		Section: "RUNTIME",
+		Code:    32,
+		Name:    "A library function returned a value with no Krait equivalent",
+		Description: fmt.Sprintf(
+			"\"%s\" in the library at \"%s\" returned a value of type %s.",
+			symbolName,
+			libraryPath,
+			goTypeName,
+		),
+	}
+}
+
+func LibraryNotOpened(libraryPath string, reason string, err error) *errors.Error {
+	return &errors.Error{
+		Section:     "RUNTIME",
+		Code:        33,
+		Name:        fmt.Sprintf("Couldn't open the library at \"%s\"", libraryPath),
+		Description: fmt.Sprintf("%s\n%s", reason, err),
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/formatter"
+	"project_umbrella/interpreter/project"
+)
+
+/*
+ * `interpreter fmt <path>...` formats the given Krait files, and those in the given directories, in
+ * place. With `--check`, it instead reports the files that aren't formatted, failing if there are
+ * any.
+ */
+func runFmtCommand(arguments []string) {
+	isCheck := false
+	paths := []string{}
+
+	for _, argument := range arguments {
+		if argument == "--check" {
+			isCheck = true
+		} else {
+			paths = append(paths, argument)
+		}
+	}
+
+	if len(paths) == 0 {
+		errors.RaiseError(entry_errors.FormatPathsNotSpecified)
+	}
+
+	unformattedPaths := []string{}
+
+	for _, path := range kraitFilesInPaths(paths) {
+		source, err := os.ReadFile(path)
+
+		if err != nil {
+			errors.RaiseError(entry_errors.FileNotOpened(path))
+		}
+
+		formatted := formatter.Format(path, string(source))
+
+		if formatted == string(source) {
+			continue
+		}
+
+		if isCheck {
+			unformattedPaths = append(unformattedPaths, path)
+
+			continue
+		}
+
+		if err := os.WriteFile(path, []byte(formatted), 0o644); err != nil {
+			errors.RaiseError(entry_errors.FileNotWritten(path))
+		}
+	}
+
+	if len(unformattedPaths) > 0 {
+		errors.RaiseError(entry_errors.FilesNotFormatted(unformattedPaths))
+	}
+}
+
+/*
+ * Directories are searched recursively for files with the `.krait` extension, skipping vendored
+ * dependencies. Files are included regardless of their extension.
+ */
+func kraitFilesInPaths(paths []string) []string {
+	result := []string{}
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				if entry.Name() == project.VendorDirectoryName {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if path == root || strings.HasSuffix(path, ".krait") {
+				result = append(result, path)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			errors.RaiseError(entry_errors.FileNotOpened(root))
+		}
+	}
+
+	return result
+}
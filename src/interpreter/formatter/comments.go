@@ -0,0 +1,288 @@
+package formatter
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"project_umbrella/interpreter/parser"
+)
+
+type comment struct {
+	text  string
+	start int
+	end   int
+}
+
+type statementListInformation struct {
+	list  *parser.ConcreteStatementList
+	depth int
+
+	// The number of indentation characters preceding the list's statements in the source.
+	indentation int
+}
+
+/*
+ * Comments on lines of their own are printed before the statement following them (`leading`) or,
+ * if they're indented past the end of a block, after the block's last statement (`dangling`).
+ * Comments following code on the same line are printed at the end of the line the token they
+ * follow is printed on (`trailing`, keyed by the token's offset).
+ */
+type commentAttachments struct {
+	leading  map[parser.ConcreteStatement][]*comment
+	dangling map[*parser.ConcreteStatementList][]*comment
+	trailing map[int][]string
+}
+
+var layoutTokenTypes = map[lexer.TokenType]bool{
+	lexer.TokenType(parser.CommentToken): true,
+	lexer.TokenType(parser.IndentToken):  true,
+	lexer.TokenType(parser.NewlineToken): true,
+	lexer.TokenType(parser.OutdentToken): true,
+}
+
+// The tokens the formatter prints with anchors, so trailing comments can follow them.
+var anchoredTokenTypes = map[lexer.TokenType]bool{
+	lexer.TokenType(parser.ElseKeywordToken):       true,
+	lexer.TokenType(parser.FloatToken):             true,
+	lexer.TokenType(parser.FromKeywordToken):       true,
+	lexer.TokenType(parser.FunctionKeywordToken):   true,
+	lexer.TokenType(parser.IdentifierToken):        true,
+	lexer.TokenType(parser.IfKeywordToken):         true,
+	lexer.TokenType(parser.ImplementsKeywordToken): true,
+	lexer.TokenType(parser.InKeywordToken):         true,
+	lexer.TokenType(parser.IntegerToken):           true,
+	lexer.TokenType(parser.LetKeywordToken):        true,
+	lexer.TokenType(parser.OperatorToken):          true,
+	lexer.TokenType(parser.StringToken):            true,
+	lexer.TokenType(parser.StructKeywordToken):     true,
+	lexer.TokenType(parser.TraitKeywordToken):      true,
+}
+
+func codeTokens(tokens []lexer.Token) []lexer.Token {
+	result := []lexer.Token{}
+
+	for _, token := range tokens {
+		if !layoutTokenTypes[token.Type] {
+			result = append(result, token)
+		}
+	}
+
+	return result
+}
+
+/*
+ * The range of source a statement spans, excluding any indentation and newlines it consumed.
+ *
+ * The end is looked up in `tokenEnds`, since the values of string tokens in the syntax tree have
+ * had their quotes removed.
+ */
+func (formatter_ *formatter) statementSpan(statement parser.ConcreteStatement) (int, int) {
+	tokens := codeTokens(statement.Tokens_())
+
+	if len(tokens) == 0 {
+		return 0, 0
+	}
+
+	return tokens[0].Pos.Offset, formatter_.tokenEnds[tokens[len(tokens)-1].Pos.Offset]
+}
+
+func lineIndentation(source string, offset int) int {
+	lineStart := strings.LastIndexByte(source[:offset], '\n') + 1
+
+	return len(source[lineStart:]) - len(strings.TrimLeft(source[lineStart:], "\t "))
+}
+
+func isFirstOnLine(source string, offset int) bool {
+	lineStart := strings.LastIndexByte(source[:offset], '\n') + 1
+
+	return strings.TrimLeft(source[lineStart:offset], "\t ") == ""
+}
+
+/*
+ * Find every statement list in a concrete syntax tree, along with how deeply it's nested in other
+ * statement lists.
+ */
+func collectStatementLists(
+	value reflect.Value,
+	depth int,
+	visit func(list *parser.ConcreteStatementList, depth int),
+) {
+	switch value.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if value.IsNil() {
+			return
+		}
+
+		if list, ok := value.Interface().(*parser.ConcreteStatementList); ok {
+			visit(list, depth)
+
+			for _, child := range list.Children {
+				collectStatementLists(reflect.ValueOf(child), depth+1, visit)
+			}
+
+			return
+		}
+
+		collectStatementLists(value.Elem(), depth, visit)
+
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				collectStatementLists(value.Field(i), depth, visit)
+			}
+		}
+
+	case reflect.Slice:
+		if value.Type().Elem() == reflect.TypeOf(lexer.Token{}) {
+			return
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			collectStatementLists(value.Index(i), depth, visit)
+		}
+	}
+}
+
+func (formatter_ *formatter) attachComments(
+	tokens []lexer.Token,
+	root *parser.ConcreteStatementList,
+) *commentAttachments {
+	source := formatter_.source
+	result := &commentAttachments{
+		leading:  map[parser.ConcreteStatement][]*comment{},
+		dangling: map[*parser.ConcreteStatementList][]*comment{},
+		trailing: map[int][]string{},
+	}
+
+	code := formatter_.code
+	statementsByStart := map[int]parser.ConcreteStatement{}
+	listsByEnd := map[int][]*statementListInformation{}
+	statements := []parser.ConcreteStatement{}
+	statementDepths := map[parser.ConcreteStatement]int{}
+
+	collectStatementLists(
+		reflect.ValueOf(root),
+		0,
+		func(list *parser.ConcreteStatementList, depth int) {
+			if len(list.Children) == 0 {
+				return
+			}
+
+			for _, child := range list.Children {
+				start, _ := formatter_.statementSpan(child)
+
+				statementsByStart[start] = child
+				statements = append(statements, child)
+				statementDepths[child] = depth
+			}
+
+			firstStart, _ := formatter_.statementSpan(list.Children[0])
+			_, lastEnd := formatter_.statementSpan(list.Children[len(list.Children)-1])
+
+			listsByEnd[lastEnd] = append(listsByEnd[lastEnd], &statementListInformation{
+				list:        list,
+				depth:       depth,
+				indentation: lineIndentation(source, firstStart),
+			})
+		},
+	)
+
+	for _, token := range tokens {
+		if token.Type != lexer.TokenType(parser.CommentToken) {
+			continue
+		}
+
+		comment_ := &comment{
+			text:  strings.TrimRight(token.Value, "\t "),
+			start: token.Pos.Offset,
+			end:   token.Pos.Offset + len(token.Value),
+		}
+
+		nextIndex := sort.Search(len(code), func(i int) bool {
+			return code[i].Pos.Offset > comment_.start
+		})
+
+		var previous *lexer.Token
+
+		if nextIndex > 0 {
+			previous = &code[nextIndex-1]
+		}
+
+		if previous != nil && !isFirstOnLine(source, comment_.start) {
+			anchor := previous.Pos.Offset
+
+			for i := nextIndex - 1; i >= 0; i-- {
+				if strings.Contains(source[code[i].Pos.Offset:previous.Pos.Offset], "\n") {
+					break
+				}
+
+				if anchoredTokenTypes[code[i].Type] {
+					anchor = code[i].Pos.Offset
+
+					break
+				}
+			}
+
+			result.trailing[anchor] = append(result.trailing[anchor], comment_.text)
+
+			continue
+		}
+
+		if previous != nil {
+			candidates := listsByEnd[previous.Pos.Offset+len(previous.Value)]
+
+			sort.Slice(candidates, func(i int, j int) bool {
+				return candidates[i].depth > candidates[j].depth
+			})
+
+			isAttached := false
+
+			for _, candidate := range candidates {
+				if lineIndentation(source, comment_.start) >= candidate.indentation {
+					result.dangling[candidate.list] = append(result.dangling[candidate.list], comment_)
+					isAttached = true
+
+					break
+				}
+			}
+
+			if isAttached {
+				continue
+			}
+		}
+
+		if nextIndex < len(code) {
+			if statement, ok := statementsByStart[code[nextIndex].Pos.Offset]; ok {
+				result.leading[statement] = append(result.leading[statement], comment_)
+
+				continue
+			}
+		}
+
+		/*
+		 * The comment is inside an expression, where we can't print it, so we move it before the
+		 * innermost statement containing it.
+		 */
+		var container parser.ConcreteStatement
+
+		for _, statement := range statements {
+			start, end := formatter_.statementSpan(statement)
+
+			if start < comment_.start && comment_.start < end &&
+				(container == nil || statementDepths[statement] > statementDepths[container]) {
+				container = statement
+			}
+		}
+
+		if container == nil {
+			result.dangling[root] = append(result.dangling[root], comment_)
+		} else {
+			result.leading[container] = append(result.leading[container], comment_)
+		}
+	}
+
+	return result
+}
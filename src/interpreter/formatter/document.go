@@ -0,0 +1,432 @@
+package formatter
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const maximumLineWidth = 100
+const tabWidth = 4
+
+/*
+ * Source is formatted by first describing it as a document, which the renderer then lays out within
+ * `maximumLineWidth` columns. This is a variant of Wadler's "prettier printer": groups are printed
+ * on a single line if they fit and otherwise have each of their lines broken.
+ */
+type document interface {
+	document()
+}
+
+type documents []document
+
+// `anchor` is the source offset of the token the text was printed from, or -1.
+type text struct {
+	value  string
+	anchor int
+}
+
+/*
+ * Lines are printed as a space when their group fits on a single line, and as a newline otherwise.
+ * Hard lines are always printed as newlines.
+ */
+type line struct {
+	isHard bool
+}
+
+type indent struct {
+	contents document
+}
+
+type group struct {
+	contents document
+	isBroken bool
+}
+
+/*
+ * Prints the first option whose first line fits, or the last option if none do. This allows us to
+ * try a layout (e.g. keeping a call's arguments on the same line as the call, even though its last
+ * argument is a multi-line function) before falling back to another.
+ */
+type alternatives struct {
+	options []document
+}
+
+/*
+ * A block's statements must be followed by a newline, regardless of what's printed after them
+ * (e.g. the closing parenthesis of a call the block's function was passed to).
+ */
+type blockEnd struct{}
+
+// Prints any trailing comments anchored within the given range that haven't been printed yet.
+type commentFlush struct {
+	start int
+	end   int
+}
+
+func (documents) document()     {}
+func (*text) document()         {}
+func (*line) document()         {}
+func (*indent) document()       {}
+func (*group) document()        {}
+func (*alternatives) document() {}
+func (*blockEnd) document()     {}
+func (*commentFlush) document() {}
+
+var hardLine = &line{
+	isHard: true,
+}
+
+var spaceLine = &line{
+	isHard: false,
+}
+
+func newText(value string) *text {
+	return &text{
+		value:  value,
+		anchor: -1,
+	}
+}
+
+func newAnchoredText(value string, anchor int) *text {
+	return &text{
+		value:  value,
+		anchor: anchor,
+	}
+}
+
+func newGroup(contents document) *group {
+	return &group{
+		contents: contents,
+		isBroken: containsForcedBreak(contents),
+	}
+}
+
+func joinDocuments(items []document, separator ...document) documents {
+	result := documents{}
+
+	for i, item := range items {
+		if i > 0 {
+			result = append(result, separator...)
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+/*
+ * Whether the document can't be printed on a single line. Groups cache the result for their
+ * contents, so this doesn't descend into them.
+ */
+func containsForcedBreak(document_ document) bool {
+	switch document_ := document_.(type) {
+	case documents:
+		for _, child := range document_ {
+			if containsForcedBreak(child) {
+				return true
+			}
+		}
+
+		return false
+
+	case *text:
+		return strings.Contains(document_.value, "\n")
+
+	case *line:
+		return document_.isHard
+
+	case *indent:
+		return containsForcedBreak(document_.contents)
+
+	case *group:
+		return document_.isBroken
+
+	case *alternatives:
+		return containsForcedBreak(document_.options[0])
+
+	case *blockEnd:
+		return true
+
+	default:
+		return false
+	}
+}
+
+type renderMode int
+
+const (
+	breakMode renderMode = iota
+	flatMode
+)
+
+type renderCommand struct {
+	indentation int
+	mode        renderMode
+	document    document
+}
+
+type renderer struct {
+	result             strings.Builder
+	column             int
+	isIndentPending    bool
+	isNewlineNeeded    bool
+	lineSuffixes       []string
+	trailingComments   map[int][]string
+	remainingAnchors   []int
+	pendingIndentation int
+}
+
+func textWidth(value string) int {
+	if i := strings.LastIndexByte(value, '\n'); i != -1 {
+		value = value[i+1:]
+	}
+
+	return utf8.RuneCountInString(value) + strings.Count(value, "\t")*(tabWidth-1)
+}
+
+/*
+ * Lay out a document, printing each of `trailingComments` (keyed by the source offset of the token
+ * they follow) at the end of the line the token was printed on.
+ */
+func render(document_ document, trailingComments map[int][]string) string {
+	renderer_ := &renderer{
+		trailingComments: trailingComments,
+		remainingAnchors: make([]int, 0, len(trailingComments)),
+	}
+
+	for anchor := range trailingComments {
+		renderer_.remainingAnchors = append(renderer_.remainingAnchors, anchor)
+	}
+
+	sort.Ints(renderer_.remainingAnchors)
+
+	stack := []*renderCommand{
+		{
+			indentation: 0,
+			mode:        breakMode,
+			document:    document_,
+		},
+	}
+
+	for len(stack) > 0 {
+		command := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch document_ := command.document.(type) {
+		case documents:
+			for i := len(document_) - 1; i >= 0; i-- {
+				stack = append(stack, &renderCommand{command.indentation, command.mode, document_[i]})
+			}
+
+		case *text:
+			renderer_.writeText(document_, command.indentation)
+
+		case *line:
+			if command.mode == breakMode || document_.isHard {
+				renderer_.writeNewline(command.indentation)
+			} else {
+				renderer_.writeText(newText(" "), command.indentation)
+			}
+
+		case *indent:
+			stack = append(
+				stack,
+				&renderCommand{command.indentation + 1, command.mode, document_.contents},
+			)
+
+		case *group:
+			mode := command.mode
+
+			if mode == breakMode {
+				flatCommand := &renderCommand{command.indentation, flatMode, document_.contents}
+
+				if !document_.isBroken && renderer_.fits(flatCommand, stack) {
+					mode = flatMode
+				}
+			}
+
+			stack = append(stack, &renderCommand{command.indentation, mode, document_.contents})
+
+		case *alternatives:
+			chosen := document_.options[0]
+
+			if command.mode == breakMode {
+				chosen = document_.options[len(document_.options)-1]
+
+				for _, option := range document_.options[:len(document_.options)-1] {
+					if renderer_.fits(&renderCommand{command.indentation, breakMode, option}, stack) {
+						chosen = option
+
+						break
+					}
+				}
+			}
+
+			stack = append(stack, &renderCommand{command.indentation, command.mode, chosen})
+
+		case *blockEnd:
+			renderer_.isNewlineNeeded = true
+
+		case *commentFlush:
+			renderer_.flushComments(document_.start, document_.end)
+		}
+	}
+
+	renderer_.flushComments(0, int(^uint(0)>>1))
+	renderer_.flushLineSuffixes()
+
+	return renderer_.result.String()
+}
+
+func (renderer_ *renderer) writeText(text_ *text, indentation int) {
+	if renderer_.isNewlineNeeded {
+		renderer_.writeNewline(indentation)
+	}
+
+	if renderer_.isIndentPending {
+		renderer_.result.WriteString(strings.Repeat("\t", renderer_.pendingIndentation))
+		renderer_.isIndentPending = false
+	}
+
+	renderer_.result.WriteString(text_.value)
+
+	if strings.Contains(text_.value, "\n") {
+		renderer_.column = textWidth(text_.value)
+	} else {
+		renderer_.column += textWidth(text_.value)
+	}
+
+	if comments, ok := renderer_.trailingComments[text_.anchor]; ok {
+		renderer_.lineSuffixes = append(renderer_.lineSuffixes, comments...)
+
+		delete(renderer_.trailingComments, text_.anchor)
+	}
+}
+
+/*
+ * Indentation is written lazily, when text is written to the line, so blank lines don't contain
+ * whitespace.
+ */
+func (renderer_ *renderer) writeNewline(indentation int) {
+	renderer_.flushLineSuffixes()
+	renderer_.result.WriteByte('\n')
+	renderer_.column = indentation * tabWidth
+	renderer_.isIndentPending = true
+	renderer_.isNewlineNeeded = false
+	renderer_.pendingIndentation = indentation
+}
+
+func (renderer_ *renderer) flushLineSuffixes() {
+	for _, suffix := range renderer_.lineSuffixes {
+		renderer_.result.WriteString(" ")
+		renderer_.result.WriteString(suffix)
+	}
+
+	renderer_.lineSuffixes = nil
+}
+
+func (renderer_ *renderer) flushComments(start int, end int) {
+	remainingAnchors := renderer_.remainingAnchors[:0]
+
+	for _, anchor := range renderer_.remainingAnchors {
+		comments, ok := renderer_.trailingComments[anchor]
+
+		if !ok {
+			continue
+		}
+
+		if anchor >= start && anchor < end {
+			renderer_.lineSuffixes = append(renderer_.lineSuffixes, comments...)
+
+			delete(renderer_.trailingComments, anchor)
+		} else {
+			remainingAnchors = append(remainingAnchors, anchor)
+		}
+	}
+
+	renderer_.remainingAnchors = remainingAnchors
+}
+
+/*
+ * Whether the command, followed by the rest of the stack, can be printed up to its next newline
+ * without exceeding the line width. Groups are assumed to be printed flat unless they contain a
+ * forced break.
+ */
+func (renderer_ *renderer) fits(command *renderCommand, stack []*renderCommand) bool {
+	remainingWidth := maximumLineWidth - renderer_.column
+
+	if renderer_.isNewlineNeeded {
+		remainingWidth = maximumLineWidth - command.indentation*tabWidth
+	}
+
+	commands := []*renderCommand{command}
+	restIndex := len(stack) - 1
+
+	for remainingWidth >= 0 {
+		if len(commands) == 0 {
+			if restIndex < 0 {
+				return true
+			}
+
+			commands = append(commands, stack[restIndex])
+			restIndex--
+
+			continue
+		}
+
+		command := commands[len(commands)-1]
+		commands = commands[:len(commands)-1]
+
+		switch document_ := command.document.(type) {
+		case documents:
+			for i := len(document_) - 1; i >= 0; i-- {
+				commands = append(
+					commands,
+					&renderCommand{command.indentation, command.mode, document_[i]},
+				)
+			}
+
+		case *text:
+			if strings.Contains(document_.value, "\n") {
+				return true
+			}
+
+			remainingWidth -= textWidth(document_.value)
+
+		case *line:
+			if command.mode == breakMode || document_.isHard {
+				return true
+			}
+
+			remainingWidth--
+
+		case *indent:
+			commands = append(
+				commands,
+				&renderCommand{command.indentation + 1, command.mode, document_.contents},
+			)
+
+		case *group:
+			mode := flatMode
+
+			if document_.isBroken {
+				mode = breakMode
+			}
+
+			commands = append(commands, &renderCommand{command.indentation, mode, document_.contents})
+
+		case *alternatives:
+			commands = append(
+				commands,
+				&renderCommand{command.indentation, command.mode, document_.options[0]},
+			)
+
+		case *blockEnd:
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,848 @@
+/*
+ * Formatting:
+ *
+ * formatter reprints Krait source in a canonical style. Blocks are indented with tabs, infix
+ * operators and assignments are surrounded by single spaces, and runs of blank lines are collapsed,
+ * with a blank line always following multi-line declarations. Calls, tuples and parameter lists
+ * that don't fit on a line have one element printed per line, as do chains of method calls, which
+ * are also kept broken if they were broken in the source.
+ *
+ * Comments are preserved: those on lines of their own stay on lines of their own, and those
+ * following code stay at the end of its line.
+ */
+package formatter
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"project_umbrella/interpreter/parser"
+)
+
+type formatter struct {
+	source   string
+	code     []lexer.Token
+	comments *commentAttachments
+
+	// The end offset of each token in `code`, keyed by its start offset.
+	tokenEnds map[int]int
+}
+
+func Format(path string, source string) string {
+	tokens := parser.Tokens(path, source)
+	root := parser.ParseSource(path, source)
+	formatter_ := &formatter{
+		source:    source,
+		code:      codeTokens(tokens),
+		comments:  nil,
+		tokenEnds: map[int]int{},
+	}
+
+	for _, token := range formatter_.code {
+		formatter_.tokenEnds[token.Pos.Offset] = token.Pos.Offset + len(token.Value)
+	}
+
+	formatter_.comments = formatter_.attachComments(tokens, root)
+	result := render(formatter_.statementList(root), formatter_.comments.trailing)
+
+	if result == "" {
+		return ""
+	}
+
+	return result + "\n"
+}
+
+func (formatter_ *formatter) tokenText(token lexer.Token) *text {
+	offset := token.Pos.Offset
+
+	return newAnchoredText(formatter_.source[offset:formatter_.tokenEnds[offset]], offset)
+}
+
+// The last token preceding the given offset, ignoring indentation, newlines and comments.
+func (formatter_ *formatter) tokenBefore(offset int) lexer.Token {
+	result := formatter_.code[0]
+
+	for _, token := range formatter_.code {
+		if token.Pos.Offset >= offset {
+			break
+		}
+
+		result = token
+	}
+
+	return result
+}
+
+func firstTokenOfType(tokens []lexer.Token, type_ lexer.TokenType) lexer.Token {
+	for _, token := range tokens {
+		if token.Type == type_ {
+			return token
+		}
+	}
+
+	panic("The token wasn't found.")
+}
+
+// Whether the first opening parenthesis among the tokens is followed by a newline.
+func isBrokenAfterOpeningParenthesis(tokens []lexer.Token) bool {
+	for i, token := range tokens {
+		if token.Type == lexer.TokenType(parser.LeftParenthesisToken) {
+			return i+1 < len(tokens) && tokens[i+1].Type == lexer.TokenType(parser.NewlineToken)
+		}
+	}
+
+	return false
+}
+
+func isBrokenBeforeSelect(tokens []lexer.Token) bool {
+	for _, token := range tokens {
+		switch token.Type {
+		case lexer.TokenType(parser.NewlineToken):
+			return true
+
+		case lexer.TokenType(parser.SelectOperatorToken):
+			return false
+		}
+	}
+
+	return false
+}
+
+func isDeclaration(statement parser.ConcreteStatement) bool {
+	switch statement.(type) {
+	case *parser.ConcreteFunction, *parser.ConcreteStruct, *parser.ConcreteTrait:
+		return true
+
+	default:
+		return false
+	}
+}
+
+/*
+ * Print a parenthesized, comma-separated list, either on one line or with one item per line. If
+ * only the last item spans multiple lines and it's a function with a block, the list is kept on
+ * one line, as long as the line with the block's header fits.
+ */
+func delimitedList(items []document, isLastItemHuggable bool, isBrokenInSource bool) document {
+	if len(items) == 0 {
+		return newText("()")
+	}
+
+	flat := documents{
+		newText("("),
+		joinDocuments(items, newText(", ")),
+		newText(")"),
+	}
+
+	broken := documents{
+		newText("("),
+		&indent{
+			contents: documents{hardLine, joinDocuments(items, newText(","), hardLine)},
+		},
+
+		hardLine,
+		newText(")"),
+	}
+
+	if isBrokenInSource || (containsForcedBreak(items[len(items)-1]) && !isLastItemHuggable) {
+		return broken
+	}
+
+	for _, item := range items[:len(items)-1] {
+		if containsForcedBreak(item) {
+			return broken
+		}
+	}
+
+	return &alternatives{
+		options: []document{flat, broken},
+	}
+}
+
+// Whether the expression is an anonymous function with no operators applied to it.
+func isFunctionExpression(expression parser.ConcreteExpression) bool {
+	miscellaneous := expression.(*parser.ConcreteInfixMiscellaneous)
+
+	if len(miscellaneous.Right_) > 0 ||
+		len(miscellaneous.Left_.Right_) > 0 ||
+		len(miscellaneous.Left_.Left_.Right_) > 0 {
+		return false
+	}
+
+	prefixOperation := miscellaneous.Left_.Left_.Left_
+
+	if len(prefixOperation.Operators) > 0 || prefixOperation.Operand.Let == nil {
+		return false
+	}
+
+	function := prefixOperation.Operand.Let.AnonymousFunction
+
+	return function != nil && function.ParametersAndBody != nil
+}
+
+// Tuples (and tuple patterns) with fewer than two elements are distinguished by a trailing comma.
+func tupleList(items []document, isBrokenInSource bool) document {
+	switch len(items) {
+	case 0:
+		return newText("(,)")
+
+	case 1:
+		return documents{newText("("), items[0], newText(",)")}
+
+	default:
+		return delimitedList(items, false, isBrokenInSource)
+	}
+}
+
+func (formatter_ *formatter) statementList(list *parser.ConcreteStatementList) document {
+	result := documents{}
+	previousEnd := -1
+	isBlankLineNeeded := false
+
+	addItem := func(item document, start int, end int) {
+		if previousEnd != -1 {
+			result = append(result, hardLine)
+
+			if isBlankLineNeeded ||
+				(start > previousEnd &&
+					strings.Count(formatter_.source[previousEnd:start], "\n") >= 2) {
+				result = append(result, hardLine)
+			}
+		}
+
+		result = append(result, item)
+		previousEnd = end
+		isBlankLineNeeded = false
+	}
+
+	for _, statement := range list.Children {
+		start, end := formatter_.statementSpan(statement)
+
+		for _, comment_ := range formatter_.comments.leading[statement] {
+			// Comments moved out of the statement shouldn't be separated from it.
+			if comment_.start > start {
+				addItem(newText(comment_.text), start, start)
+			} else {
+				addItem(newText(comment_.text), comment_.start, comment_.end)
+			}
+		}
+
+		statementDocument := formatter_.statement(statement)
+
+		addItem(documents{statementDocument, &commentFlush{start, end}}, start, end)
+
+		isBlankLineNeeded = isDeclaration(statement) && containsForcedBreak(statementDocument)
+	}
+
+	for _, comment_ := range formatter_.comments.dangling[list] {
+		addItem(newText(comment_.text), comment_.start, comment_.end)
+	}
+
+	return result
+}
+
+func (formatter_ *formatter) statement(statement parser.ConcreteStatement) document {
+	switch statement := statement.(type) {
+	case *parser.ConcreteAssignment:
+		return formatter_.assignment(statement)
+
+	case *parser.ConcreteFromImport:
+		return formatter_.fromImport(statement)
+
+	case *parser.ConcreteFunction:
+		return documents{
+			formatter_.tokenText(statement.Tokens[0]),
+			newText(" "),
+			formatter_.identifier(statement.Name),
+			formatter_.parametersAndBody(statement.ParametersAndBody, statement.Tokens),
+		}
+
+	case *parser.ConcreteInfixMiscellaneous:
+		return formatter_.expression(statement)
+
+	case *parser.ConcreteStruct:
+		return formatter_.struct_(statement)
+
+	case *parser.ConcreteTrait:
+		return formatter_.trait(statement)
+	}
+
+	panic("Unknown statement type.")
+}
+
+func (formatter_ *formatter) assignment(assignment *parser.ConcreteAssignment) document {
+	result := documents{}
+
+	for current := assignment; current != nil; current = current.Tail {
+		if current.Pattern == nil {
+			result = append(result, formatter_.identifier(current.Name))
+		} else {
+			result = append(result, formatter_.pattern(current.Pattern))
+		}
+
+		result = append(result, newText(" = "))
+
+		if current.Tail == nil {
+			result = append(result, formatter_.expression(current.Value))
+		}
+	}
+
+	return result
+}
+
+func (formatter_ *formatter) fromImport(fromImport *parser.ConcreteFromImport) document {
+	var path document
+
+	if fromImport.Path == nil {
+		components := make([]document, 0, len(fromImport.Components))
+
+		for _, component := range fromImport.Components {
+			components = append(components, formatter_.identifier(component))
+		}
+
+		path = joinDocuments(components, newText("."))
+	} else {
+		path = formatter_.tokenText(fromImport.Path.Tokens[0])
+	}
+
+	names := []document{formatter_.identifier(fromImport.Head)}
+
+	for _, name := range fromImport.Tail {
+		names = append(names, formatter_.identifier(name))
+	}
+
+	return documents{
+		formatter_.tokenText(fromImport.Tokens[0]),
+		newText(" "),
+		path,
+		newText(" "),
+		formatter_.tokenText(formatter_.tokenBefore(fromImport.Head.Tokens[0].Pos.Offset)),
+		newText(" "),
+		newGroup(&indent{
+			contents: joinDocuments(names, newText(","), spaceLine),
+		}),
+	}
+}
+
+func (formatter_ *formatter) struct_(struct_ *parser.ConcreteStruct) document {
+	result := documents{
+		formatter_.tokenText(struct_.Tokens[0]),
+		newText(" "),
+		formatter_.identifier(struct_.Name),
+		delimitedList(
+			formatter_.parameters(struct_.Parameters),
+			false,
+			isBrokenAfterOpeningParenthesis(struct_.Tokens),
+		),
+	}
+
+	if len(struct_.Traits) > 0 {
+		traits := make([]document, 0, len(struct_.Traits))
+
+		for _, trait := range struct_.Traits {
+			traits = append(traits, formatter_.select_(trait))
+		}
+
+		result = append(
+			result,
+			newText(" "),
+			formatter_.tokenText(
+				firstTokenOfType(struct_.Tokens, lexer.TokenType(parser.ImplementsKeywordToken)),
+			),
+
+			newText(" "),
+			joinDocuments(traits, newText(", ")),
+		)
+	}
+
+	return append(result, formatter_.block(struct_.Body))
+}
+
+func (formatter_ *formatter) trait(trait *parser.ConcreteTrait) document {
+	parameters := []document{formatter_.identifier(trait.Self)}
+
+	for _, field := range trait.RequiredFields {
+		parameters = append(parameters, formatter_.identifier(field))
+	}
+
+	return documents{
+		formatter_.tokenText(trait.Tokens[0]),
+		newText(" "),
+		formatter_.identifier(trait.Name),
+		delimitedList(parameters, false, isBrokenAfterOpeningParenthesis(trait.Tokens)),
+		formatter_.block(trait.Body),
+	}
+}
+
+func (formatter_ *formatter) parameters(
+	parameters *parser.ConcreteFunctionOrStructParameters,
+) []document {
+	result := []document{}
+
+	for current := parameters; current != nil; current = current.Tail {
+		switch {
+		case current.Rest != nil:
+			result = append(result, documents{newText("..."), formatter_.identifier(current.Rest)})
+
+		case current.HeadPattern != nil:
+			result = append(result, formatter_.pattern(current.HeadPattern))
+
+		default:
+			result = append(result, formatter_.identifier(current.Head))
+		}
+	}
+
+	return result
+}
+
+func (formatter_ *formatter) parametersAndBody(
+	parametersAndBody *parser.ConcreteFunctionParametersAndBody,
+	tokens []lexer.Token,
+) document {
+	return documents{
+		delimitedList(
+			formatter_.parameters(parametersAndBody.Parameters),
+			false,
+			isBrokenAfterOpeningParenthesis(tokens),
+		),
+
+		formatter_.block(parametersAndBody.Body),
+	}
+}
+
+func (formatter_ *formatter) block(block *parser.ConcreteBlock) document {
+	if block.Expression != nil {
+		return documents{newText(": "), formatter_.expression(block.Expression)}
+	}
+
+	if block.StatementList != nil {
+		return documents{
+			newText(":"),
+			&indent{
+				contents: documents{hardLine, formatter_.statementList(block.StatementList)},
+			},
+
+			&blockEnd{},
+		}
+	}
+
+	return newText(":")
+}
+
+func (formatter_ *formatter) expression(expression parser.ConcreteExpression) document {
+	miscellaneous := expression.(*parser.ConcreteInfixMiscellaneous)
+	operators := make([]*parser.Identifier, 0, len(miscellaneous.Right_))
+	operands := make([]document, 0, len(miscellaneous.Right_))
+
+	for _, right := range miscellaneous.Right_ {
+		operators = append(operators, right.Operator())
+		operands = append(operands, formatter_.infixAddition(right.Operand()))
+	}
+
+	return formatter_.infixOperation(
+		formatter_.infixAddition(miscellaneous.Left_),
+		operators,
+		operands,
+	)
+}
+
+func (formatter_ *formatter) infixAddition(addition *parser.ConcreteInfixAddition) document {
+	operators := make([]*parser.Identifier, 0, len(addition.Right_))
+	operands := make([]document, 0, len(addition.Right_))
+
+	for _, right := range addition.Right_ {
+		operators = append(operators, right.Operator())
+		operands = append(operands, formatter_.infixMultiplication(right.Operand()))
+	}
+
+	return formatter_.infixOperation(
+		formatter_.infixMultiplication(addition.Left_),
+		operators,
+		operands,
+	)
+}
+
+func (formatter_ *formatter) infixMultiplication(
+	multiplication *parser.ConcreteInfixMultiplication,
+) document {
+	operators := make([]*parser.Identifier, 0, len(multiplication.Right_))
+	operands := make([]document, 0, len(multiplication.Right_))
+
+	for _, right := range multiplication.Right_ {
+		operators = append(operators, right.Operator())
+		operands = append(operands, formatter_.prefixOperation(right.Operand()))
+	}
+
+	return formatter_.infixOperation(
+		formatter_.prefixOperation(multiplication.Left_),
+		operators,
+		operands,
+	)
+}
+
+/*
+ * Operations whose first line doesn't fit are broken after each operator. Operands spanning
+ * multiple lines don't otherwise cause them to be broken, so `(...).codepoint() + digit` stays
+ * together.
+ */
+func (formatter_ *formatter) infixOperation(
+	left document,
+	operators []*parser.Identifier,
+	operands []document,
+) document {
+	if len(operators) == 0 {
+		return left
+	}
+
+	flat := documents{left}
+	brokenOperations := documents{}
+
+	for i, operator := range operators {
+		operatorText := newAnchoredText(operator.Value, operator.Position().Start)
+
+		flat = append(flat, newText(" "), operatorText, newText(" "), operands[i])
+		brokenOperations = append(brokenOperations, newText(" "), operatorText, hardLine, operands[i])
+	}
+
+	return &alternatives{
+		options: []document{
+			flat,
+			documents{left, &indent{contents: brokenOperations}},
+		},
+	}
+}
+
+/*
+ * Adjacent prefix operators are separated by spaces, since they would otherwise be lexed as a
+ * single operator, as is an operator from a number it precedes, which would otherwise be lexed as a
+ * signed number.
+ */
+func (formatter_ *formatter) prefixOperation(operation *parser.ConcretePrefixOperation) document {
+	result := documents{}
+
+	for i, operator := range operation.Operators {
+		if i > 0 {
+			result = append(result, newText(" "))
+		}
+
+		result = append(result, formatter_.tokenText(operator.Tokens[0]))
+	}
+
+	if len(operation.Operators) > 0 {
+		operandTokens := codeTokens(operation.Operand.Tokens)
+
+		if len(operandTokens) > 0 {
+			switch operandTokens[0].Type {
+			case lexer.TokenType(parser.FloatToken),
+				lexer.TokenType(parser.IntegerToken),
+				lexer.TokenType(parser.OperatorToken):
+				result = append(result, newText(" "))
+			}
+		}
+	}
+
+	return append(result, formatter_.if_(operation.Operand))
+}
+
+func (formatter_ *formatter) if_(if_ *parser.ConcreteIf) document {
+	if if_.Let != nil {
+		return formatter_.let(if_.Let)
+	}
+
+	result := documents{
+		formatter_.tokenText(if_.Tokens[0]),
+		newText(" "),
+		formatter_.expression(if_.Condition),
+		formatter_.block(if_.Body),
+	}
+
+	for _, elseIf := range if_.ElseIf {
+		result = append(
+			result,
+			hardLine,
+			formatter_.tokenText(elseIf.Tokens[0]),
+			newText(" if "),
+			formatter_.expression(elseIf.Condition),
+			formatter_.block(elseIf.Body),
+		)
+	}
+
+	if if_.Else != nil {
+		result = append(
+			result,
+			hardLine,
+			formatter_.tokenText(if_.Else.Tokens[0]),
+			formatter_.block(if_.Else.Body),
+		)
+	}
+
+	return result
+}
+
+func (formatter_ *formatter) let(let *parser.ConcreteLet) document {
+	if let.AnonymousFunction != nil {
+		return formatter_.anonymousFunction(let.AnonymousFunction)
+	}
+
+	bindings := []document{formatter_.assignment(let.Head)}
+
+	for _, binding := range let.Tail {
+		bindings = append(bindings, formatter_.assignment(binding))
+	}
+
+	valueStart := codeTokens(let.Value.Tokens_())[0].Pos.Offset
+
+	return newGroup(documents{
+		formatter_.tokenText(let.Tokens[0]),
+		newText(" "),
+		&indent{
+			contents: joinDocuments(bindings, newText(","), spaceLine),
+		},
+
+		spaceLine,
+		formatter_.tokenText(formatter_.tokenBefore(valueStart)),
+		newText(" "),
+		formatter_.expression(let.Value),
+	})
+}
+
+func (formatter_ *formatter) anonymousFunction(
+	function *parser.ConcreteAnonymousFunction,
+) document {
+	if function.Call != nil {
+		return formatter_.call(function.Call)
+	}
+
+	return formatter_.parametersAndBody(function.ParametersAndBody, function.Tokens)
+}
+
+type chainLink struct {
+	document       documents
+	isBrokenBefore bool
+}
+
+/*
+ * Calls and selects following a call (e.g. `next().map(mapper).get_or(default)`) form a chain,
+ * which is broken before each select if it doesn't fit on a line, if one of its calls but the last
+ * spans multiple lines, or if it was broken in the source. A select directly following a value
+ * that isn't called (e.g. `math.min(...)`) stays with the value, unless it was broken in the source.
+ */
+func (formatter_ *formatter) call(call *parser.ConcreteCall) document {
+	type chainRight struct {
+		field          *parser.ConcreteIdentifier
+		copy           *parser.ConcreteCopyRight
+		arguments      *parser.ConcreteCallRight
+		isBrokenBefore bool
+	}
+
+	rights := []*chainRight{}
+
+	for _, right := range call.Left.Right {
+		rights = append(rights, &chainRight{
+			field:          right.Field,
+			copy:           nil,
+			arguments:      nil,
+			isBrokenBefore: isBrokenBeforeSelect(right.Tokens),
+		})
+	}
+
+	for _, right := range call.Right {
+		switch {
+		case right.Copy != nil:
+			rights = append(rights, &chainRight{nil, right.Copy, nil, isBrokenBeforeSelect(right.Tokens)})
+
+		case right.Select != nil:
+			rights = append(
+				rights,
+				&chainRight{right.Select.Field, nil, nil, isBrokenBeforeSelect(right.Tokens)},
+			)
+
+		default:
+			rights = append(rights, &chainRight{nil, nil, right, false})
+		}
+	}
+
+	head := documents{formatter_.primary(call.Left.Left)}
+	i := 0
+
+	for ; i < len(rights) && rights[i].arguments != nil; i++ {
+		head = append(head, formatter_.callArguments(rights[i].arguments))
+	}
+
+	isHeadCalled := i > 0
+	links := []*chainLink{}
+
+	for i < len(rights) {
+		link := &chainLink{
+			document:       documents{},
+			isBrokenBefore: rights[i].isBrokenBefore,
+		}
+
+		isCalled := false
+
+		for ; i < len(rights) && (!isCalled || rights[i].arguments != nil); i++ {
+			switch right := rights[i]; {
+			case right.field != nil:
+				link.document = append(link.document, newText("."), formatter_.identifier(right.field))
+
+			case right.copy != nil:
+				link.document = append(link.document, newText("."), formatter_.copy(right.copy))
+				isCalled = true
+
+			default:
+				link.document = append(link.document, formatter_.callArguments(right.arguments))
+				isCalled = true
+			}
+		}
+
+		links = append(links, link)
+	}
+
+	if !isHeadCalled && len(links) > 0 && !links[0].isBrokenBefore {
+		head = append(head, links[0].document)
+		links = links[1:]
+	}
+
+	if len(links) == 0 {
+		return head
+	}
+
+	flat := documents{head}
+	brokenLinks := documents{}
+	isBroken := false
+
+	for i, link := range links {
+		flat = append(flat, link.document)
+		brokenLinks = append(brokenLinks, hardLine, link.document)
+		isBroken = isBroken ||
+			link.isBrokenBefore ||
+			(i < len(links)-1 && containsForcedBreak(link.document))
+	}
+
+	broken := documents{head, &indent{contents: brokenLinks}}
+
+	if isBroken {
+		return broken
+	}
+
+	return &alternatives{
+		options: []document{flat, broken},
+	}
+}
+
+func (formatter_ *formatter) callArguments(right *parser.ConcreteCallRight) document {
+	arguments := []document{}
+	isLastArgumentHuggable := false
+
+	for current := right.Arguments; current != nil; current = current.Tail {
+		argument := formatter_.expression(current.Head.Value)
+
+		if current.Head.IsSpread {
+			argument = documents{newText("..."), argument}
+		}
+
+		arguments = append(arguments, argument)
+		isLastArgumentHuggable = !current.Head.IsSpread && isFunctionExpression(current.Head.Value)
+	}
+
+	return delimitedList(
+		arguments,
+		isLastArgumentHuggable,
+		isBrokenAfterOpeningParenthesis(right.Tokens),
+	)
+}
+
+func (formatter_ *formatter) copy(copy *parser.ConcreteCopyRight) document {
+	fields := []document{}
+
+	for _, field := range append([]*parser.ConcreteCopyField{copy.Head}, copy.Tail...) {
+		fields = append(
+			fields,
+			documents{
+				formatter_.identifier(field.Name),
+				newText(" = "),
+				formatter_.expression(field.Value),
+			},
+		)
+	}
+
+	return delimitedList(fields, false, isBrokenAfterOpeningParenthesis(copy.Tokens))
+}
+
+func (formatter_ *formatter) select_(select_ *parser.ConcreteSelect) document {
+	result := documents{formatter_.primary(select_.Left)}
+
+	for _, right := range select_.Right {
+		result = append(result, newText("."), formatter_.identifier(right.Field))
+	}
+
+	return result
+}
+
+func (formatter_ *formatter) primary(primary parser.ConcretePrimary) document {
+	switch primary := primary.(type) {
+	case *parser.ConcreteParenthesized:
+		return delimitedList(
+			[]document{formatter_.expression(primary.Value)},
+			isFunctionExpression(primary.Value),
+			isBrokenAfterOpeningParenthesis(primary.Tokens),
+		)
+
+	case *parser.ConcreteTuple:
+		elements := make([]document, 0, len(primary.Elements))
+
+		for _, element := range primary.Elements {
+			elements = append(elements, formatter_.expression(element))
+		}
+
+		return tupleList(elements, isBrokenAfterOpeningParenthesis(primary.Tokens))
+
+	case *parser.ConcreteFloat:
+		return formatter_.tokenText(primary.Tokens[0])
+
+	case *parser.ConcreteIdentifier:
+		return formatter_.identifier(primary)
+
+	case *parser.ConcreteInteger:
+		return formatter_.tokenText(primary.Tokens[0])
+
+	case *parser.ConcreteString:
+		return formatter_.tokenText(primary.Tokens[0])
+	}
+
+	panic("Unknown primary type.")
+}
+
+func (formatter_ *formatter) pattern(pattern *parser.ConcretePattern) document {
+	elements := pattern.Elements
+
+	if pattern.Constructor != nil {
+		elements = pattern.Arguments
+	}
+
+	elementDocuments := make([]document, 0, len(elements))
+
+	for _, element := range elements {
+		if element.Pattern == nil {
+			elementDocuments = append(elementDocuments, formatter_.identifier(element.Name))
+		} else {
+			elementDocuments = append(elementDocuments, formatter_.pattern(element.Pattern))
+		}
+	}
+
+	isBrokenInSource := isBrokenAfterOpeningParenthesis(pattern.Tokens)
+
+	if pattern.Constructor != nil {
+		return documents{
+			formatter_.identifier(pattern.Constructor),
+			delimitedList(elementDocuments, false, isBrokenInSource),
+		}
+	}
+
+	return tupleList(elementDocuments, isBrokenInSource)
+}
+
+func (formatter_ *formatter) identifier(identifier *parser.ConcreteIdentifier) document {
+	return formatter_.tokenText(identifier.Tokens[0])
+}
@@ -11,3 +11,4 @@ require (
 require github.com/dlclark/regexp2 v1.10.0
 require github.com/puzpuzpuz/xsync/v3 v3.0.2
 require github.com/ugorji/go/codec v1.2.11
+require github.com/BurntSushi/toml v1.4.0
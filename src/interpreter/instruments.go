@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/loader/module_loader"
+	"project_umbrella/interpreter/runtime"
+)
+
+// A file to write what an instrument observed to once the program finishes, and how
+type instrumentOutput struct {
+	path  string
+	write func(writer io.Writer) error
+}
+
+/*
+ * Run the file at `path` observed by `instruments`, then write what they observed to `outputs`.
+ * The outputs are written even if the program fails.
+ */
+func runInstrumentedFile(
+	path string,
+	instruments runtime.Instruments,
+	outputs []instrumentOutput,
+) {
+	loader := module_loader.NewModuleLoader()
+	loader.Instruments = instruments
+
+	err := errors.Catch(func() {
+		loader.LoadFile(path)
+	})
+
+	for _, output := range outputs {
+		file, fileErr := os.Create(output.path)
+
+		if fileErr != nil {
+			errors.RaiseError(entry_errors.FileNotWritten(output.path))
+		}
+
+		if output.write(file) != nil || file.Close() != nil {
+			errors.RaiseError(entry_errors.FileNotWritten(output.path))
+		}
+	}
+
+	if err != nil {
+		errors.RaiseError(err)
+	}
+}
@@ -0,0 +1,472 @@
+package language_server
+
+import (
+	"path/filepath"
+
+	"project_umbrella/interpreter/bytecode_generator"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/loader/file_loader"
+	"project_umbrella/interpreter/loader/module_loader"
+	"project_umbrella/interpreter/parser"
+)
+
+/*
+ * Resolution follows names through assignments and imports, which may be cyclic across modules
+ * (e.g. two modules each importing a name from the other).
+ */
+const maximumResolutionDepth = 32
+
+/*
+ * A source file translated the way it would be were it loaded, so that names are resolved exactly
+ * as the bytecode translator resolves them, along with what's known statically about the values
+ * they're bound to.
+ */
+type analysis struct {
+	path       string
+	source     string
+	root       *parser.ExpressionList
+	translator *bytecode_generator.BytecodeTranslator
+
+	// The expression each declared name is bound to, where that's known
+	bindings map[*parser.Identifier]parser.Expression
+
+	// The functions (including structs) declared by or assigned to names
+	functions map[*parser.Identifier]*parser.Function
+
+	// The `self` parameter of each struct's field factory, mapped to the struct
+	selfParameters map[*parser.Identifier]*parser.Function
+
+	docComments *parser.DocComments
+}
+
+func analyze(path string, source string) (*analysis, *errors.Error) {
+	result := &analysis{
+		path:           path,
+		source:         source,
+		root:           nil,
+		translator:     bytecode_generator.NewBytecodeTranslator(source),
+		bindings:       map[*parser.Identifier]parser.Expression{},
+		functions:      map[*parser.Identifier]*parser.Function{},
+		selfParameters: map[*parser.Identifier]*parser.Function{},
+		docComments:    parser.NewDocComments(path, source),
+	}
+
+	if err := errors.Catch(func() {
+		result.root = file_loader.ModuleExpressionList(path, source)
+		result.translator.ExpressionToBytecode(result.root)
+	}); err != nil {
+		return nil, err
+	}
+
+	result.collectBindings(result.root)
+
+	return result, nil
+}
+
+func (analysis_ *analysis) collectBindings(expression parser.Expression) {
+	switch expression := expression.(type) {
+	case *parser.Assignment:
+		for _, name := range expression.Names_ {
+			analysis_.bindings[name] = expression.Value
+
+			if function, ok := expression.Value.(*parser.Function); ok {
+				analysis_.functions[name] = function
+			}
+		}
+
+		/*
+		 * Imports are desugared into tuple patterns assigned the result of a function returning a
+		 * tuple (see `ConcreteFromImport.Abstract`), which we bind element by element.
+		 */
+		if call, ok := expression.Value.(*parser.Call); ok && len(expression.Patterns) == 1 {
+			if function, ok := call.Function.(*parser.Function); ok {
+				elements := expression.Patterns[0].Elements
+				tuple, ok := lastExpression(function.Body).(*parser.Call)
+
+				if ok && tuple.IsBuiltInCall("__tuple__") && len(tuple.Arguments) == len(elements) {
+					for i, element := range elements {
+						if name, ok := element.(*parser.Identifier); ok {
+							analysis_.bindings[name] = tuple.Arguments[i]
+						}
+					}
+				}
+			}
+		}
+
+	case *parser.Call:
+		// Immediately called functions bind their parameters to their arguments.
+		if function, ok := expression.Function.(*parser.Function); ok && !function.IsVariadic {
+			for i, parameter := range function.Parameters {
+				if i < len(expression.Arguments) {
+					if _, ok := expression.Arguments[i].(*parser.Spread); ok {
+						break
+					}
+
+					analysis_.bindings[parameter] = expression.Arguments[i]
+				}
+			}
+		}
+
+	case *parser.Function:
+		if expression.Name != nil {
+			analysis_.functions[expression.Name] = expression
+		}
+
+		if fieldFactory, ok := expression.StructFieldFactory(); ok {
+			analysis_.selfParameters[fieldFactory.Parameters[0]] = expression
+		}
+	}
+
+	for _, child := range expression.Children() {
+		analysis_.collectBindings(child)
+	}
+}
+
+/*
+ * Find the innermost name at `offset`, which is either an identifier or the field of a select. Only
+ * names written in the source are considered, and not those generated by desugaring.
+ */
+func (analysis_ *analysis) nameAt(offset int) (*parser.Identifier, *parser.Select) {
+	var resultIdentifier *parser.Identifier
+	var resultSelect *parser.Select
+
+	resultLength := len(analysis_.source) + 1
+
+	isNameAt := func(identifier *parser.Identifier) bool {
+		position := identifier.Position()
+
+		return position != nil &&
+			position.Filename == analysis_.path &&
+			position.Start <= offset && offset <= position.End &&
+			analysis_.source[position.Start:position.End] == identifier.Value
+	}
+
+	var visit func(expression parser.Expression)
+
+	visit = func(expression parser.Expression) {
+		switch expression := expression.(type) {
+		case *parser.Identifier:
+			position := expression.Position()
+
+			if isNameAt(expression) && position.End-position.Start < resultLength {
+				resultIdentifier = expression
+				resultSelect = nil
+				resultLength = position.End - position.Start
+			}
+
+		// The fields of selects are preferred to identically positioned identifiers.
+		case *parser.Select:
+			position := expression.Field.Position()
+
+			if isNameAt(expression.Field) && position.End-position.Start <= resultLength {
+				resultIdentifier = nil
+				resultSelect = expression
+				resultLength = position.End - position.Start
+			}
+		}
+
+		for _, child := range expression.Children() {
+			visit(child)
+		}
+	}
+
+	visit(analysis_.root)
+
+	return resultIdentifier, resultSelect
+}
+
+/*
+ * A name's declaration, in whichever analyzed file it was found.
+ */
+type declaration struct {
+	analysis   *analysis
+	identifier *parser.Identifier
+}
+
+/*
+ * The fields of a module or struct instance. Those of modules are its declarations, and those of
+ * structs their parameters and the declarations in their bodies.
+ */
+type members struct {
+	declarations []*declaration
+	isModule     bool
+}
+
+func (members_ *members) find(name string) (*declaration, bool) {
+	for _, declaration_ := range members_.declarations {
+		if declaration_.identifier.Value == name {
+			return declaration_, true
+		}
+	}
+
+	return nil, false
+}
+
+/*
+ * Files are analyzed at most once per request, preferring the contents of open documents to those
+ * on disk.
+ */
+type resolver struct {
+	server       *server
+	moduleLoader *module_loader.ModuleLoader
+	analyses     map[string]*analysis
+	depth        int
+}
+
+func newResolver(server_ *server) *resolver {
+	return &resolver{
+		server:       server_,
+		moduleLoader: module_loader.NewModuleLoader(),
+		analyses:     map[string]*analysis{},
+		depth:        0,
+	}
+}
+
+func (resolver_ *resolver) analysisForModule(
+	importing *analysis,
+	moduleName string,
+) (*analysis, bool) {
+	path, ok := resolver_.moduleLoader.ModulePath(moduleName, filepath.Dir(importing.path))
+
+	if !ok {
+		return nil, false
+	}
+
+	if result, ok := resolver_.analyses[path]; ok {
+		return result, result != nil
+	}
+
+	resolver_.analyses[path] = nil
+
+	source, ok := resolver_.server.source(path)
+
+	if !ok {
+		return nil, false
+	}
+
+	result, err := analyze(path, source)
+
+	if err != nil {
+		return nil, false
+	}
+
+	resolver_.analyses[path] = result
+
+	return result, true
+}
+
+/*
+ * Find the members of the value an expression evaluates to, if it's a module or struct instance
+ * whose members can be determined statically.
+ */
+func (resolver_ *resolver) members(analysis_ *analysis, expression parser.Expression) (
+	*members,
+	bool,
+) {
+	if resolver_.depth >= maximumResolutionDepth {
+		return nil, false
+	}
+
+	resolver_.depth++
+	defer func() { resolver_.depth-- }()
+
+	switch expression := expression.(type) {
+	case *parser.Identifier:
+		declaration_, ok := analysis_.translator.Declaration(expression)
+
+		if !ok {
+			return nil, false
+		}
+
+		if struct_, ok := analysis_.selfParameters[declaration_]; ok {
+			return structMembers(analysis_, struct_), true
+		}
+
+		if value, ok := analysis_.bindings[declaration_]; ok {
+			return resolver_.members(analysis_, value)
+		}
+
+	case *parser.Select:
+		if declaration_, ok := resolver_.member(analysis_, expression); ok {
+			return resolver_.members(declaration_.analysis, declaration_.identifier)
+		}
+
+	case *parser.Call:
+		if moduleName, ok := importedModuleName(analysis_, expression); ok {
+			if module, ok := resolver_.analysisForModule(analysis_, moduleName); ok {
+				return moduleMembers(module), true
+			}
+
+			return nil, false
+		}
+
+		if declaration_, ok := resolver_.resolve(analysis_, expression.Function); ok {
+			function := declaration_.analysis.functions[declaration_.identifier]
+
+			if _, ok := function.StructFieldFactory(); ok {
+				return structMembers(declaration_.analysis, function), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (resolver_ *resolver) member(analysis_ *analysis, select_ *parser.Select) (*declaration, bool) {
+	members_, ok := resolver_.members(analysis_, select_.Value)
+
+	if !ok {
+		return nil, false
+	}
+
+	return members_.find(select_.Field.Value)
+}
+
+/*
+ * Find the declaration of the value an identifier or select refers to, following any names it's
+ * bound to (e.g. through an import) to the name's original declaration.
+ */
+func (resolver_ *resolver) resolve(analysis_ *analysis, expression parser.Expression) (
+	*declaration,
+	bool,
+) {
+	if resolver_.depth >= maximumResolutionDepth {
+		return nil, false
+	}
+
+	resolver_.depth++
+	defer func() { resolver_.depth-- }()
+
+	var result *declaration
+
+	switch expression := expression.(type) {
+	case *parser.Identifier:
+		identifier, ok := analysis_.translator.Declaration(expression)
+
+		if !ok {
+			return nil, false
+		}
+
+		result = &declaration{
+			analysis:   analysis_,
+			identifier: identifier,
+		}
+
+	case *parser.Select:
+		declaration_, ok := resolver_.member(analysis_, expression)
+
+		if !ok {
+			return nil, false
+		}
+
+		result = declaration_
+
+	default:
+		return nil, false
+	}
+
+	switch value := result.analysis.bindings[result.identifier].(type) {
+	case *parser.Identifier, *parser.Select:
+		if aliased, ok := resolver_.resolve(result.analysis, value); ok {
+			return aliased, true
+		}
+	}
+
+	return result, true
+}
+
+func moduleMembers(module *analysis) *members {
+	result := &members{
+		declarations: []*declaration{},
+		isModule:     true,
+	}
+
+	// Declarations from the startup file are exported too, but don't belong to the module.
+	for _, statement := range module.root.Children() {
+		if declaration_, ok := statement.(parser.Declaration); ok {
+			for _, name := range declaration_.Names() {
+				if name.Position() != nil && name.Position().Filename == module.path {
+					result.declarations = append(result.declarations, &declaration{
+						analysis:   module,
+						identifier: name,
+					})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func structMembers(analysis_ *analysis, struct_ *parser.Function) *members {
+	result := &members{
+		declarations: []*declaration{},
+		isModule:     false,
+	}
+
+	call := struct_.Body.Children_[0].(*parser.Call)
+	fieldFactory := call.Arguments[2].(*parser.Function)
+
+	for _, fields := range []parser.Expression{call.Arguments[3], lastExpression(fieldFactory.Body)} {
+		for _, name := range tupleFieldNames(fields) {
+			result.declarations = append(result.declarations, &declaration{
+				analysis:   analysis_,
+				identifier: name,
+			})
+		}
+	}
+
+	return result
+}
+
+/*
+ * The names of the fields in a tuple of name-value pairs, as passed to `__struct__`, `__trait__`,
+ * and `__module__`.
+ */
+func tupleFieldNames(expression parser.Expression) []*parser.Identifier {
+	result := []*parser.Identifier{}
+	tuple, ok := expression.(*parser.Call)
+
+	if !ok || !tuple.IsBuiltInCall("__tuple__") {
+		return result
+	}
+
+	for _, field := range tuple.Arguments {
+		if pair, ok := field.(*parser.Call); ok && len(pair.Arguments) == 2 {
+			if name, ok := pair.Arguments[1].(*parser.Identifier); ok {
+				result = append(result, name)
+			}
+		}
+	}
+
+	return result
+}
+
+func importedModuleName(analysis_ *analysis, call *parser.Call) (string, bool) {
+	identifier, ok := call.Function.(*parser.Identifier)
+
+	if !ok || identifier.Value != "import" || len(call.Arguments) != 1 {
+		return "", false
+	}
+
+	// `import` may have been shadowed.
+	if _, ok := analysis_.translator.Declaration(identifier); ok {
+		return "", false
+	}
+
+	moduleName, ok := call.Arguments[0].(*parser.String)
+
+	if !ok {
+		return "", false
+	}
+
+	return moduleName.Value, true
+}
+
+func lastExpression(expressionList *parser.ExpressionList) parser.Expression {
+	if expressionList == nil || len(expressionList.Children_) == 0 {
+		return nil
+	}
+
+	return expressionList.Children_[len(expressionList.Children_)-1]
+}
@@ -0,0 +1,411 @@
+package language_server
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/parser"
+)
+
+/*
+ * Translation stops at the first error, so at most one diagnostic is reported. Errors without a
+ * position in the document (e.g. in the startup file) are reported at its start.
+ */
+func diagnostics(document_ *document) []*diagnostic {
+	_, err := analyze(document_.path, document_.source)
+
+	if err == nil {
+		return []*diagnostic{}
+	}
+
+	errorRange := rangeForSpan(document_.source, 0, 0)
+
+	if err.Position != nil && err.Position.Filename == document_.path {
+		errorRange = rangeForSpan(document_.source, err.Position.Start, err.Position.End)
+	}
+
+	return []*diagnostic{
+		{
+			Range:    errorRange,
+			Severity: diagnosticSeverityError,
+			Code:     fmt.Sprintf("%s-%d", err.Section, err.Code),
+			Source:   "krait",
+			Message:  err.Name,
+		},
+	}
+}
+
+func (server_ *server) documentAnalysis(uri string) (*analysis, bool) {
+	document_, ok := server_.documents[uri]
+
+	if !ok {
+		return nil, false
+	}
+
+	result, err := analyze(document_.path, document_.source)
+
+	return result, err == nil
+}
+
+func (server_ *server) location(declaration_ *declaration) (*location, bool) {
+	position := declaration_.identifier.Position()
+
+	if position == nil {
+		return nil, false
+	}
+
+	uri, ok := server_.uri(position.Filename)
+
+	if !ok {
+		return nil, false
+	}
+
+	source, ok := server_.source(position.Filename)
+
+	if !ok {
+		return nil, false
+	}
+
+	return &location{
+		URI:   uri,
+		Range: rangeForSpan(source, position.Start, position.End),
+	}, true
+}
+
+/*
+ * Identifiers are resolved to their declarations in scope, and fields selected from modules and
+ * struct instances to their declarations in the module or struct. Imported names are fields of the
+ * modules they're imported from.
+ */
+func (server_ *server) definition(params *textDocumentPositionParams) any {
+	analysis_, ok := server_.documentAnalysis(params.TextDocument.URI)
+
+	if !ok {
+		return nil
+	}
+
+	identifier, select_ := analysis_.nameAt(offsetForPosition(analysis_.source, params.Position))
+
+	var declaration_ *declaration
+
+	if select_ != nil {
+		declaration_, ok = newResolver(server_).member(analysis_, select_)
+	} else if identifier != nil {
+		var declaringIdentifier *parser.Identifier
+
+		declaringIdentifier, ok = analysis_.translator.Declaration(identifier)
+		declaration_ = &declaration{
+			analysis:   analysis_,
+			identifier: declaringIdentifier,
+		}
+	}
+
+	if declaration_ == nil || !ok {
+		return nil
+	}
+
+	if result, ok := server_.location(declaration_); ok {
+		return result
+	}
+
+	return nil
+}
+
+func (server_ *server) hover(params *textDocumentPositionParams) any {
+	analysis_, ok := server_.documentAnalysis(params.TextDocument.URI)
+
+	if !ok {
+		return nil
+	}
+
+	identifier, select_ := analysis_.nameAt(offsetForPosition(analysis_.source, params.Position))
+
+	var name *parser.Identifier
+	var declaration_ *declaration
+
+	if select_ != nil {
+		name = select_.Field
+		declaration_, ok = newResolver(server_).resolve(analysis_, select_)
+	} else if identifier != nil {
+		name = identifier
+		declaration_, ok = newResolver(server_).resolve(analysis_, identifier)
+	}
+
+	if declaration_ == nil || !ok {
+		return nil
+	}
+
+	signature := declaration_.identifier.Value
+	docComment := declaration_.docComment()
+
+	if function, ok := declaration_.analysis.functions[declaration_.identifier]; ok {
+		signature = declaration_.signature(function)
+	} else if docComment == "" {
+		return nil
+	}
+
+	value := fmt.Sprintf("```krait\n%s\n```", signature)
+
+	if docComment != "" {
+		value += "\n\n" + docComment
+	}
+
+	return &hover{
+		Contents: markupContent{
+			Kind:  "markdown",
+			Value: value,
+		},
+
+		Range: rangeForSpan(analysis_.source, name.Position().Start, name.Position().End),
+	}
+}
+
+func (declaration_ *declaration) docComment() string {
+	position := declaration_.identifier.Position()
+
+	if position == nil || position.Filename != declaration_.analysis.path {
+		return ""
+	}
+
+	return declaration_.analysis.docComments.Of(position.Start)
+}
+
+/*
+ * Named functions and structs are described by their declarations' headers (e.g. `fn add(a, b)`),
+ * and other functions by the name they're assigned to followed by their parameters.
+ */
+func (declaration_ *declaration) signature(function *parser.Function) string {
+	parameters := make([]string, 0, len(function.Parameters)+1)
+
+	// Destructured parameters are replaced by placeholders, so we print them from the source.
+	parameterSource := func(parameter *parser.Identifier) string {
+		position := parameter.Position()
+
+		if position != nil && position.Filename == declaration_.analysis.path &&
+			declaration_.analysis.source[position.Start:position.End] != parameter.Value {
+			return declaration_.analysis.source[position.Start:position.End]
+		}
+
+		return parameter.Value
+	}
+
+	fieldFactory, isStruct := function.StructFieldFactory()
+
+	if isStruct {
+		parameters = append(parameters, fieldFactory.Parameters[0].Value)
+	}
+
+	for i, parameter := range function.Parameters {
+		if function.IsVariadic && i == len(function.Parameters)-1 {
+			parameters = append(parameters, "..."+parameter.Value)
+		} else {
+			parameters = append(parameters, parameterSource(parameter))
+		}
+	}
+
+	name := declaration_.identifier.Value
+
+	if function.Name != nil {
+		name = function.Name.Value
+	}
+
+	header := fmt.Sprintf("%s(%s)", name, strings.Join(parameters, ", "))
+
+	if isStruct {
+		return "struct " + header
+	}
+
+	if function.Name != nil {
+		return "fn " + header
+	}
+
+	return header
+}
+
+func (server_ *server) documentSymbols(params *documentSymbolParams) any {
+	document_, ok := server_.documents[params.TextDocument.URI]
+
+	if !ok {
+		return nil
+	}
+
+	var expressionList *parser.ExpressionList
+
+	if errors.Catch(func() {
+		expressionList = parser.ParseSource(document_.path, document_.source).AbstractExpressionList()
+	}) != nil {
+		return nil
+	}
+
+	return symbolsForExpressions(document_.source, expressionList.Children())
+}
+
+func symbolsForExpressions(source string, expressions []parser.Expression) []*documentSymbol {
+	result := []*documentSymbol{}
+
+	newSymbol := func(
+		name *parser.Identifier,
+		expression parser.Expression,
+		kind symbolKind,
+		children []*documentSymbol,
+	) *documentSymbol {
+		return &documentSymbol{
+			Name:           name.Value,
+			Kind:           kind,
+			Range:          rangeForSpan(source, expression.Position().Start, expression.Position().End),
+			SelectionRange: rangeForSpan(source, name.Position().Start, name.Position().End),
+			Children:       children,
+		}
+	}
+
+	fieldSymbols := func(names []*parser.Identifier, body *parser.ExpressionList) []*documentSymbol {
+		children := []*documentSymbol{}
+
+		for _, name := range names {
+			children = append(children, newSymbol(name, name, fieldSymbol, []*documentSymbol{}))
+		}
+
+		// The body's last expression is the tuple of its fields, generated by desugaring.
+		return append(
+			children,
+			symbolsForExpressions(source, body.Children_[:len(body.Children_)-1])...,
+		)
+	}
+
+	for _, expression := range expressions {
+		switch expression := expression.(type) {
+		case *parser.Function:
+			if expression.Name == nil || expression.Position() == nil {
+				continue
+			}
+
+			if fieldFactory, ok := expression.StructFieldFactory(); ok {
+				result = append(result, newSymbol(
+					expression.Name,
+					expression,
+					structSymbol,
+					fieldSymbols(expression.Parameters, fieldFactory.Body),
+				))
+			} else {
+				result = append(result, newSymbol(
+					expression.Name,
+					expression,
+					functionSymbol,
+					symbolsForExpressions(source, expression.Body.Children()),
+				))
+			}
+
+		case *parser.Assignment:
+			if expression.IsParameter || expression.IsFromImport() {
+				continue
+			}
+
+			if defaultFieldFactory, ok := expression.TraitDefaultFieldFactory(); ok {
+				result = append(result, newSymbol(
+					expression.Names_[0],
+					expression,
+					interfaceSymbol,
+					fieldSymbols(defaultFieldFactory.Parameters[1:], defaultFieldFactory.Body),
+				))
+
+				continue
+			}
+
+			for _, name := range expression.Names() {
+				if function, ok := expression.Value.(*parser.Function); ok {
+					result = append(result, newSymbol(
+						name,
+						expression,
+						functionSymbol,
+						symbolsForExpressions(source, function.Body.Children()),
+					))
+				} else {
+					result = append(result, newSymbol(name, expression, variableSymbol, []*documentSymbol{}))
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+/*
+ * Completes the fields of the module or struct instance before the `.` preceding the cursor.
+ *
+ * The document doesn't parse while a select is incomplete (e.g. `point.`), so the select's `.` and
+ * partial field are blanked out before analyzing it, leaving only the value being selected from.
+ */
+func (server_ *server) completion(params *textDocumentPositionParams) any {
+	document_, ok := server_.documents[params.TextDocument.URI]
+
+	if !ok {
+		return []*completionItem{}
+	}
+
+	offset := offsetForPosition(document_.source, params.Position)
+	selectStart := strings.LastIndexFunc(document_.source[:offset], func(character rune) bool {
+		return !unicode.IsLetter(character) && !unicode.IsDigit(character) && character != '_'
+	})
+
+	if selectStart == -1 || document_.source[selectStart] != '.' {
+		return []*completionItem{}
+	}
+
+	source := document_.source[:selectStart] +
+		strings.Repeat(" ", offset-selectStart) +
+		document_.source[offset:]
+
+	analysis_, err := analyze(document_.path, source)
+
+	if err != nil {
+		return []*completionItem{}
+	}
+
+	identifier, select_ := analysis_.nameAt(selectStart)
+
+	var value parser.Expression = identifier
+
+	if select_ != nil {
+		value = select_
+	} else if identifier == nil {
+		return []*completionItem{}
+	}
+
+	members_, ok := newResolver(server_).members(analysis_, value)
+
+	if !ok {
+		return []*completionItem{}
+	}
+
+	result := make([]*completionItem, 0, len(members_.declarations))
+
+	for _, declaration_ := range members_.declarations {
+		item := &completionItem{
+			Label:  declaration_.identifier.Value,
+			Kind:   fieldCompletion,
+			Detail: "",
+		}
+
+		function, isFunction := declaration_.analysis.functions[declaration_.identifier]
+
+		if isFunction {
+			item.Detail = declaration_.signature(function)
+		}
+
+		if _, isStruct := function.StructFieldFactory(); isStruct {
+			item.Kind = structCompletion
+		} else if isFunction && members_.isModule {
+			item.Kind = functionCompletion
+		} else if isFunction {
+			item.Kind = methodCompletion
+		} else if members_.isModule {
+			item.Kind = variableCompletion
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
@@ -0,0 +1,299 @@
+package language_server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"unicode/utf8"
+)
+
+const maximumMessageSize = 64 * 1024 * 1024
+
+// https://www.jsonrpc.org/specification#error_object
+const (
+	parseErrorCode     = -32700
+	methodNotFoundCode = -32601
+	invalidParamsCode  = -32602
+)
+
+const (
+	textDocumentSyncFull    = 1
+	diagnosticSeverityError = 1
+)
+
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#symbolKind
+type symbolKind int
+
+const (
+	fieldSymbol     symbolKind = 8
+	interfaceSymbol symbolKind = 11
+	functionSymbol  symbolKind = 12
+	variableSymbol  symbolKind = 13
+	structSymbol    symbolKind = 23
+)
+
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#completionItemKind
+type completionItemKind int
+
+const (
+	methodCompletion   completionItemKind = 2
+	functionCompletion completionItemKind = 3
+	fieldCompletion    completionItemKind = 5
+	variableCompletion completionItemKind = 6
+	structCompletion   completionItemKind = 22
+)
+
+/*
+ * Requests and notifications are both sent as requests; notifications just don't have an ID, and
+ * therefore don't receive a response.
+ */
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *responseError  `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+/*
+ * Lines and characters are zero-indexed, and characters are counted in UTF-16 code units.
+ */
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type range_ struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+type location struct {
+	URI   string `json:"uri"`
+	Range range_ `json:"range"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentItem struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     position               `json:"position"`
+}
+
+type didOpenTextDocumentParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type didChangeTextDocumentParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseTextDocumentParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type documentSymbolParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type initializeResult struct {
+	Capabilities serverCapabilities `json:"capabilities"`
+	ServerInfo   serverInfo         `json:"serverInfo"`
+}
+
+type serverCapabilities struct {
+	TextDocumentSync       int               `json:"textDocumentSync"`
+	DefinitionProvider     bool              `json:"definitionProvider"`
+	HoverProvider          bool              `json:"hoverProvider"`
+	DocumentSymbolProvider bool              `json:"documentSymbolProvider"`
+	CompletionProvider     completionOptions `json:"completionProvider"`
+}
+
+type completionOptions struct {
+	TriggerCharacters []string `json:"triggerCharacters"`
+}
+
+type serverInfo struct {
+	Name string `json:"name"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string        `json:"uri"`
+	Diagnostics []*diagnostic `json:"diagnostics"`
+}
+
+type diagnostic struct {
+	Range    range_ `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+type hover struct {
+	Contents markupContent `json:"contents"`
+	Range    range_        `json:"range"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type documentSymbol struct {
+	Name           string            `json:"name"`
+	Kind           symbolKind        `json:"kind"`
+	Range          range_            `json:"range"`
+	SelectionRange range_            `json:"selectionRange"`
+	Children       []*documentSymbol `json:"children"`
+}
+
+type completionItem struct {
+	Label  string             `json:"label"`
+	Kind   completionItemKind `json:"kind"`
+	Detail string             `json:"detail,omitempty"`
+}
+
+/*
+ * Messages are framed by HTTP-style headers, of which only `Content-Length` is meaningful.
+ */
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+
+	if err != nil {
+		return nil, err
+	}
+
+	contentLength, err := strconv.Atoi(header.Get("Content-Length"))
+
+	if err != nil || contentLength < 0 || contentLength > maximumMessageSize {
+		return nil, fmt.Errorf("invalid content length: %q", header.Get("Content-Length"))
+	}
+
+	result := make([]byte, contentLength)
+
+	if _, err := io.ReadFull(reader, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func writeMessage(writer io.Writer, message any) error {
+	encoded, err := json.Marshal(message)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "Content-Length: %d\r\n\r\n%s", len(encoded), encoded)
+
+	return err
+}
+
+// Characters outside of the Basic Multilingual Plane are encoded as surrogate pairs.
+func utf16Length(character rune) int {
+	if character >= 0x10000 {
+		return 2
+	}
+
+	return 1
+}
+
+func positionForOffset(source string, offset int) position {
+	result := position{
+		Line:      0,
+		Character: 0,
+	}
+
+	for i, character := range source {
+		if i >= offset {
+			break
+		}
+
+		if character == '\n' {
+			result.Line++
+			result.Character = 0
+		} else {
+			result.Character += utf16Length(character)
+		}
+	}
+
+	return result
+}
+
+/*
+ * Positions past the end of their line are clamped to it, and positions past the end of the
+ * source to its end.
+ */
+func offsetForPosition(source string, position_ position) int {
+	offset := 0
+
+	for line := 0; line < position_.Line; line++ {
+		lineLength := 0
+
+		for offset+lineLength < len(source) && source[offset+lineLength] != '\n' {
+			lineLength++
+		}
+
+		if offset+lineLength == len(source) {
+			return len(source)
+		}
+
+		offset += lineLength + 1
+	}
+
+	for character := 0; character < position_.Character && offset < len(source); {
+		rune_, size := utf8.DecodeRuneInString(source[offset:])
+
+		if rune_ == '\n' {
+			break
+		}
+
+		character += utf16Length(rune_)
+		offset += size
+	}
+
+	return offset
+}
+
+func rangeForSpan(source string, start int, end int) range_ {
+	return range_{
+		Start: positionForOffset(source, start),
+		End:   positionForOffset(source, end),
+	}
+}
@@ -0,0 +1,294 @@
+/*
+ * The language server provides editor support for Krait over the Language Server Protocol
+ * (https://microsoft.github.io/language-server-protocol), communicating over stdin and stdout.
+ *
+ * Documents are analyzed by translating them into bytecode exactly as they would be when loaded,
+ * so that diagnostics and name resolution match the interpreter's. Values aren't evaluated, so the
+ * members of a value (for definitions and completions) are only known when it's a module imported
+ * by name or a struct instance created by calling the struct directly.
+ */
+package language_server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/standard_library"
+)
+
+type document struct {
+	uri    string
+	path   string
+	source string
+}
+
+type server struct {
+	writer    io.Writer
+	documents map[string]*document
+
+	// Whether the client has asked the server to shut down, after which it should only exit
+	isShutDown bool
+}
+
+type requestHandler func(server_ *server, params json.RawMessage) (any, *responseError)
+type notificationHandler func(server_ *server, params json.RawMessage)
+
+var requestHandlers = map[string]requestHandler{
+	"initialize":                  handleRequest((*server).initialize),
+	"shutdown":                    handleRequest((*server).shutdown),
+	"textDocument/definition":     handleRequest((*server).definition),
+	"textDocument/hover":          handleRequest((*server).hover),
+	"textDocument/documentSymbol": handleRequest((*server).documentSymbols),
+	"textDocument/completion":     handleRequest((*server).completion),
+}
+
+var notificationHandlers = map[string]notificationHandler{
+	"textDocument/didOpen":   handleNotification((*server).didOpen),
+	"textDocument/didChange": handleNotification((*server).didChange),
+	"textDocument/didClose":  handleNotification((*server).didClose),
+}
+
+func handleRequest[Params any](handler func(*server, *Params) any) requestHandler {
+	return func(server_ *server, rawParams json.RawMessage) (any, *responseError) {
+		params := new(Params)
+
+		if len(rawParams) > 0 {
+			if err := json.Unmarshal(rawParams, params); err != nil {
+				return nil, &responseError{
+					Code:    invalidParamsCode,
+					Message: err.Error(),
+				}
+			}
+		}
+
+		return handler(server_, params), nil
+	}
+}
+
+// Notifications can't be responded to, so those with invalid parameters are ignored.
+func handleNotification[Params any](handler func(*server, *Params)) notificationHandler {
+	return func(server_ *server, rawParams json.RawMessage) {
+		params := new(Params)
+
+		if json.Unmarshal(rawParams, params) == nil {
+			handler(server_, params)
+		}
+	}
+}
+
+/*
+ * Serve requests read from `reader` until the client exits, returning whether it asked the server
+ * to shut down first (as it should have).
+ */
+func Serve(reader io.Reader, writer io.Writer) bool {
+	server_ := &server{
+		writer:     writer,
+		documents:  map[string]*document{},
+		isShutDown: false,
+	}
+
+	bufferedReader := bufio.NewReader(reader)
+
+	for {
+		message, err := readMessage(bufferedReader)
+
+		if err != nil {
+			return false
+		}
+
+		request_ := &request{}
+
+		if err := json.Unmarshal(message, request_); err != nil {
+			server_.respondWithError(nil, &responseError{
+				Code:    parseErrorCode,
+				Message: err.Error(),
+			})
+
+			continue
+		}
+
+		if request_.Method == "exit" {
+			return server_.isShutDown
+		}
+
+		if request_.ID == nil {
+			if handler, ok := notificationHandlers[request_.Method]; ok {
+				handler(server_, request_.Params)
+			}
+
+			continue
+		}
+
+		handler, ok := requestHandlers[request_.Method]
+
+		if !ok {
+			server_.respondWithError(request_.ID, &responseError{
+				Code:    methodNotFoundCode,
+				Message: "Unsupported method: " + request_.Method,
+			})
+
+			continue
+		}
+
+		if result, err := handler(server_, request_.Params); err != nil {
+			server_.respondWithError(request_.ID, err)
+		} else {
+			server_.send(&response{
+				JSONRPC: "2.0",
+				ID:      request_.ID,
+				Result:  result,
+			})
+		}
+	}
+}
+
+/*
+ * The client can't be told about failures to write to it, so they're ignored; the server will exit
+ * once it fails to read from the client too.
+ */
+func (server_ *server) send(message any) {
+	_ = writeMessage(server_.writer, message)
+}
+
+func (server_ *server) respondWithError(id json.RawMessage, error_ *responseError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	server_.send(&errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   error_,
+	})
+}
+
+func (server_ *server) notify(method string, params any) {
+	server_.send(&notification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
+func (*server) initialize(*struct{}) any {
+	return &initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync:       textDocumentSyncFull,
+			DefinitionProvider:     true,
+			HoverProvider:          true,
+			DocumentSymbolProvider: true,
+			CompletionProvider: completionOptions{
+				TriggerCharacters: []string{"."},
+			},
+		},
+
+		ServerInfo: serverInfo{
+			Name: "krait",
+		},
+	}
+}
+
+func (server_ *server) shutdown(*struct{}) any {
+	server_.isShutDown = true
+
+	return nil
+}
+
+func (server_ *server) didOpen(params *didOpenTextDocumentParams) {
+	server_.updateDocument(params.TextDocument.URI, params.TextDocument.Text)
+}
+
+// Documents are synchronized in full, so the last change holds the document's entire content.
+func (server_ *server) didChange(params *didChangeTextDocumentParams) {
+	if len(params.ContentChanges) > 0 {
+		server_.updateDocument(
+			params.TextDocument.URI,
+			params.ContentChanges[len(params.ContentChanges)-1].Text,
+		)
+	}
+}
+
+func (server_ *server) didClose(params *didCloseTextDocumentParams) {
+	if document_, ok := server_.documents[params.TextDocument.URI]; ok {
+		errors.RemoveSourceOverride(document_.path)
+		delete(server_.documents, params.TextDocument.URI)
+	}
+
+	server_.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+		URI:         params.TextDocument.URI,
+		Diagnostics: []*diagnostic{},
+	})
+}
+
+/*
+ * Errors raised while analyzing a document highlight its unsaved content rather than the file on
+ * disk.
+ */
+func (server_ *server) updateDocument(uri string, source string) {
+	document_ := &document{
+		uri:    uri,
+		path:   pathForURI(uri),
+		source: source,
+	}
+
+	server_.documents[uri] = document_
+	errors.OverrideSource(document_.path, source)
+
+	server_.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics(document_),
+	})
+}
+
+/*
+ * Read a source file, preferring the content of an open document to the file on disk.
+ */
+func (server_ *server) source(path string) (string, bool) {
+	for _, document_ := range server_.documents {
+		if document_.path == path {
+			return document_.source, true
+		}
+	}
+
+	result, err := standard_library.ReadFile(path)
+
+	if err != nil {
+		return "", false
+	}
+
+	return string(result), true
+}
+
+// Documents that aren't files (e.g. unsaved ones) are identified by their URIs instead.
+func pathForURI(uri string) string {
+	parsed, err := url.Parse(uri)
+
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+
+	return parsed.Path
+}
+
+/*
+ * Embedded files can't be opened by the client, so they don't have URIs.
+ */
+func (server_ *server) uri(path string) (string, bool) {
+	if standard_library.IsEmbeddedPath(path) {
+		return "", false
+	}
+
+	for _, document_ := range server_.documents {
+		if document_.path == path {
+			return document_.uri, true
+		}
+	}
+
+	return (&url.URL{
+		Scheme: "file",
+		Path:   path,
+	}).String(), true
+}
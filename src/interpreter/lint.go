@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/linter"
+)
+
+/*
+ * `interpreter lint <path>...` reports likely mistakes in the given Krait files, and those in the
+ * given directories, failing if there are any.
+ */
+func runLintCommand(arguments []string) {
+	if len(arguments) == 0 {
+		errors.RaiseError(entry_errors.LintPathsNotSpecified)
+	}
+
+	problemCount := 0
+
+	for _, path := range kraitFilesInPaths(arguments) {
+		source, err := os.ReadFile(path)
+
+		if err != nil {
+			errors.RaiseError(entry_errors.FileNotOpened(path))
+		}
+
+		for _, problem := range linter.Lint(path, string(source)) {
+			if problemCount > 0 {
+				fmt.Println()
+			}
+
+			fmt.Print(problem.String())
+			problemCount++
+		}
+	}
+
+	if problemCount > 0 {
+		errors.RaiseError(entry_errors.LintProblemsFound(problemCount))
+	}
+}
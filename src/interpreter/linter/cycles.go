@@ -0,0 +1,214 @@
+package linter
+
+import (
+	"sort"
+
+	"project_umbrella/interpreter/errors/lint_errors"
+	"project_umbrella/interpreter/parser"
+)
+
+/*
+ * A named value or function, referring to the declarations evaluated when it's evaluated (or, for a
+ * function, when it's called).
+ */
+type cycleNode struct {
+	name       *parser.Identifier
+	isFunction bool
+	references []*parser.Identifier
+
+	// The node's index in the order it was visited, and the lowest index reachable from it, for
+	// finding strongly connected components
+	index    int
+	lowLink  int
+	isOnPath bool
+}
+
+/*
+ * Functions are hoisted, so values declared before them can call them. If a function refers to such
+ * a value, however, calling it while evaluating the value refers to the value before it's been
+ * assigned: the function refers to itself through the value.
+ *
+ * Cycles are the strongly connected components of the graph of references between declarations
+ * (found using Tarjan's algorithm) containing both a function and a value.
+ */
+func (linter_ *linter) lintValueCycles(root *parser.ExpressionList) {
+	nodes := []*cycleNode{}
+	nodesByName := map[*parser.Identifier]*cycleNode{}
+
+	var collectNodes func(expression parser.Expression)
+
+	addNode := func(name *parser.Identifier, isFunction bool, value parser.Expression) {
+		if !linter_.isInFile(name) || isGeneratedName(name) {
+			return
+		}
+
+		node := &cycleNode{
+			name:       name,
+			isFunction: isFunction,
+			references: linter_.eagerReferences(value, []*parser.Identifier{}),
+			index:      -1,
+			lowLink:    -1,
+			isOnPath:   false,
+		}
+
+		nodes = append(nodes, node)
+		nodesByName[name] = node
+	}
+
+	collectNodes = func(expression parser.Expression) {
+		switch expression := expression.(type) {
+		case *parser.Function:
+			if expression.Name != nil {
+				addNode(expression.Name, true, expression.Body)
+			}
+
+		case *parser.Assignment:
+			if !expression.IsParameter {
+				function, isFunction := expression.Value.(*parser.Function)
+
+				for _, name := range expression.Names() {
+					if isFunction {
+						addNode(name, true, function.Body)
+					} else {
+						addNode(name, false, expression.Value)
+					}
+				}
+			}
+		}
+
+		for _, child := range expression.Children() {
+			collectNodes(child)
+		}
+	}
+
+	collectNodes(root)
+
+	nextIndex := 0
+	path := []*cycleNode{}
+
+	var visit func(node *cycleNode)
+
+	visit = func(node *cycleNode) {
+		node.index = nextIndex
+		node.lowLink = nextIndex
+		node.isOnPath = true
+		nextIndex++
+		path = append(path, node)
+
+		for _, reference := range node.references {
+			referencedNode, ok := nodesByName[reference]
+
+			if !ok {
+				continue
+			}
+
+			if referencedNode.index == -1 {
+				visit(referencedNode)
+				node.lowLink = min(node.lowLink, referencedNode.lowLink)
+			} else if referencedNode.isOnPath {
+				node.lowLink = min(node.lowLink, referencedNode.index)
+			}
+		}
+
+		if node.lowLink != node.index {
+			return
+		}
+
+		component := []*cycleNode{}
+
+		for {
+			member := path[len(path)-1]
+			path = path[:len(path)-1]
+			member.isOnPath = false
+			component = append(component, member)
+
+			if member == node {
+				break
+			}
+		}
+
+		linter_.lintComponent(component)
+	}
+
+	for _, node := range nodes {
+		if node.index == -1 {
+			visit(node)
+		}
+	}
+}
+
+/*
+ * Cycles are reported at their first function, naming the values it refers to itself through.
+ */
+func (linter_ *linter) lintComponent(component []*cycleNode) {
+	var function *cycleNode
+
+	values := []*cycleNode{}
+
+	for _, node := range component {
+		if !node.isFunction {
+			values = append(values, node)
+		} else if function == nil || node.name.Position().Start < function.name.Position().Start {
+			function = node
+		}
+	}
+
+	if function == nil || len(values) == 0 {
+		return
+	}
+
+	sort.Slice(values, func(i int, j int) bool {
+		return values[i].name.Position().Start < values[j].name.Position().Start
+	})
+
+	valueNames := make([]string, 0, len(values))
+
+	for _, value := range values {
+		valueNames = append(valueNames, value.name.Value)
+	}
+
+	linter_.report(
+		lint_errors.ValueCycle(function.name.Value, valueNames),
+		function.name.Position(),
+	)
+}
+
+/*
+ * The declarations referred to when an expression is evaluated. Functions' bodies aren't evaluated
+ * until they're called, unless they're called immediately (e.g. those desugared from `let`
+ * expressions) or are the branches of an `if` expression.
+ */
+func (linter_ *linter) eagerReferences(
+	expression parser.Expression,
+	result []*parser.Identifier,
+) []*parser.Identifier {
+	switch expression := expression.(type) {
+	case *parser.Function:
+		return result
+
+	case *parser.Identifier:
+		if declaration, ok := linter_.translator.Declaration(expression); ok &&
+			declaration != expression {
+			result = append(result, declaration)
+		}
+
+	case *parser.Call:
+		if function, ok := expression.Function.(*parser.Function); ok {
+			result = linter_.eagerReferences(function.Body, result)
+		}
+
+		if expression.IsBuiltInCall("__if_else__") {
+			for _, argument := range expression.Arguments {
+				if branch, ok := argument.(*parser.Function); ok {
+					result = linter_.eagerReferences(branch.Body, result)
+				}
+			}
+		}
+	}
+
+	for _, child := range expression.Children() {
+		result = linter_.eagerReferences(child, result)
+	}
+
+	return result
+}
@@ -0,0 +1,348 @@
+/*
+ * The linter reports likely mistakes that don't prevent Krait source files from being translated:
+ * unused values, parameters and imports, shadowed built-in and standard library values,
+ * unreachable branches, and functions referring to themselves through values.
+ *
+ * Files are translated exactly as they would be when loaded, so that names are resolved the same
+ * way the bytecode translator resolves them. Only declarations in the linted file itself are
+ * reported, and not those in the startup file it's prefixed with.
+ */
+package linter
+
+import (
+	"sort"
+	"strings"
+
+	"project_umbrella/interpreter/bytecode_generator"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/lint_errors"
+	"project_umbrella/interpreter/loader/file_loader"
+	"project_umbrella/interpreter/parser"
+	"project_umbrella/interpreter/parser/parser_types"
+)
+
+type scopeKind int
+
+const (
+	// Declarations are exported, unless they're private.
+	moduleScope scopeKind = iota
+	functionScope
+
+	// Declarations are the fields of a struct or trait.
+	fieldScope
+)
+
+type linter struct {
+	path       string
+	source     string
+	translator *bytecode_generator.BytecodeTranslator
+
+	// Declarations referred to by identifiers other than their own
+	usedDeclarations map[*parser.Identifier]bool
+
+	// The names declared by the startup file, which are in scope in every module
+	startupNames map[string]bool
+
+	// Calls to `__if_else__` desugared from `else if` branches, which are linted with their `if`
+	elseIfs map[*parser.Call]bool
+
+	problems []*errors.PositionalError
+}
+
+/*
+ * Lint the file at `path`, were it to contain `source`, returning the problems found ordered by
+ * position. If the file can't be translated, only the error preventing it is returned.
+ */
+func Lint(path string, source string) []*errors.Error {
+	linter_ := &linter{
+		path:             path,
+		source:           source,
+		translator:       bytecode_generator.NewBytecodeTranslator(source),
+		usedDeclarations: map[*parser.Identifier]bool{},
+		startupNames:     map[string]bool{},
+		elseIfs:          map[*parser.Call]bool{},
+		problems:         []*errors.PositionalError{},
+	}
+
+	var root *parser.ExpressionList
+
+	if err := errors.Catch(func() {
+		root = file_loader.ModuleExpressionList(path, source)
+		linter_.translator.ExpressionToBytecode(root)
+	}); err != nil {
+		return []*errors.Error{err}
+	}
+
+	linter_.collectUsedDeclarations(root)
+	linter_.collectStartupNames(root)
+	linter_.lintExpressionList(root, moduleScope)
+	linter_.lintValueCycles(root)
+
+	sort.SliceStable(linter_.problems, func(i int, j int) bool {
+		return linter_.problems[i].Position.Start < linter_.problems[j].Position.Start
+	})
+
+	result := make([]*errors.Error, 0, len(linter_.problems))
+
+	for _, problem := range linter_.problems {
+		result = append(result, problem.Highlighted())
+	}
+
+	return result
+}
+
+func (linter_ *linter) report(error_ *errors.Error, position *errors.Position) {
+	linter_.problems = append(linter_.problems, &errors.PositionalError{
+		Error:    error_,
+		Position: position,
+	})
+}
+
+func (linter_ *linter) isInFile(expression parser.Expression) bool {
+	position := expression.Position()
+
+	return position != nil && position.Filename == linter_.path
+}
+
+func (linter_ *linter) collectUsedDeclarations(expression parser.Expression) {
+	if identifier, ok := expression.(*parser.Identifier); ok {
+		if declaration, ok := linter_.translator.Declaration(identifier); ok && declaration != identifier {
+			linter_.usedDeclarations[declaration] = true
+		}
+	}
+
+	for _, child := range expression.Children() {
+		linter_.collectUsedDeclarations(child)
+	}
+}
+
+func (linter_ *linter) collectStartupNames(root *parser.ExpressionList) {
+	for _, expression := range root.Children_ {
+		if declaration, ok := expression.(parser.Declaration); ok {
+			for _, name := range declaration.Names() {
+				if name.Position() != nil && !linter_.isInFile(name) {
+					linter_.startupNames[name.Value] = true
+				}
+			}
+		}
+	}
+}
+
+func (linter_ *linter) lintExpressionList(expressionList *parser.ExpressionList, kind scopeKind) {
+	for _, expression := range expressionList.Children_ {
+		switch expression := expression.(type) {
+		case *parser.Assignment:
+			linter_.lintAssignment(expression, kind)
+
+		case *parser.Function:
+			if expression.Name != nil {
+				linter_.lintDeclaration(expression.Name, kind)
+			}
+
+			linter_.lintExpression(expression)
+
+		default:
+			linter_.lintExpression(expression)
+		}
+	}
+}
+
+func (linter_ *linter) lintAssignment(assignment *parser.Assignment, kind scopeKind) {
+	if defaultFieldFactory, ok := assignment.TraitDefaultFieldFactory(); ok {
+		linter_.lintDeclaration(assignment.Names_[0], kind)
+
+		for _, parameter := range defaultFieldFactory.Parameters {
+			linter_.lintShadowing(parameter)
+		}
+
+		linter_.lintExpressionList(defaultFieldFactory.Body, fieldScope)
+
+		return
+	}
+
+	isImport := assignment.IsFromImport() || linter_.isImportCall(assignment.Value)
+
+	for _, name := range assignment.Names() {
+		if assignment.IsParameter {
+			linter_.lintParameter(name)
+		} else if isImport {
+			linter_.lintImport(name)
+		} else {
+			linter_.lintDeclaration(name, kind)
+		}
+	}
+
+	linter_.lintExpression(assignment.Value)
+}
+
+func (linter_ *linter) lintExpression(expression parser.Expression) {
+	switch expression := expression.(type) {
+	case *parser.Function:
+		linter_.lintFunction(expression)
+
+		return
+
+	case *parser.Call:
+		if expression.IsBuiltInCall("__if_else__") && len(expression.Arguments) == 3 {
+			linter_.lintIfElse(expression)
+		}
+	}
+
+	for _, child := range expression.Children() {
+		linter_.lintExpression(child)
+	}
+}
+
+/*
+ * The parameters of structs are their fields, and are therefore used by any code selecting them
+ * from instances.
+ */
+func (linter_ *linter) lintFunction(function *parser.Function) {
+	if fieldFactory, ok := function.StructFieldFactory(); ok {
+		for _, parameter := range function.Parameters {
+			linter_.lintShadowing(parameter)
+		}
+
+		linter_.lintShadowing(fieldFactory.Parameters[0])
+		linter_.lintExpressionList(fieldFactory.Body, fieldScope)
+
+		return
+	}
+
+	for _, parameter := range function.Parameters {
+		linter_.lintParameter(parameter)
+	}
+
+	linter_.lintExpressionList(function.Body, functionScope)
+}
+
+/*
+ * Names generated by desugaring (e.g. the placeholders of destructured parameters) are wrapped in
+ * parentheses, so they can't be written in the source.
+ */
+func isGeneratedName(name *parser.Identifier) bool {
+	return strings.HasPrefix(name.Value, "(")
+}
+
+/*
+ * Exported values might be used by other modules, and fields by code selecting them from instances,
+ * so neither are reported when unused. Names starting with an underscore are deliberately unused,
+ * except at the top level, where they're private.
+ */
+func (linter_ *linter) lintDeclaration(name *parser.Identifier, kind scopeKind) {
+	if isGeneratedName(name) || !linter_.isInFile(name) || name.Value == "_" {
+		return
+	}
+
+	linter_.lintShadowing(name)
+
+	if linter_.usedDeclarations[name] {
+		return
+	}
+
+	if (kind == moduleScope && parser_types.IsPrivateName(name.Value)) ||
+		(kind == functionScope && !strings.HasPrefix(name.Value, "_")) {
+		linter_.report(lint_errors.UnusedValue(name.Value), name.Position())
+	}
+}
+
+func (linter_ *linter) lintParameter(parameter *parser.Identifier) {
+	if isGeneratedName(parameter) || !linter_.isInFile(parameter) {
+		return
+	}
+
+	linter_.lintShadowing(parameter)
+
+	if !linter_.usedDeclarations[parameter] && !strings.HasPrefix(parameter.Value, "_") {
+		linter_.report(lint_errors.UnusedParameter(parameter.Value), parameter.Position())
+	}
+}
+
+func (linter_ *linter) lintImport(name *parser.Identifier) {
+	if isGeneratedName(name) || !linter_.isInFile(name) {
+		return
+	}
+
+	linter_.lintShadowing(name)
+
+	if !linter_.usedDeclarations[name] {
+		linter_.report(lint_errors.UnusedImport(name.Value), name.Position())
+	}
+}
+
+func (linter_ *linter) lintShadowing(name *parser.Identifier) {
+	if !linter_.isInFile(name) {
+		return
+	}
+
+	if linter_.startupNames[name.Value] || bytecode_generator.IsBuiltInName(name.Value) {
+		linter_.report(lint_errors.ValueShadowed(name.Value), name.Position())
+	}
+}
+
+func (linter_ *linter) isBuiltIn(expression parser.Expression, name string) bool {
+	identifier, ok := expression.(*parser.Identifier)
+
+	if !ok || identifier.Value != name {
+		return false
+	}
+
+	_, isDeclared := linter_.translator.Declaration(identifier)
+
+	return !isDeclared
+}
+
+func (linter_ *linter) isImportCall(expression parser.Expression) bool {
+	call, ok := expression.(*parser.Call)
+
+	return ok && (linter_.isBuiltIn(call.Function, "import") ||
+		linter_.isBuiltIn(call.Function, "import_library"))
+}
+
+/*
+ * `else if` branches are desugared into `else` functions whose bodies consist only of another call
+ * to `__if_else__` (see `ConcreteIf.Abstract`). Unlike an `if` nested in an `else` branch, the call's
+ * position begins at the `else`.
+ */
+func (linter_ *linter) elseIf(call *parser.Call) (*parser.Call, bool) {
+	elseFunction, ok := call.Arguments[2].(*parser.Function)
+
+	if !ok || len(elseFunction.Body.Children_) != 1 {
+		return nil, false
+	}
+
+	result, ok := elseFunction.Body.Children_[0].(*parser.Call)
+
+	if !ok || !result.IsBuiltInCall("__if_else__") || len(result.Arguments) != 3 ||
+		!linter_.isInFile(result) {
+		return nil, false
+	}
+
+	return result, strings.HasPrefix(linter_.source[result.Position().Start:], "else")
+}
+
+func (linter_ *linter) lintIfElse(call *parser.Call) {
+	if linter_.elseIfs[call] || !linter_.isInFile(call) {
+		return
+	}
+
+	isPreviousConditionTrue := linter_.isBuiltIn(call.Arguments[0], "true")
+
+	for elseIf, ok := linter_.elseIf(call); ok; elseIf, ok = linter_.elseIf(elseIf) {
+		linter_.elseIfs[elseIf] = true
+		condition := elseIf.Arguments[0]
+		position := condition.Position()
+
+		if position == nil {
+			position = elseIf.Position()
+		}
+
+		if isPreviousConditionTrue {
+			linter_.report(lint_errors.BranchUnreachableAfterTrueCondition, position)
+		} else if linter_.isBuiltIn(condition, "false") {
+			linter_.report(lint_errors.BranchUnreachableWithFalseCondition, position)
+		}
+
+		isPreviousConditionTrue = isPreviousConditionTrue || linter_.isBuiltIn(condition, "true")
+	}
+}
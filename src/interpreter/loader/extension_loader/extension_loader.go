@@ -0,0 +1,441 @@
+package extension_loader
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/puzpuzpuz/xsync/v3"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/runtime_errors"
+	"project_umbrella/interpreter/parser/parser_types"
+	"project_umbrella/interpreter/runtime"
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types/function"
+	"project_umbrella/interpreter/runtime/value_types/library"
+)
+
+/*
+ * The version of the protocol described in `docs/EXTENSIONS.md`.
+ */
+const protocolVersion = 1
+
+const maximumMessageSize = 64 * 1024 * 1024
+
+// How long an extension has to respond to `initialize` before it's stopped
+const initializeTimeout = 10 * time.Second
+
+/*
+ * Extensions are started at most once per interpreter, no matter how many times they're imported,
+ * unless they're unloaded. The mutex only guards the map, so that extensions are started (and
+ * initialized) concurrently.
+ */
+var (
+	extensions      = map[string]*loadedExtension{}
+	extensionsMutex sync.Mutex
+)
+
+// Its other fields are set once `ready` is closed.
+type loadedExtension struct {
+	ready     chan struct{}
+	extension *extension
+	library   *library.Library
+	err       *errors.Error
+}
+
+type extension struct {
+	path       string
+	process    *os.Process
+	stdin      io.WriteCloser
+	stdinMutex sync.Mutex
+	nextID     atomic.Int64
+
+	/*
+	 * Calls awaiting a response, keyed by request ID. `exited` is closed once the extension's
+	 * stdout is closed, after which no more responses will arrive.
+	 */
+	pendingCalls *xsync.MapOf[int64, chan *response]
+	exited       chan struct{}
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type response struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type initializeParams struct {
+	ProtocolVersion int `json:"protocol_version"`
+}
+
+type initializeResult struct {
+	Symbols map[string]*symbol `json:"symbols"`
+}
+
+/*
+ * `Kind` is either "function" or "value", in which case `Value` holds the symbol's value.
+ */
+type symbol struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+type callParams struct {
+	Symbol    string `json:"symbol"`
+	Arguments []any  `json:"arguments"`
+}
+
+/*
+ * Start the executable at `path` as an out-of-process extension and fetch the symbols it exports.
+ * Each exported function becomes a built-in function forwarding its calls to the extension.
+ */
+func LoadExtension(path string) *library.Library {
+	extensionsMutex.Lock()
+	loaded, ok := extensions[path]
+
+	if !ok {
+		loaded = &loadedExtension{
+			ready:     make(chan struct{}),
+			extension: nil,
+			library:   nil,
+			err:       nil,
+		}
+
+		extensions[path] = loaded
+	}
+
+	extensionsMutex.Unlock()
+
+	if ok {
+		<-loaded.ready
+	} else {
+		loadExtension(path, loaded)
+	}
+
+	if loaded.err != nil {
+		errors.RaiseError(loaded.err)
+	}
+
+	return loaded.library
+}
+
+/*
+ * Start and initialize the extension at `path`, storing the result in `loaded`. Extensions that
+ * fail to start or initialize are killed and forgotten, so that importing them again retries.
+ */
+func loadExtension(path string, loaded *loadedExtension) {
+	defer close(loaded.ready)
+
+	var extension_ *extension
+
+	loaded.err = errors.Catch(func() {
+		extension_ = startExtension(path)
+		loaded.library = extension_.library()
+		loaded.extension = extension_
+	})
+
+	if loaded.err == nil {
+		return
+	}
+
+	if extension_ != nil {
+		extension_.process.Kill()
+	}
+
+	extensionsMutex.Lock()
+	defer extensionsMutex.Unlock()
+
+	if extensions[path] == loaded {
+		delete(extensions, path)
+	}
+}
+
+/*
+ * Stop the extension at `path` (if it was started) by closing its stdin, so that it's started
+ * again the next time it's imported (e.g. after it's rebuilt).
+ */
+func UnloadExtension(path string) {
+	extensionsMutex.Lock()
+	loaded, ok := extensions[path]
+	delete(extensions, path)
+	extensionsMutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	<-loaded.ready
+
+	if loaded.extension != nil {
+		loaded.extension.stdinMutex.Lock()
+		defer loaded.extension.stdinMutex.Unlock()
+
+		loaded.extension.stdin.Close()
+	}
+}
+
+func startExtension(path string) *extension {
+	absolutePath, err := filepath.Abs(path)
+
+	if err != nil {
+		errors.RaiseError(runtime_errors.ExtensionNotStarted(path, err))
+	}
+
+	// Extensions share the interpreter's stderr, so they can report problems themselves.
+	command := exec.Command(absolutePath)
+	command.Stderr = os.Stderr
+
+	stdin, err := command.StdinPipe()
+
+	if err != nil {
+		errors.RaiseError(runtime_errors.ExtensionNotStarted(path, err))
+	}
+
+	stdout, err := command.StdoutPipe()
+
+	if err != nil {
+		errors.RaiseError(runtime_errors.ExtensionNotStarted(path, err))
+	}
+
+	if err := command.Start(); err != nil {
+		errors.RaiseError(runtime_errors.ExtensionNotStarted(path, err))
+	}
+
+	extension_ := &extension{
+		path:         path,
+		process:      command.Process,
+		stdin:        stdin,
+		pendingCalls: xsync.NewMapOf[int64, chan *response](),
+		exited:       make(chan struct{}),
+	}
+
+	go extension_.readResponses(stdout, command)
+
+	return extension_
+}
+
+/*
+ * Dispatch each response to the call awaiting it. Responses to unknown requests are ignored.
+ */
+func (extension_ *extension) readResponses(stdout io.Reader, command *exec.Cmd) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(nil, maximumMessageSize)
+
+	for scanner.Scan() {
+		response_ := &response{}
+
+		if err := json.Unmarshal(scanner.Bytes(), response_); err != nil {
+			continue
+		}
+
+		if responseChannel, ok := extension_.pendingCalls.LoadAndDelete(response_.ID); ok {
+			responseChannel <- response_
+		}
+	}
+
+	close(extension_.exited)
+	command.Wait()
+}
+
+/*
+ * Send a request to the extension and wait for its response, for at most `timeout` (or
+ * indefinitely if it's 0). Requests may be sent concurrently; responses are matched to them by ID.
+ */
+func (extension_ *extension) call(method string, params any, timeout time.Duration) *response {
+	id := extension_.nextID.Add(1)
+	responseChannel := make(chan *response, 1)
+
+	extension_.pendingCalls.Store(id, responseChannel)
+
+	message, err := json.Marshal(&request{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+
+	if err != nil {
+		panic(err)
+	}
+
+	extension_.stdinMutex.Lock()
+	_, err = extension_.stdin.Write(append(message, '\n'))
+	extension_.stdinMutex.Unlock()
+
+	if err != nil {
+		extension_.pendingCalls.Delete(id)
+		errors.RaiseError(runtime_errors.ExtensionExited(extension_.path))
+	}
+
+	var timeoutChannel <-chan time.Time
+
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		timeoutChannel = timer.C
+	}
+
+	select {
+	case response_ := <-responseChannel:
+		return response_
+
+	case <-timeoutChannel:
+		extension_.pendingCalls.Delete(id)
+		errors.RaiseError(
+			runtime_errors.ExtensionProtocolViolated(
+				extension_.path,
+				fmt.Sprintf("It didn't respond to `%s` within %s.", method, timeout),
+			),
+		)
+
+	case <-extension_.exited:
+		select {
+		case response_ := <-responseChannel:
+			return response_
+
+		default:
+			errors.RaiseError(runtime_errors.ExtensionExited(extension_.path))
+		}
+	}
+
+	return nil
+}
+
+func (extension_ *extension) library() *library.Library {
+	response_ := extension_.call(
+		"initialize",
+		&initializeParams{
+			ProtocolVersion: protocolVersion,
+		},
+
+		initializeTimeout,
+	)
+
+	if response_.Error != nil {
+		errors.RaiseError(
+			runtime_errors.ExtensionProtocolViolated(
+				extension_.path,
+				fmt.Sprintf("Initialization failed: %s", response_.Error.Message),
+			),
+		)
+	}
+
+	result := &initializeResult{}
+
+	if err := json.Unmarshal(response_.Result, result); err != nil {
+		errors.RaiseError(
+			runtime_errors.ExtensionProtocolViolated(
+				extension_.path,
+				fmt.Sprintf("The result of `initialize` is malformed: %s.", err),
+			),
+		)
+	}
+
+	fields := make(map[string]value.Value, len(result.Symbols))
+
+	for name, symbol_ := range result.Symbols {
+		switch symbol_.Kind {
+		case "function":
+			fields[name] = extension_.function(name)
+
+		case "value":
+			fields[name] = extension_.valueFromJSON(symbol_.Value)
+
+		default:
+			errors.RaiseError(
+				runtime_errors.ExtensionProtocolViolated(
+					extension_.path,
+					fmt.Sprintf("The symbol \"%s\" has an unknown kind: \"%s\".", name, symbol_.Kind),
+				),
+			)
+		}
+	}
+
+	return &library.Library{
+		Path: extension_.path,
+		GetField: func(name string) (value.Value, bool) {
+			result, ok := fields[name]
+
+			return result, ok
+		},
+
+		SymbolNames: library.SortedSymbolNames(fields),
+		HasSymbol:   nil,
+	}
+}
+
+func (extension_ *extension) function(name string) *function.Function {
+	return function.NewBuiltInFunction(
+		function.NewVariadicFunctionArgumentValidator(name, nil),
+		func(_ *runtime.Runtime, arguments ...value.Value) value.Value {
+			serializedArguments := make([]any, 0, len(arguments))
+
+			for i, argument := range arguments {
+				serializedArgument, ok := valueToJSON(argument)
+
+				if !ok {
+					errors.RaiseError(runtime_errors.ExtensionArgumentNotSerializable(name, i))
+				}
+
+				serializedArguments = append(serializedArguments, serializedArgument)
+			}
+
+			// Calls may legitimately take any amount of time, so they aren't timed out.
+			response_ := extension_.call(
+				"call",
+				&callParams{
+					Symbol:    name,
+					Arguments: serializedArguments,
+				},
+
+				0,
+			)
+
+			if response_.Error != nil {
+				errors.RaiseError(
+					runtime_errors.ExtensionCallFailed(extension_.path, name, response_.Error.Message),
+				)
+			}
+
+			return extension_.valueFromJSON(response_.Result)
+		},
+
+		parser_types.NormalFunction,
+	)
+}
+
+func (extension_ *extension) valueFromJSON(message json.RawMessage) value.Value {
+	result, err := valueFromJSON(message)
+
+	if err != nil {
+		errors.RaiseError(
+			runtime_errors.ExtensionProtocolViolated(
+				extension_.path,
+				fmt.Sprintf("A value sent by the extension is malformed: %s.", err),
+			),
+		)
+	}
+
+	return result
+}
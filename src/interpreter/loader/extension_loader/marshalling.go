@@ -0,0 +1,148 @@
+package extension_loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types"
+)
+
+/*
+ * Integers and floats become JSON numbers, strings become JSON strings, booleans become JSON
+ * booleans, tuples become arrays and units become `null`. No other values can be sent to
+ * extensions.
+ */
+func valueToJSON(value_ value.Value) (any, bool) {
+	switch value_ := value_.(type) {
+	case value_types.IntegerValue:
+		return int64(value_), true
+
+	case value_types.FloatValue:
+		return float64(value_), true
+
+	case value_types.StringValue:
+		return string(value_), true
+
+	case value_types.BooleanValue:
+		return bool(value_), true
+
+	case value_types.UnitValue:
+		return nil, true
+
+	case *value_types.TupleValue:
+		result := make([]any, 0, len(value_.Elements))
+
+		for _, element := range value_.Elements {
+			serializedElement, ok := valueToJSON(element)
+
+			if !ok {
+				return nil, false
+			}
+
+			result = append(result, serializedElement)
+		}
+
+		return result, true
+	}
+
+	return nil, false
+}
+
+/*
+ * The inverse of `valueToJSON`, where numbers written with a fraction or exponent become floats.
+ * Objects become tuples of key-value pairs sorted by key.
+ */
+func valueFromJSON(message json.RawMessage) (value.Value, error) {
+	if len(message) == 0 {
+		return value_types.UnitValue{}, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.UseNumber()
+
+	var decoded any
+
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return valueFromDecodedJSON(decoded)
+}
+
+func valueFromDecodedJSON(decoded any) (value.Value, error) {
+	switch decoded := decoded.(type) {
+	case nil:
+		return value_types.UnitValue{}, nil
+
+	case bool:
+		return value_types.BooleanValue(decoded), nil
+
+	case string:
+		return value_types.StringValue(decoded), nil
+
+	case json.Number:
+		if !strings.ContainsAny(decoded.String(), ".eE") {
+			if result, err := decoded.Int64(); err == nil {
+				return value_types.IntegerValue(result), nil
+			}
+		}
+
+		result, err := decoded.Float64()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return value_types.FloatValue(result), nil
+
+	case []any:
+		elements := make([]value.Value, 0, len(decoded))
+
+		for _, decodedElement := range decoded {
+			element, err := valueFromDecodedJSON(decodedElement)
+
+			if err != nil {
+				return nil, err
+			}
+
+			elements = append(elements, element)
+		}
+
+		return &value_types.TupleValue{
+			Elements: elements,
+		}, nil
+
+	case map[string]any:
+		keys := make([]string, 0, len(decoded))
+
+		for key := range decoded {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		pairs := make([]value.Value, 0, len(keys))
+
+		for _, key := range keys {
+			element, err := valueFromDecodedJSON(decoded[key])
+
+			if err != nil {
+				return nil, err
+			}
+
+			pairs = append(pairs, &value_types.TupleValue{
+				Elements: []value.Value{value_types.StringValue(key), element},
+			})
+		}
+
+		return &value_types.TupleValue{
+			Elements: pairs,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected JSON value: %v", decoded)
+}
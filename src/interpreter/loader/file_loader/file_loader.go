@@ -1,21 +1,19 @@
 package file_loader
 
 import (
-	"os"
 	"strings"
 
-	"github.com/alecthomas/participle/v2"
-
 	"project_umbrella/interpreter/bytecode_generator"
 	"project_umbrella/interpreter/common"
 	"project_umbrella/interpreter/environment_variables"
 	"project_umbrella/interpreter/errors"
 	"project_umbrella/interpreter/errors/entry_errors"
-	"project_umbrella/interpreter/errors/parser_errors"
 	"project_umbrella/interpreter/loader"
 	"project_umbrella/interpreter/parser"
+	"project_umbrella/interpreter/runtime"
 	"project_umbrella/interpreter/runtime/runtime_executor"
 	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/standard_library"
 )
 
 func expressionListFromSource(
@@ -23,96 +21,98 @@ func expressionListFromSource(
 	source string,
 	loaderChannel *loader.LoaderChannel,
 ) *parser.ExpressionList {
-	concreteResult, err := parser.ParseString(path, source)
+	return parser.ParseSource(path, source).AbstractExpressionList()
+}
 
-	if err != nil {
-		var participleError participle.Error
-		var participleErrorPosition *errors.Position
-
-		switch err := err.(type) {
-		case *participle.ParseError:
-			participleError = err
-			participleErrorPosition = &errors.Position{
-				Filename: path,
-				Start:    err.Pos.Offset,
-				End:      err.Pos.Offset + 1,
-			}
-
-		case *participle.UnexpectedTokenError:
-			participleError = err
-			participleErrorPosition = &errors.Position{
-				Filename: path,
-				Start:    err.Unexpected.Pos.Offset,
-				End:      err.Unexpected.Pos.Offset + len(err.Unexpected.Value),
-			}
-
-		default:
-			panic(err)
+/*
+ * The startup file whose statements precede those of the file at `sourcePath`, unless there isn't
+ * one or the file is exempt from it (i.e. embedded or in a directory in `KRAIT_STARTUP_EXCLUDE`).
+ */
+func StartupFilePath(sourcePath string) (string, bool) {
+	excludedDirectories := strings.Split(environment_variables.KRAIT_STARTUP_EXCLUDE, ":")
+
+	if standard_library.IsEmbeddedPath(sourcePath) {
+		return "", false
+	}
+
+	for _, excludedDirectory := range excludedDirectories {
+		if excludedDirectory != "" &&
+			common.IsDirectoryAncestorOfFile(excludedDirectory, sourcePath) {
+			return "", false
 		}
+	}
 
-		errors.RaisePositionalError(
-			&errors.PositionalError{
-				Error:    parser_errors.ParserFailed(participleError),
-				Position: participleErrorPosition,
-			},
-		)
+	if environment_variables.KRAIT_STARTUP == "" {
+		return "", false
 	}
 
-	return concreteResult.AbstractExpressionList()
+	return environment_variables.KRAIT_STARTUP, true
 }
 
 func expressionListFromStartupFile(
 	sourcePath string,
 	loaderChannel *loader.LoaderChannel,
 ) *parser.ExpressionList {
-	excludedDirectories := strings.Split(environment_variables.KRAIT_STARTUP_EXCLUDE, ":")
-	emptyResult := &parser.ExpressionList{
-		Children_: []parser.Expression{},
-	}
+	startupFilePath, ok := StartupFilePath(sourcePath)
 
-	for _, excludedDirectory := range excludedDirectories {
-		if excludedDirectory != "" &&
-			common.IsDirectoryAncestorOfFile(excludedDirectory, sourcePath) {
-			return &parser.ExpressionList{
-				Children_: []parser.Expression{},
-			}
+	if !ok {
+		return &parser.ExpressionList{
+			Children_: []parser.Expression{},
 		}
 	}
 
-	if environment_variables.KRAIT_STARTUP == "" {
-		return emptyResult
-	}
-
-	startupFileContent, err := os.ReadFile(environment_variables.KRAIT_STARTUP)
+	startupFileContent, err := standard_library.ReadFile(startupFilePath)
 
 	if err != nil {
-		errors.RaiseError(entry_errors.StartupFileNotOpened(environment_variables.KRAIT_STARTUP))
+		errors.RaiseError(entry_errors.StartupFileNotOpened(startupFilePath))
 	}
 
-	return expressionListFromSource(
-		environment_variables.KRAIT_STARTUP,
-		string(startupFileContent),
-		loaderChannel,
-	)
+	return expressionListFromSource(startupFilePath, string(startupFileContent), loaderChannel)
 }
 
-func LoadFile(path string, loaderChannel *loader.LoaderChannel) value.Value {
-	fileContentByteSlice, err := os.ReadFile(path)
+// Run the file at `path`, observed by `instruments`.
+func LoadFile(
+	path string,
+	loaderChannel *loader.LoaderChannel,
+	instruments runtime.Instruments,
+) value.Value {
+	fileContentByteSlice, err := standard_library.ReadFile(path)
 
 	if err != nil {
 		errors.RaiseError(entry_errors.FileNotOpened(path))
 	}
 
 	fileContent := string(fileContentByteSlice)
-	expressionList := (&parser.ExpressionList{
+
+	return runtime_executor.ExecuteBytecode(
+		path,
+		bytecode_generator.ExpressionToBytecodeFromCache(
+			moduleExpressionList(path, fileContent, loaderChannel),
+			fileContent,
+		),
+
+		loaderChannel,
+		instruments,
+	)
+}
+
+func moduleExpressionList(
+	path string,
+	source string,
+	loaderChannel *loader.LoaderChannel,
+) *parser.ExpressionList {
+	return (&parser.ExpressionList{
 		Children_: append(
 			expressionListFromStartupFile(path, loaderChannel).Children_,
-			expressionListFromSource(path, fileContent, loaderChannel).Children_...,
+			expressionListFromSource(path, source, loaderChannel).Children_...,
 		),
 	}).ToModule()
+}
 
-	return runtime_executor.ExecuteBytecode(
-		bytecode_generator.ExpressionToBytecodeFromCache(expressionList, fileContent),
-		loaderChannel,
-	)
+/*
+ * The expression list the file at `path` is translated into, were it to contain `source`: the
+ * startup file's statements followed by its own, evaluating to a module of its declarations.
+ */
+func ModuleExpressionList(path string, source string) *parser.ExpressionList {
+	return moduleExpressionList(path, source, nil)
 }
@@ -3,6 +3,8 @@ package library_loader
 import (
 	"plugin"
 	"reflect"
+	"slices"
+	"strings"
 
 	"project_umbrella/interpreter/errors"
 	"project_umbrella/interpreter/errors/runtime_errors"
@@ -10,11 +12,56 @@ import (
 	"project_umbrella/interpreter/runtime/value_types/library"
 )
 
+/*
+ * Substrings of the errors returned by `plugin.Open` (which come from the dynamic loader and Go's
+ * runtime) paired with explanations of their usual cause.
+ */
+var openFailureReasons = []struct {
+	errorSubstrings []string
+	reason          string
+}{
+	{
+		errorSubstrings: []string{"different version of package", "plugin already loaded"},
+		reason: "The library was built with a different version of the interpreter or one of its " +
+			"dependencies.",
+	},
+
+	{
+		errorSubstrings: []string{
+			"wrong ELF class",
+			"invalid ELF header",
+			"file too short",
+			"exec format error",
+			"incompatible architecture",
+			"not a mach-o file",
+		},
+
+		reason: "The library isn't a shared object built for this platform.",
+	},
+
+	{
+		errorSubstrings: []string{"undefined symbol", "could not find symbol", "symbol not found"},
+		reason:          "The library refers to a symbol that doesn't exist.",
+	},
+}
+
+func openFailureReason(err error) string {
+	for _, candidate := range openFailureReasons {
+		for _, substring := range candidate.errorSubstrings {
+			if strings.Contains(err.Error(), substring) {
+				return candidate.reason
+			}
+		}
+	}
+
+	return "The library couldn't be loaded."
+}
+
 func LoadLibrary(path string) *library.Library {
 	plugin_, err := plugin.Open(path)
 
 	if err != nil {
-		panic(err)
+		errors.RaiseError(runtime_errors.LibraryNotOpened(path, openFailureReason(err), err))
 	}
 
 	return &library.Library{
@@ -26,17 +73,54 @@ func LoadLibrary(path string) *library.Library {
 				return nil, false
 			}
 
+			/*
+			 * Looking up a variable yields a pointer to it, whereas looking up a function yields the
+			 * function itself.
+			 */
 			symbolValue := reflect.ValueOf(symbol)
 
 			if symbolValue.Kind() == reflect.Pointer {
-				if symbolDereferencedValue, ok := symbolValue.Elem().Interface().(value.Value); ok {
-					return symbolDereferencedValue, true
-				}
+				symbolValue = symbolValue.Elem()
+			}
+
+			if result, ok := goToValue(path, name, symbolValue); ok {
+				return result, true
 			}
 
 			errors.RaiseError(runtime_errors.LibrarySymbolNotValue(path, name))
 
 			return nil, false
 		},
+
+		SymbolNames: pluginSymbolNames(plugin_),
+		HasSymbol: func(name string) bool {
+			_, err := plugin_.Lookup(name)
+
+			return err == nil
+		},
+	}
+}
+
+/*
+ * The `plugin` package doesn't expose the names of a plugin's symbols, so they're read from the
+ * unexported map it looks symbols up in. Should that map ever disappear, plugins appear to export
+ * nothing when listed, though their symbols can still be checked for and fetched.
+ */
+func pluginSymbolNames(plugin_ *plugin.Plugin) []string {
+	symbols := reflect.ValueOf(plugin_).Elem().FieldByName("syms")
+
+	if !symbols.IsValid() || symbols.Kind() != reflect.Map ||
+		symbols.Type().Key().Kind() != reflect.String {
+		return []string{}
+	}
+
+	result := make([]string, 0, symbols.Len())
+
+	for _, key := range symbols.MapKeys() {
+		result = append(result, key.String())
 	}
+
+	slices.Sort(result)
+
+	return result
 }
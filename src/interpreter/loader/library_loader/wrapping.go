@@ -0,0 +1,508 @@
+package library_loader
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/runtime_errors"
+	"project_umbrella/interpreter/loader"
+	"project_umbrella/interpreter/parser/parser_types"
+	"project_umbrella/interpreter/runtime"
+	"project_umbrella/interpreter/runtime/built_in_definitions"
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types"
+	"project_umbrella/interpreter/runtime/value_types/function"
+	"project_umbrella/interpreter/standard_library"
+)
+
+var valueType = reflect.TypeOf((*value.Value)(nil)).Elem()
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+/*
+ * Convert a Go value exported by a library into a Krait value:
+ *
+ *   - Values implementing `value.Value` are used as is
+ *   - Booleans, integers, floats and strings become their Krait equivalents
+ *   - Slices and arrays become tuples, and maps become tuples of key-value pairs sorted by key
+ *   - Structs become lookup functions of their exported fields, named in snake case unless
+ *     overridden by a `krait:"name"` tag
+ *   - Functions are wrapped by `wrapFunction`
+ *   - Pointers and interfaces are dereferenced, with `nil` becoming unit
+ *
+ * Values referring to themselves (like a cyclic linked list) have no Krait equivalent, so they
+ * raise an error.
+ */
+func goToValue(libraryPath string, name string, goValue reflect.Value) (value.Value, bool) {
+	return goToValueWithAncestors(libraryPath, name, goValue, map[goReference]struct{}{})
+}
+
+/*
+ * A pointer, map or slice, identifying the value it refers to. The type distinguishes a struct from
+ * its first field, and the length distinguishes slices sharing an array.
+ */
+type goReference struct {
+	pointer uintptr
+	type_   reflect.Type
+	length  int
+}
+
+/*
+ * Like `goToValue`, but raise an error if `goValue` is one of `ancestors`, the references converted
+ * to reach it.
+ */
+func goToValueWithAncestors(
+	libraryPath string,
+	name string,
+	goValue reflect.Value,
+	ancestors map[goReference]struct{},
+) (value.Value, bool) {
+	if !goValue.IsValid() {
+		return value_types.UnitValue{}, true
+	}
+
+	if goValue.Type().Implements(valueType) && goValue.CanInterface() {
+		if result, ok := goValue.Interface().(value.Value); ok && result != nil {
+			return result, true
+		}
+	}
+
+	switch goValue.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if goValue.IsNil() {
+			break
+		}
+
+		reference := goReference{
+			pointer: goValue.Pointer(),
+			type_:   goValue.Type(),
+			length:  0,
+		}
+
+		if goValue.Kind() == reflect.Slice {
+			reference.length = goValue.Len()
+		}
+
+		if _, ok := ancestors[reference]; ok {
+			errors.RaiseError(
+				runtime_errors.LibraryValueNotConvertible(libraryPath, name, goValue.Type().String()),
+			)
+		}
+
+		ancestors[reference] = struct{}{}
+		defer delete(ancestors, reference)
+	}
+
+	switch goValue.Kind() {
+	case reflect.Bool:
+		return value_types.BooleanValue(goValue.Bool()), true
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value_types.IntegerValue(goValue.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value_types.IntegerValue(goValue.Uint()), true
+
+	case reflect.Float32, reflect.Float64:
+		return value_types.FloatValue(goValue.Float()), true
+
+	case reflect.String:
+		return value_types.StringValue(goValue.String()), true
+
+	case reflect.Slice, reflect.Array:
+		elements := make([]value.Value, 0, goValue.Len())
+
+		for i := 0; i < goValue.Len(); i++ {
+			element, ok := goToValueWithAncestors(libraryPath, name, goValue.Index(i), ancestors)
+
+			if !ok {
+				return nil, false
+			}
+
+			elements = append(elements, element)
+		}
+
+		return &value_types.TupleValue{
+			Elements: elements,
+		}, true
+
+	case reflect.Map:
+		keys := goValue.MapKeys()
+
+		slices.SortFunc(keys, compareMapKeys)
+
+		pairs := make([]value.Value, 0, len(keys))
+
+		for _, key := range keys {
+			keyValue, ok := goToValueWithAncestors(libraryPath, name, key, ancestors)
+
+			if !ok {
+				return nil, false
+			}
+
+			elementValue, ok := goToValueWithAncestors(
+				libraryPath,
+				name,
+				goValue.MapIndex(key),
+				ancestors,
+			)
+
+			if !ok {
+				return nil, false
+			}
+
+			pairs = append(pairs, &value_types.TupleValue{
+				Elements: []value.Value{keyValue, elementValue},
+			})
+		}
+
+		return &value_types.TupleValue{
+			Elements: pairs,
+		}, true
+
+	case reflect.Struct:
+		fields := map[value_types.StringValue]value.Value{}
+
+		for i := 0; i < goValue.NumField(); i++ {
+			field := goValue.Type().Field(i)
+
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldValue, ok := goToValueWithAncestors(libraryPath, name, goValue.Field(i), ancestors)
+
+			if !ok {
+				return nil, false
+			}
+
+			fields[value_types.StringValue(fieldName(field))] = fieldValue
+		}
+
+		return built_in_definitions.NewLookupFunction(fields), true
+
+	case reflect.Func:
+		if goValue.IsNil() {
+			return value_types.UnitValue{}, true
+		}
+
+		return wrapFunction(libraryPath, name, goValue), true
+
+	case reflect.Pointer, reflect.Interface:
+		if goValue.IsNil() {
+			return value_types.UnitValue{}, true
+		}
+
+		return goToValueWithAncestors(libraryPath, name, goValue.Elem(), ancestors)
+	}
+
+	return nil, false
+}
+
+func fieldName(field reflect.StructField) string {
+	if name, ok := field.Tag.Lookup("krait"); ok {
+		return name
+	}
+
+	var result strings.Builder
+	runes := []rune(field.Name)
+
+	for i, rune_ := range runes {
+		if unicode.IsUpper(rune_) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				result.WriteRune('_')
+			}
+
+			rune_ = unicode.ToLower(rune_)
+		}
+
+		result.WriteRune(rune_)
+	}
+
+	return result.String()
+}
+
+/*
+ * Convert a Krait value into a Go value of the given type. Integers are accepted wherever floats
+ * are, and tuples wherever slices or arrays are.
+ */
+func valueToGo(value_ value.Value, goType reflect.Type) (reflect.Value, bool) {
+	if goType.Kind() == reflect.Interface && goType.NumMethod() == 0 {
+		if naturalValue := naturalGoValue(value_); naturalValue != nil {
+			return reflect.ValueOf(naturalValue), true
+		}
+
+		return reflect.Zero(goType), true
+	}
+
+	if reflect.TypeOf(value_).AssignableTo(goType) {
+		return reflect.ValueOf(value_), true
+	}
+
+	switch goType.Kind() {
+	case reflect.Bool:
+		if boolean, ok := value_.(value_types.BooleanValue); ok {
+			return reflect.ValueOf(bool(boolean)).Convert(goType), true
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if integer, ok := value_.(value_types.IntegerValue); ok {
+			result := reflect.New(goType).Elem()
+
+			if result.OverflowInt(int64(integer)) {
+				return reflect.Value{}, false
+			}
+
+			result.SetInt(int64(integer))
+
+			return result, true
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if integer, ok := value_.(value_types.IntegerValue); ok && integer >= 0 {
+			result := reflect.New(goType).Elem()
+
+			if result.OverflowUint(uint64(integer)) {
+				return reflect.Value{}, false
+			}
+
+			result.SetUint(uint64(integer))
+
+			return result, true
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch number := value_.(type) {
+		case value_types.FloatValue:
+			return reflect.ValueOf(float64(number)).Convert(goType), true
+
+		case value_types.IntegerValue:
+			return reflect.ValueOf(float64(number)).Convert(goType), true
+		}
+
+	case reflect.String:
+		if string_, ok := value_.(value_types.StringValue); ok {
+			return reflect.ValueOf(string(string_)).Convert(goType), true
+		}
+
+	case reflect.Slice, reflect.Array:
+		tuple, ok := value_.(*value_types.TupleValue)
+
+		if !ok {
+			break
+		}
+
+		var result reflect.Value
+
+		if goType.Kind() == reflect.Slice {
+			result = reflect.MakeSlice(goType, len(tuple.Elements), len(tuple.Elements))
+		} else if goType.Len() == len(tuple.Elements) {
+			result = reflect.New(goType).Elem()
+		} else {
+			break
+		}
+
+		for i, element := range tuple.Elements {
+			elementValue, ok := valueToGo(element, goType.Elem())
+
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			result.Index(i).Set(elementValue)
+		}
+
+		return result, true
+
+	case reflect.Pointer:
+		elementValue, ok := valueToGo(value_, goType.Elem())
+
+		if !ok {
+			break
+		}
+
+		result := reflect.New(goType.Elem())
+		result.Elem().Set(elementValue)
+
+		return result, true
+	}
+
+	return reflect.Value{}, false
+}
+
+/*
+ * The Go value a Krait value most naturally corresponds to, for parameters of type `any`.
+ */
+func naturalGoValue(value_ value.Value) any {
+	switch value_ := value_.(type) {
+	case value_types.BooleanValue:
+		return bool(value_)
+
+	case value_types.IntegerValue:
+		return int64(value_)
+
+	case value_types.FloatValue:
+		return float64(value_)
+
+	case value_types.StringValue:
+		return string(value_)
+
+	case value_types.UnitValue:
+		return nil
+
+	case *value_types.TupleValue:
+		result := make([]any, 0, len(value_.Elements))
+
+		for _, element := range value_.Elements {
+			result = append(result, naturalGoValue(element))
+		}
+
+		return result
+	}
+
+	return value_
+}
+
+/*
+ * Wrap a plain Go function as a built-in function, converting its arguments with `valueToGo` and
+ * its results with `goToValue`. Multiple results are returned as a tuple. If the last result is an
+ * `error`, the function instead returns an `Either`: `Left` of the error's message if it isn't
+ * `nil`, or `Right` of the remaining results otherwise.
+ */
+func wrapFunction(libraryPath string, name string, goFunction reflect.Value) *function.Function {
+	functionType := goFunction.Type()
+	parameterCount := functionType.NumIn()
+	returnsError := functionType.NumOut() > 0 &&
+		functionType.Out(functionType.NumOut()-1) == errorType
+
+	return function.NewBuiltInFunction(
+		func(argumentTypes []reflect.Type) *errors.Error {
+			if functionType.IsVariadic() {
+				if len(argumentTypes) < parameterCount-1 {
+					return runtime_errors.IncorrectCallArgumentCount(
+						fmt.Sprintf("at least %d", parameterCount-1),
+						parameterCount-1 != 1,
+						len(argumentTypes),
+					)
+				}
+			} else if len(argumentTypes) != parameterCount {
+				return runtime_errors.IncorrectCallArgumentCount(
+					fmt.Sprint(parameterCount),
+					parameterCount != 1,
+					len(argumentTypes),
+				)
+			}
+
+			return nil
+		},
+
+		func(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
+			goArguments := make([]reflect.Value, 0, len(arguments))
+
+			for i, argument := range arguments {
+				var parameterType reflect.Type
+
+				if functionType.IsVariadic() && i >= parameterCount-1 {
+					parameterType = functionType.In(parameterCount - 1).Elem()
+				} else {
+					parameterType = functionType.In(i)
+				}
+
+				goArgument, ok := valueToGo(argument, parameterType)
+
+				if !ok {
+					errors.RaiseError(runtime_errors.IncorrectBuiltInFunctionArgumentType(name, i))
+				}
+
+				goArguments = append(goArguments, goArgument)
+			}
+
+			results := goFunction.Call(goArguments)
+
+			if returnsError {
+				err := results[len(results)-1]
+
+				if !err.IsNil() {
+					return newEither(
+						runtime_,
+						"Left",
+						value_types.StringValue(err.Interface().(error).Error()),
+					)
+				}
+
+				return newEither(
+					runtime_,
+					"Right",
+					resultsToValue(libraryPath, name, results[:len(results)-1]),
+				)
+			}
+
+			return resultsToValue(libraryPath, name, results)
+		},
+
+		parser_types.NormalFunction,
+	)
+}
+
+func resultsToValue(libraryPath string, name string, results []reflect.Value) value.Value {
+	elements := make([]value.Value, 0, len(results))
+
+	for _, result := range results {
+		element, ok := goToValue(libraryPath, name, result)
+
+		if !ok {
+			errors.RaiseError(
+				runtime_errors.LibraryValueNotConvertible(libraryPath, name, result.Type().String()),
+			)
+		}
+
+		elements = append(elements, element)
+	}
+
+	switch len(elements) {
+	case 0:
+		return value_types.UnitValue{}
+
+	case 1:
+		return elements[0]
+	}
+
+	return &value_types.TupleValue{
+		Elements: elements,
+	}
+}
+
+/*
+ * Order numeric map keys by their values, and all others by their string representations.
+ */
+func compareMapKeys(left reflect.Value, right reflect.Value) int {
+	switch left.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(left.Int(), right.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(left.Uint(), right.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(left.Float(), right.Float())
+	}
+
+	return strings.Compare(fmt.Sprint(left.Interface()), fmt.Sprint(right.Interface()))
+}
+
+/*
+ * Construct a `Left` or `Right` from the standard library's `either` module. The embedded module is
+ * loaded by its path, so that modules named `either` elsewhere in `KRAIT_PATH` can't shadow it.
+ */
+func newEither(runtime_ *runtime.Runtime, constructorName string, value_ value.Value) value.Value {
+	path, _ := standard_library.ModulePath([]string{"either"})
+	module := runtime_.LoaderChannel.Load(loader.FileRequest, path).(*function.Function)
+	constructor := module.Evaluate(runtime_, value_types.StringValue(constructorName))
+
+	return constructor.(*function.Function).Evaluate(runtime_, value_)
+}
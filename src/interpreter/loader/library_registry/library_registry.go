@@ -0,0 +1,55 @@
+package library_registry
+
+import (
+	"fmt"
+	"sync"
+
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types/library"
+)
+
+/*
+ * Registered libraries are referred to by their name prefixed with `PathPrefix` wherever a
+ * library's path would otherwise be used.
+ */
+const PathPrefix = "<built-in>/"
+
+var (
+	registeredLibraries      = map[string]*library.Library{}
+	registeredLibrariesMutex sync.RWMutex
+)
+
+/*
+ * Register a native library compiled into the interpreter, which `import_library(name)` then
+ * returns instead of searching for `name.so`. Packages should register their libraries when
+ * initialized.
+ */
+func Register(name string, fields map[string]value.Value) {
+	registeredLibrariesMutex.Lock()
+	defer registeredLibrariesMutex.Unlock()
+
+	if _, ok := registeredLibraries[name]; ok {
+		panic(fmt.Sprintf("the library \"%s\" was registered twice", name))
+	}
+
+	registeredLibraries[name] = &library.Library{
+		Path: PathPrefix + name,
+		GetField: func(name string) (value.Value, bool) {
+			result, ok := fields[name]
+
+			return result, ok
+		},
+
+		SymbolNames: library.SortedSymbolNames(fields),
+		HasSymbol:   nil,
+	}
+}
+
+func Lookup(name string) (*library.Library, bool) {
+	registeredLibrariesMutex.RLock()
+	defer registeredLibrariesMutex.RUnlock()
+
+	result, ok := registeredLibraries[name]
+
+	return result, ok
+}
@@ -1,27 +1,53 @@
 package loader
 
-import "project_umbrella/interpreter/runtime/value"
+import (
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/runtime/value"
+)
 
+/*
+ * The runtime sends a request on `LoadRequest` whenever a module or library is imported. Imports
+ * may be made concurrently, so each request carries its own channel on which it's responded to.
+ */
 type LoaderChannel struct {
-	LoadRequest  chan *LoaderRequest
-	LoadResponse chan value.Value
+	LoadRequest chan *LoaderRequest
 }
 
 func (channel *LoaderChannel) Close() {
 	close(channel.LoadRequest)
-	close(channel.LoadResponse)
+}
+
+/*
+ * Request a module or library and wait for it, raising any error raised while loading it.
+ */
+func (channel *LoaderChannel) Load(type_ LoaderRequestType, name string) value.Value {
+	request := &LoaderRequest{
+		Type:         type_,
+		Name:         name,
+		LoadResponse: make(chan *LoaderResponse, 1),
+	}
+
+	channel.LoadRequest <- request
+
+	response := <-request.LoadResponse
+
+	if response.Error != nil {
+		errors.RaiseError(response.Error)
+	}
+
+	return response.Value
 }
 
 func NewLoaderChannel() *LoaderChannel {
 	return &LoaderChannel{
-		LoadRequest:  make(chan *LoaderRequest),
-		LoadResponse: make(chan value.Value),
+		LoadRequest: make(chan *LoaderRequest),
 	}
 }
 
 type LoaderRequest struct {
-	Type LoaderRequestType
-	Name string
+	Type         LoaderRequestType
+	Name         string
+	LoadResponse chan *LoaderResponse
 }
 
 type LoaderRequestType int
@@ -29,4 +55,15 @@ type LoaderRequestType int
 const (
 	ModuleRequest LoaderRequestType = iota + 1
 	LibraryRequest
+
+	// Requests the module at a path rather than by name (e.g. one embedded in the interpreter)
+	FileRequest
 )
+
+/*
+ * Exactly one of `Value` and `Error` is set.
+ */
+type LoaderResponse struct {
+	Value value.Value
+	Error *errors.Error
+}
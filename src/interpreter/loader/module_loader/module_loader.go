@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/benbjohnson/immutable"
 	"github.com/puzpuzpuz/xsync/v3"
@@ -18,14 +19,33 @@ import (
 	"project_umbrella/interpreter/errors"
 	"project_umbrella/interpreter/errors/runtime_errors"
 	"project_umbrella/interpreter/loader"
+	"project_umbrella/interpreter/loader/extension_loader"
 	"project_umbrella/interpreter/loader/file_loader"
 	"project_umbrella/interpreter/loader/library_loader"
+	"project_umbrella/interpreter/loader/library_registry"
+	"project_umbrella/interpreter/project"
+	"project_umbrella/interpreter/runtime"
 	"project_umbrella/interpreter/runtime/value"
 	"project_umbrella/interpreter/runtime/value_types/library"
+	"project_umbrella/interpreter/standard_library"
 )
 
 type ModuleLoader struct {
-	cache *xsync.MapOf[string, *moduleLoaderCacheEntry]
+	cache             *xsync.MapOf[string, *moduleLoaderCacheEntry]
+	searchDirectories *xsync.MapOf[string, []string]
+
+	/*
+	 * Every import made so far, as edges from importing to imported file paths. Imports are loaded
+	 * concurrently, so a cycle can span several import stacks without appearing in any one of them.
+	 */
+	importGraph      map[string][]string
+	importGraphMutex sync.Mutex
+
+	// The files opened so far (other than embedded ones), with their modification times at the time
+	openedFiles *xsync.MapOf[string, time.Time]
+
+	// Observe every module loaded
+	Instruments runtime.Instruments
 }
 
 func (moduleLoader *ModuleLoader) LoadFile(path_ string) value.Value {
@@ -42,6 +62,10 @@ func (moduleLoader *ModuleLoader) loadFileWithStack(
 		errors.RaiseError(runtime_errors.ModuleCycle(moduleLoaderStack_.ToSlice()))
 	}
 
+	if importingPath, ok := moduleLoaderStack_.Top(); ok {
+		moduleLoader.addImport(importingPath, path_, moduleLoaderStack_)
+	}
+
 	entry, _ := moduleLoader.cache.LoadOrStore(path_, &moduleLoaderCacheEntry{
 		result:        nil,
 		computeResult: &sync.Once{},
@@ -52,40 +76,280 @@ func (moduleLoader *ModuleLoader) loadFileWithStack(
 	go func() {
 		entry.computeResult.Do(
 			func() {
-				entry.result = file_loader.LoadFile(path_, loaderChannel)
+				moduleLoader.addOpenedFile(path_)
+
+				if startupFilePath, ok := file_loader.StartupFilePath(path_); ok {
+					moduleLoader.addOpenedFile(startupFilePath)
+				}
+
+				entry.err = errors.Catch(func() {
+					entry.result = file_loader.LoadFile(path_, loaderChannel, moduleLoader.Instruments)
+				})
 			},
 		)
 
 		loaderChannel.Close()
 	}()
 
-	for {
-		request, ok := <-loaderChannel.LoadRequest
+	// Each request is served concurrently, so that independent modules are loaded in parallel.
+	for request := range loaderChannel.LoadRequest {
+		go moduleLoader.serveRequest(request, path_, moduleLoaderStack_.Add(path_))
+	}
 
-		if !ok {
-			break
-		}
+	if entry.err != nil {
+		errors.RaiseError(entry.err)
+	}
+
+	return entry.result
+}
+
+/*
+ * Call `callback` with a runtime whose imports are resolved as if made by the file at `path_`, for
+ * calling the functions of modules already loaded. Errors raised by `callback` are re-raised.
+ */
+func (moduleLoader *ModuleLoader) WithRuntime(
+	path_ string,
+	callback func(runtime_ *runtime.Runtime),
+) {
+	path_ = filepath.Clean(path_)
+	loaderChannel := loader.NewLoaderChannel()
+
+	var err *errors.Error
+
+	go func() {
+		err = errors.Catch(func() {
+			callback(&runtime.Runtime{
+				LoaderChannel:  loaderChannel,
+				CallStack:      nil,
+				Tracer:         nil,
+				DebuggerThread: nil,
+				Coverage:       moduleLoader.Instruments.Coverage,
+				Struct:         nil,
+			})
+		})
+
+		loaderChannel.Close()
+	}()
 
+	for request := range loaderChannel.LoadRequest {
+		go moduleLoader.serveRequest(request, path_, newModuleStack().Add(path_))
+	}
+
+	if err != nil {
+		errors.RaiseError(err)
+	}
+}
+
+func (moduleLoader *ModuleLoader) serveRequest(
+	request *loader.LoaderRequest,
+	importingPath string,
+	moduleLoaderStack_ *moduleLoaderStack,
+) {
+	var result value.Value
+
+	err := errors.Catch(func() {
 		switch request.Type {
 		case loader.ModuleRequest:
-			loaderChannel.LoadResponse <- moduleLoader.loadModuleWithStack(
+			result = moduleLoader.loadModuleWithStack(
 				request.Name,
-				moduleLoaderStack_.Add(path_),
+				filepath.Dir(importingPath),
+				moduleLoaderStack_,
 			)
 
 		case loader.LibraryRequest:
-			loaderChannel.LoadResponse <- moduleLoader.loadLibrary(request.Name)
+			result = moduleLoader.loadLibrary(request.Name, importingPath)
+
+		case loader.FileRequest:
+			result = moduleLoader.loadFileWithStack(request.Name, moduleLoaderStack_)
 		}
+	})
+
+	request.LoadResponse <- &loader.LoaderResponse{
+		Value: result,
+		Error: err,
 	}
+}
 
-	return entry.result
+/*
+ * Record that the file at `importingPath` imports the one at `importedPath`, raising an error if
+ * the import completes a cycle. `moduleLoaderStack_` is the import stack ending in `importingPath`.
+ */
+func (moduleLoader *ModuleLoader) addImport(
+	importingPath string,
+	importedPath string,
+	moduleLoaderStack_ *moduleLoaderStack,
+) {
+	moduleLoader.importGraphMutex.Lock()
+	defer moduleLoader.importGraphMutex.Unlock()
+
+	if cyclePath, ok := moduleLoader.importGraphPath(importedPath, importingPath); ok {
+		errors.RaiseError(
+			runtime_errors.ModuleCycle(
+				append(moduleLoaderStack_.ToSlice(), cyclePath[:len(cyclePath)-1]...),
+			),
+		)
+	}
+
+	moduleLoader.importGraph[importingPath] = append(
+		moduleLoader.importGraph[importingPath],
+		importedPath,
+	)
+}
+
+/*
+ * Find a chain of imports from `sourcePath` to `destinationPath` (inclusive), by breadth-first
+ * search. The import graph's mutex must be held.
+ */
+func (moduleLoader *ModuleLoader) importGraphPath(
+	sourcePath string,
+	destinationPath string,
+) ([]string, bool) {
+	previousPaths := map[string]string{sourcePath: ""}
+	queue := []string{sourcePath}
+
+	for len(queue) > 0 {
+		currentPath := queue[0]
+		queue = queue[1:]
+
+		if currentPath == destinationPath {
+			result := []string{}
+
+			for ; currentPath != ""; currentPath = previousPaths[currentPath] {
+				result = append([]string{currentPath}, result...)
+			}
+
+			return result, true
+		}
+
+		for _, nextPath := range moduleLoader.importGraph[currentPath] {
+			if _, ok := previousPaths[nextPath]; !ok {
+				previousPaths[nextPath] = currentPath
+				queue = append(queue, nextPath)
+			}
+		}
+	}
+
+	return nil, false
+}
+
+/*
+ * Record that the file at `importingPath` imports the native library at `libraryPath`, so that it's
+ * loaded again if the library changes. Libraries can't import modules, so they can't form cycles.
+ */
+func (moduleLoader *ModuleLoader) addLibraryImport(importingPath string, libraryPath string) {
+	moduleLoader.importGraphMutex.Lock()
+	defer moduleLoader.importGraphMutex.Unlock()
+
+	moduleLoader.importGraph[importingPath] = append(
+		moduleLoader.importGraph[importingPath],
+		libraryPath,
+	)
+
+	moduleLoader.addOpenedFile(libraryPath)
+}
+
+/*
+ * Record that the file at `path_` was opened, with its current modification time (or the zero time
+ * if it doesn't exist), unless it already was.
+ */
+func (moduleLoader *ModuleLoader) addOpenedFile(path_ string) {
+	if standard_library.IsEmbeddedPath(path_) {
+		return
+	}
+
+	modificationTime := time.Time{}
+
+	if info, err := os.Stat(path_); err == nil {
+		modificationTime = info.ModTime()
+	}
+
+	moduleLoader.openedFiles.LoadOrStore(filepath.Clean(path_), modificationTime)
+}
+
+/*
+ * The files opened so far (other than embedded ones, which can't change), with their modification
+ * times when they were opened: the files of the modules loaded, the startup file and the native
+ * libraries imported.
+ */
+func (moduleLoader *ModuleLoader) OpenedFiles() map[string]time.Time {
+	result := map[string]time.Time{}
+
+	moduleLoader.openedFiles.Range(func(path_ string, modificationTime time.Time) bool {
+		result[path_] = modificationTime
+
+		return true
+	})
+
+	return result
+}
+
+/*
+ * Forget the modules loaded from the files at `changedPaths` and those importing them (directly or
+ * indirectly), so that they're loaded again the next time they're imported, and stop the extensions
+ * at `changedPaths`. Other modules aren't loaded again. If the startup file changed, every module
+ * it precedes is loaded again.
+ */
+func (moduleLoader *ModuleLoader) Invalidate(changedPaths []string) {
+	moduleLoader.importGraphMutex.Lock()
+	defer moduleLoader.importGraphMutex.Unlock()
+
+	invalidatedPathSet := map[string]bool{}
+	queue := []string{}
+
+	invalidate := func(path_ string) {
+		if !invalidatedPathSet[path_] {
+			invalidatedPathSet[path_] = true
+			queue = append(queue, path_)
+		}
+	}
+
+	for _, path_ := range changedPaths {
+		path_ = filepath.Clean(path_)
+
+		invalidate(path_)
+		moduleLoader.openedFiles.Delete(path_)
+		extension_loader.UnloadExtension(path_)
+	}
+
+	moduleLoader.cache.Range(func(path_ string, _ *moduleLoaderCacheEntry) bool {
+		startupFilePath, ok := file_loader.StartupFilePath(path_)
+
+		if ok && invalidatedPathSet[filepath.Clean(startupFilePath)] {
+			invalidate(path_)
+		}
+
+		return true
+	})
+
+	importingPathMap := map[string][]string{}
+
+	for importingPath, importedPaths := range moduleLoader.importGraph {
+		for _, importedPath := range importedPaths {
+			importingPathMap[importedPath] = append(importingPathMap[importedPath], importingPath)
+		}
+	}
+
+	for len(queue) > 0 {
+		currentPath := queue[0]
+		queue = queue[1:]
+
+		for _, importingPath := range importingPathMap[currentPath] {
+			invalidate(importingPath)
+		}
+	}
+
+	for path_ := range invalidatedPathSet {
+		moduleLoader.cache.Delete(path_)
+		delete(moduleLoader.importGraph, path_)
+	}
 }
 
 func (loader *ModuleLoader) loadModuleWithStack(
 	moduleName string,
+	importingDirectory string,
 	moduleLoaderStack_ *moduleLoaderStack,
 ) value.Value {
-	path_, ok := getModuleOrLibraryPath(moduleName, "krait")
+	path_, ok := loader.ModulePath(moduleName, importingDirectory)
 
 	if !ok {
 		errors.RaiseError(runtime_errors.ModuleNotFound(moduleName))
@@ -94,21 +358,128 @@ func (loader *ModuleLoader) loadModuleWithStack(
 	return loader.loadFileWithStack(path_, moduleLoaderStack_)
 }
 
-func (loader *ModuleLoader) loadLibrary(libraryName string) *library.Library {
-	path, ok := getModuleOrLibraryPath(libraryName, "so")
+/*
+ * Find the file the Krait module `moduleName` would be loaded from if imported by a file in
+ * `importingDirectory`.
+ */
+func (loader *ModuleLoader) ModulePath(moduleName string, importingDirectory string) (string, bool) {
+	return getModuleOrLibraryPath(
+		moduleName,
+		importingDirectory,
+		loader.moduleSearchDirectories(moduleName, importingDirectory),
+		"krait",
+	)
+}
 
-	if !ok {
-		errors.RaiseError(runtime_errors.LibraryNotFound(libraryName))
+/*
+ * Libraries compiled into the interpreter take precedence over plugins (`.so` files), which take
+ * precedence over out-of-process extensions (executable `.extension` files).
+ */
+func (loader *ModuleLoader) loadLibrary(
+	libraryName string,
+	importingPath string,
+) *library.Library {
+	if library_, ok := library_registry.Lookup(libraryName); ok {
+		return library_
 	}
 
-	return library_loader.LoadLibrary(path)
+	importingDirectory := filepath.Dir(importingPath)
+	searchDirectories := loader.moduleSearchDirectories(libraryName, importingDirectory)
+
+	if path, ok := getModuleOrLibraryPath(
+		libraryName,
+		importingDirectory,
+		searchDirectories,
+		"so",
+	); ok {
+		loader.addLibraryImport(importingPath, path)
+
+		return library_loader.LoadLibrary(path)
+	}
+
+	if path, ok := getModuleOrLibraryPath(
+		libraryName,
+		importingDirectory,
+		searchDirectories,
+		"extension",
+	); ok {
+		loader.addLibraryImport(importingPath, path)
+
+		return extension_loader.LoadExtension(path)
+	}
+
+	errors.RaiseError(runtime_errors.LibraryNotFound(libraryName))
+
+	return nil
 }
 
-func getModuleOrLibraryPath(name string, fileExtension string) (string, bool) {
-	kraitPathDirectories := strings.Split(environment_variables.KRAIT_PATH, ":")
+/*
+ * Modules imported from within a project are searched for in its vendor directory and source
+ * directories before those in `KRAIT_PATH`. Relative imports aren't searched for, so the project
+ * isn't looked up for them.
+ */
+func (loader *ModuleLoader) moduleSearchDirectories(
+	name string,
+	importingDirectory string,
+) []string {
+	if isRelativeName(name) {
+		return []string{}
+	}
+
+	if result, ok := loader.searchDirectories.Load(importingDirectory); ok {
+		return result
+	}
+
+	/*
+	 * Finding the project may raise an error (e.g. if its manifest is invalid), so it's done before
+	 * storing the result rather than in `LoadOrCompute`, which would stay locked.
+	 */
+	result := strings.Split(environment_variables.KRAIT_PATH, ":")
+
+	// Modules in the embedded standard library don't belong to any project.
+	if !standard_library.IsEmbeddedPath(importingDirectory) {
+		if project_, ok := project.FindProject(importingDirectory); ok {
+			result = append(project_.ModuleDirectories(importingDirectory), result...)
+		}
+	}
+
+	result, _ = loader.searchDirectories.LoadOrStore(importingDirectory, result)
+
+	return result
+}
+
+func isRelativeName(name string) bool {
+	return strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../")
+}
+
+/*
+ * Names beginning with "./" or "../" are paths relative to the importing file's directory; all
+ * others are dot-separated paths relative to one of `searchDirectories`, or failing that, to the
+ * embedded standard library.
+ */
+func getModuleOrLibraryPath(
+	name string,
+	importingDirectory string,
+	searchDirectories []string,
+	fileExtension string,
+) (string, bool) {
+	if isRelativeName(name) {
+		path_ := filepath.Join(importingDirectory, fmt.Sprintf("%s.%s", name, fileExtension))
+
+		if standard_library.IsEmbeddedPath(importingDirectory) {
+			return path_, standard_library.IsEmbeddedFile(path_)
+		}
+
+		if _, err := os.Stat(path_); err != nil || !common.IsFileUnsafe(path_) {
+			return "", false
+		}
+
+		return path_, true
+	}
+
 	moduleComponents := strings.Split(name, ".")
 
-	for _, path_ := range kraitPathDirectories {
+	for _, path_ := range searchDirectories {
 		if _, err := os.Stat(path_); standard_errors.Is(err, fs.ErrNotExist) {
 			continue
 		}
@@ -151,17 +522,26 @@ func getModuleOrLibraryPath(name string, fileExtension string) (string, bool) {
 		}
 	}
 
+	if fileExtension == "krait" {
+		return standard_library.ModulePath(moduleComponents)
+	}
+
 	return "", false
 }
 
 func NewModuleLoader() *ModuleLoader {
 	return &ModuleLoader{
-		cache: xsync.NewMapOf[string, *moduleLoaderCacheEntry](),
+		cache:             xsync.NewMapOf[string, *moduleLoaderCacheEntry](),
+		searchDirectories: xsync.NewMapOf[string, []string](),
+		importGraph:       map[string][]string{},
+		openedFiles:       xsync.NewMapOf[string, time.Time](),
+		Instruments:       runtime.Instruments{},
 	}
 }
 
 type moduleLoaderCacheEntry struct {
 	result        value.Value
+	err           *errors.Error
 	computeResult *sync.Once
 }
 
@@ -181,6 +561,14 @@ func (stack *moduleLoaderStack) Has(moduleName string) bool {
 	return stack.stackSet.Has(moduleName)
 }
 
+func (stack *moduleLoaderStack) Top() (string, bool) {
+	if stack.stackList.Len() == 0 {
+		return "", false
+	}
+
+	return stack.stackList.Get(stack.stackList.Len() - 1), true
+}
+
 func (stack *moduleLoaderStack) ToSlice() []string {
 	result := make([]string, 0, stack.stackList.Len())
 	iterator := stack.stackList.Iterator()
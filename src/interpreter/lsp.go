@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"project_umbrella/interpreter/language_server"
+)
+
+/*
+ * `interpreter lsp` runs the language server over stdin and stdout, exiting unsuccessfully if the
+ * client exits without shutting it down first.
+ */
+func runLSPCommand() {
+	if !language_server.Serve(os.Stdin, os.Stdout) {
+		os.Exit(1)
+	}
+}
@@ -6,12 +6,49 @@ import (
 	"project_umbrella/interpreter/errors"
 	"project_umbrella/interpreter/errors/entry_errors"
 	"project_umbrella/interpreter/loader/module_loader"
+
+	// Native libraries compiled into the interpreter register themselves when initialized.
+	_ "project_umbrella/interpreter/standard_library/native_io"
+	_ "project_umbrella/interpreter/standard_library/native_math"
+	_ "project_umbrella/interpreter/standard_library/native_testing"
 )
 
 func main() {
+	defer errors.ExitOnUncaughtError()
+
 	if len(os.Args) < 2 {
 		errors.RaiseError(entry_errors.FileNotSpecified)
 	}
 
-	module_loader.NewModuleLoader().LoadFile(os.Args[1])
+	switch os.Args[1] {
+	case "coverage":
+		runCoverageCommand(os.Args[2:])
+
+	case "debug":
+		runDebugCommand(os.Args[2:])
+
+	case "deps":
+		runDepsCommand(os.Args[2:])
+
+	case "doc":
+		runDocCommand(os.Args[2:])
+
+	case "fmt":
+		runFmtCommand(os.Args[2:])
+
+	case "lint":
+		runLintCommand(os.Args[2:])
+
+	case "lsp":
+		runLSPCommand()
+
+	case "run":
+		runRunCommand(os.Args[2:])
+
+	case "test":
+		runTestCommand(os.Args[2:])
+
+	default:
+		module_loader.NewModuleLoader().LoadFile(os.Args[1])
+	}
 }
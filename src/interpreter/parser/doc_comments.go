@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"strings"
+)
+
+/*
+ * Comments on the lines immediately preceding a declaration (a `fn`, `struct`, `trait` or
+ * assignment), each on a line of its own, document it.
+ */
+type DocComments struct {
+	source string
+
+	// The text of each comment on a line of its own, keyed by the offset of the line's start
+	lineComments map[int]string
+}
+
+func NewDocComments(filename string, source string) *DocComments {
+	_, comments := separateComments(filename, source)
+	result := &DocComments{
+		source:       source,
+		lineComments: map[int]string{},
+	}
+
+	for _, comment := range comments {
+		lineStart := strings.LastIndexByte(source[:comment.Pos.Offset], '\n') + 1
+
+		if strings.TrimSpace(source[lineStart:comment.Pos.Offset]) == "" {
+			result.lineComments[lineStart] = strings.TrimPrefix(
+				strings.TrimPrefix(comment.Value, "#"),
+				" ",
+			)
+		}
+	}
+
+	return result
+}
+
+/*
+ * The doc comment of the declaration on the line containing `offset` (e.g. that of its name), with
+ * each line's "#" and the space following it removed, or "" if it isn't documented.
+ */
+func (docComments *DocComments) Of(offset int) string {
+	lines := []string{}
+	lineStart := strings.LastIndexByte(docComments.source[:offset], '\n') + 1
+
+	for lineStart > 0 {
+		previousLineStart := strings.LastIndexByte(docComments.source[:lineStart-1], '\n') + 1
+		comment, ok := docComments.lineComments[previousLineStart]
+
+		if !ok {
+			break
+		}
+
+		lines = append([]string{comment}, lines...)
+		lineStart = previousLineStart
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -14,9 +14,14 @@ const (
 	AssignmentOperatorToken lexer.TokenType = iota + 1
 	ColonToken
 	CommaToken
+	EllipsisToken
 	ElseKeywordToken
 	IfKeywordToken
+	ImplementsKeywordToken
+	InKeywordToken
+	LetKeywordToken
 	FloatToken
+	FromKeywordToken
 	FunctionKeywordToken
 	IdentifierToken
 	IndentToken
@@ -30,6 +35,8 @@ const (
 	SpaceToken
 	StringToken
 	StructKeywordToken
+	TraitKeywordToken
+	CommentToken
 )
 
 /*
@@ -73,11 +80,31 @@ var matcher = ExhaustiveMatcher{
 			CompileMatcher("^if$"),
 		},
 
+		{
+			MatcherCode(ImplementsKeywordToken),
+			CompileMatcher("^implements$"),
+		},
+
+		{
+			MatcherCode(InKeywordToken),
+			CompileMatcher("^in$"),
+		},
+
+		{
+			MatcherCode(LetKeywordToken),
+			CompileMatcher("^let$"),
+		},
+
 		{
 			MatcherCode(FloatToken),
 			CompileMatcher(`^(?:\+|-)?(?:\d+\.\d*|\.\d+)$`),
 		},
 
+		{
+			MatcherCode(FromKeywordToken),
+			CompileMatcher(`^from$`),
+		},
+
 		{
 			MatcherCode(FunctionKeywordToken),
 			CompileMatcher(`^fn$`),
@@ -113,6 +140,11 @@ var matcher = ExhaustiveMatcher{
 			CompileMatcher(`^struct$`),
 		},
 
+		{
+			MatcherCode(TraitKeywordToken),
+			CompileMatcher(`^trait$`),
+		},
+
 		/*
 		 * Operators and identifiers are parsed towards the end because they shouldn't contain
 		 * anything that'd be _identified_ (get it?) as another token.
@@ -123,8 +155,11 @@ var matcher = ExhaustiveMatcher{
 		 * Conflicts with other tokens:
 		 * "\"", "(", ")", ",", ".", ":", "_"
 		 *
+		 * Used for comments:
+		 * "#"
+		 *
 		 * Reserved for future use:
-		 * "#", "$", ",", ";", "?", "@", "[", "]", "\\", "`", "{", "}"
+		 * "$", ",", ";", "?", "@", "[", "]", "\\", "`", "{", "}"
 		 *
 		 * "=" is also not a valid operator.
 		 */
@@ -148,6 +183,15 @@ var matcher = ExhaustiveMatcher{
 			CompileMatcher(`[^\t\n !"%&()*+,\-.\d<=>][^\t\n !"%&()*+,\-.<=>]*`),
 		},
 
+		/*
+		 * The ellipsis is parsed before the select operator so it isn't parsed as three select
+		 * operators.
+		 */
+		{
+			MatcherCode(EllipsisToken),
+			CompileMatcher(`\.\.\.`),
+		},
+
 		/*
 		 * Because float tokens are parsed using the start-of-line and end-of-line symbols,
 		 * we'd like to give the lexer every opportunity to parse tokens beside floats before the
@@ -162,11 +206,63 @@ var matcher = ExhaustiveMatcher{
 
 type Lexer struct {
 	cachedTokens []*lexer.Token
+	comments     []*lexer.Token
 	fileContent  string
 	filename     string
 	i            int
 }
 
+/*
+ * Comments run from a "#" outside a string to the end of the line. Since they can appear anywhere
+ * (including on lines indented differently from their neighbours), we separate them from the
+ * source before tokenizing it, replacing each with spaces so the offsets of the remaining tokens
+ * don't change and lines containing only a comment are considered blank.
+ */
+func separateComments(filename string, fileContent string) (string, []*lexer.Token) {
+	comments := []*lexer.Token{}
+	maskedContent := []byte(fileContent)
+	isInString := false
+
+	for i := 0; i < len(fileContent); i++ {
+		switch fileContent[i] {
+		case '"':
+			isInString = !isInString
+
+		case '#':
+			if isInString {
+				continue
+			}
+
+			end := strings.IndexByte(fileContent[i:], '\n')
+
+			if end == -1 {
+				end = len(fileContent)
+			} else {
+				end += i
+			}
+
+			comments = append(comments, &lexer.Token{
+				Type:  lexer.TokenType(CommentToken),
+				Value: fileContent[i:end],
+				Pos: lexer.Position{
+					Filename: filename,
+					Offset:   i,
+					Line:     0,
+					Column:   0,
+				},
+			})
+
+			for j := i; j < end; j++ {
+				maskedContent[j] = ' '
+			}
+
+			i = end - 1
+		}
+	}
+
+	return string(maskedContent), comments
+}
+
 func indentCharacterAndCount(line string) (rune, int) {
 	lineCharacters := []rune(line)
 
@@ -226,12 +322,17 @@ func (lexer_ *Lexer) Next() (lexer.Token, error) {
 
 	lexer_.i++
 
+	// Comments are only of interest to tools reading the source (see `Tokens`), not the parser.
+	if token.Type == CommentToken {
+		return lexer_.Next()
+	}
+
 	return *token, nil
 }
 
 func (lexer_ *Lexer) tokens() ([]*lexer.Token, bool) {
 	if len(lexer_.fileContent) == 0 {
-		return []*lexer.Token{}, true
+		return lexer_.comments, true
 	}
 
 	matches, ok :=
@@ -242,8 +343,14 @@ func (lexer_ *Lexer) tokens() ([]*lexer.Token, bool) {
 	}
 
 	result := []*lexer.Token{}
+	comments := lexer_.comments
 
 	for _, match := range matches {
+		for len(comments) > 0 && comments[0].Pos.Offset < match.Start {
+			result = append(result, comments[0])
+			comments = comments[1:]
+		}
+
 		if match.Type != MatcherCode(SpaceToken) {
 			result = append(result, &lexer.Token{
 				Type:  lexer.TokenType(match.Type),
@@ -260,7 +367,7 @@ func (lexer_ *Lexer) tokens() ([]*lexer.Token, bool) {
 		}
 	}
 
-	return result, true
+	return append(result, comments...), true
 }
 
 /*
@@ -399,12 +506,39 @@ func (definition *LexerDefinition) Lex(filename string, reader io.Reader) (lexer
 }
 
 func (definition *LexerDefinition) LexString(filename string, fileContent string) (lexer.Lexer, error) {
+	return newLexer(filename, fileContent), nil
+}
+
+func newLexer(filename string, fileContent string) *Lexer {
+	maskedContent, comments := separateComments(filename, fileContent)
+
 	return &Lexer{
 		cachedTokens: nil,
-		fileContent:  fileContent,
+		comments:     comments,
+		fileContent:  maskedContent,
 		filename:     filename,
 		i:            0,
-	}, nil
+	}
+}
+
+/*
+ * Tokenize source as the parser would, but including comment tokens. Space tokens are omitted,
+ * and the values of string tokens retain their quotes.
+ */
+func Tokens(filename string, fileContent string) []lexer.Token {
+	tokens, ok := newLexer(filename, fileContent).tokens()
+
+	if !ok {
+		errors.RaiseError(lexer_errors.LexerFailed)
+	}
+
+	result := make([]lexer.Token, 0, len(tokens))
+
+	for _, token := range tokens {
+		result = append(result, *token)
+	}
+
+	return result
 }
 
 func (definition *LexerDefinition) Symbols() map[string]lexer.TokenType {
@@ -412,9 +546,14 @@ func (definition *LexerDefinition) Symbols() map[string]lexer.TokenType {
 		"AssignmentOperatorToken": lexer.TokenType(AssignmentOperatorToken),
 		"ColonToken":              lexer.TokenType(ColonToken),
 		"CommaToken":              lexer.TokenType(CommaToken),
+		"EllipsisToken":           lexer.TokenType(EllipsisToken),
 		"ElseKeywordToken":        lexer.TokenType(ElseKeywordToken),
 		"IfKeywordToken":          lexer.TokenType(IfKeywordToken),
+		"ImplementsKeywordToken":  lexer.TokenType(ImplementsKeywordToken),
+		"InKeywordToken":          lexer.TokenType(InKeywordToken),
+		"LetKeywordToken":         lexer.TokenType(LetKeywordToken),
 		"FloatToken":              lexer.TokenType(FloatToken),
+		"FromKeywordToken":        lexer.TokenType(FromKeywordToken),
 		"FunctionKeywordToken":    lexer.TokenType(FunctionKeywordToken),
 		"IdentifierToken":         lexer.TokenType(IdentifierToken),
 		"IndentToken":             lexer.TokenType(IndentToken),
@@ -427,6 +566,8 @@ func (definition *LexerDefinition) Symbols() map[string]lexer.TokenType {
 		"SelectOperatorToken":     lexer.TokenType(SelectOperatorToken),
 		"StringToken":             lexer.TokenType(StringToken),
 		"StructKeywordToken":      lexer.TokenType(StructKeywordToken),
+		"TraitKeywordToken":       lexer.TokenType(TraitKeywordToken),
+		"CommentToken":            lexer.TokenType(CommentToken),
 		"EOF":                     lexer.EOF,
 	}
 }
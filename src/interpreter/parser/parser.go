@@ -1,11 +1,15 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
 
 	"project_umbrella/interpreter/common"
 	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/parser_errors"
 	"project_umbrella/interpreter/parser/parser_types"
 )
 
@@ -42,20 +46,40 @@ func AbstractInfixOperation[
 
 	for _, rightHandSide := range concrete.Right() {
 		abstractRightHandSide := rightHandSide.Operand().Abstract()
+		operator := rightHandSide.Operator()
+		position := &errors.Position{
+			Filename: result.Position().Filename,
+			Start:    result.Position().Start,
+			End:      abstractRightHandSide.Position().End,
+		}
+
+		/*
+		 * Any value can be tested against a trait, so `implements` isn't a method of the left-hand
+		 * side like other infix operators.
+		 */
+		if operator.Value == "implements" {
+			result = &Call{
+				Function: &Identifier{
+					Value:    "__implements__",
+					position: nil,
+				},
+
+				Arguments: []Expression{result, abstractRightHandSide},
+				position:  position,
+			}
+
+			continue
+		}
 
 		result = &Call{
 			Function: &Select{
 				Value: result,
-				Field: rightHandSide.Operator(),
+				Field: operator,
 				Type:  parser_types.InfixSelect,
 			},
 
 			Arguments: []Expression{abstractRightHandSide},
-			position: &errors.Position{
-				Filename: result.Position().Filename,
-				Start:    result.Position().Start,
-				End:      abstractRightHandSide.Position().End,
-			},
+			position:  position,
 		}
 	}
 
@@ -80,10 +104,11 @@ type ConcretePrimary interface {
 // Statements
 
 type ConcreteAssignment struct {
-	Name   *ConcreteIdentifier `parser:"@@ (IndentToken | OutdentToken | NewlineToken)* '=':AssignmentOperatorToken (IndentToken | OutdentToken | NewlineToken)*"`
-	Tail   *ConcreteAssignment `parser:"  (@@"`
-	Value  ConcreteExpression  `parser:" | @@)"`
-	Tokens []lexer.Token
+	Pattern *ConcretePattern    `parser:"(@@"`
+	Name    *ConcreteIdentifier `parser:" | @@) (IndentToken | OutdentToken | NewlineToken)* '=':AssignmentOperatorToken (IndentToken | OutdentToken | NewlineToken)*"`
+	Tail    *ConcreteAssignment `parser:"  (@@"`
+	Value   ConcreteExpression  `parser:" | @@)"`
+	Tokens  []lexer.Token
 }
 
 func (concrete *ConcreteAssignment) Abstract() Expression {
@@ -91,15 +116,28 @@ func (concrete *ConcreteAssignment) Abstract() Expression {
 }
 
 func (concrete *ConcreteAssignment) AbstractAssignment() *Assignment {
-	names, last := common.LinkedListToSlice[ConcreteAssignment, *Identifier](
+	targets, last := common.LinkedListToSlice[ConcreteAssignment, *ConcreteAssignment](
 		concrete,
-		func(child *ConcreteAssignment) *Identifier { return child.Name.AbstractIdentifier() },
+		func(child *ConcreteAssignment) *ConcreteAssignment { return child },
 		func(child *ConcreteAssignment) *ConcreteAssignment { return child.Tail },
 	)
 
+	names := []*Identifier{}
+	patterns := []*Pattern{}
+
+	for _, target := range targets {
+		if target.Pattern == nil {
+			names = append(names, target.Name.AbstractIdentifier())
+		} else {
+			patterns = append(patterns, target.Pattern.AbstractPattern())
+		}
+	}
+
 	return &Assignment{
-		Names_: names,
-		Value:  last.Value.Abstract(),
+		Names_:      names,
+		Patterns:    patterns,
+		Value:       last.Value.Abstract(),
+		IsParameter: false,
 	}
 }
 
@@ -130,6 +168,115 @@ func (concrete *ConcreteBlock) AbstractExpressionList() *ExpressionList {
 	}
 }
 
+/*
+ * Imports values from a module by name (e.g. `from option import Some, None`). The module is named
+ * either by its dot-separated path or by a string, which also permits relative paths.
+ */
+type ConcreteFromImport struct {
+	Path       *ConcreteString       `parser:"'from':FromKeywordToken (IndentToken | OutdentToken | NewlineToken)* (@@"`
+	Components []*ConcreteIdentifier `parser:" | @@ ((IndentToken | OutdentToken | NewlineToken)* '.':SelectOperatorToken (IndentToken | OutdentToken | NewlineToken)* @@)*)"`
+	Head       *ConcreteIdentifier   `parser:"(IndentToken | OutdentToken | NewlineToken)* 'import':IdentifierToken (IndentToken | OutdentToken | NewlineToken)* @@"`
+	Tail       []*ConcreteIdentifier `parser:"((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)*"`
+	Tokens     []lexer.Token
+}
+
+/*
+ * `from option import Some, None` is equivalent to `(Some, None) = ((module): (module.Some,
+ * module.None))(import("option"))`, so the module is only imported once.
+ */
+func (concrete *ConcreteFromImport) Abstract() Expression {
+	position := tokenListSyntaxTreePosition(concrete.Tokens)
+	newModuleIdentifier := func() *Identifier {
+		return &Identifier{
+			Value:    "(module)",
+			position: position,
+		}
+	}
+
+	var moduleName string
+
+	if concrete.Path == nil {
+		components := make([]string, 0, len(concrete.Components))
+
+		for _, component := range concrete.Components {
+			components = append(components, component.Value)
+		}
+
+		moduleName = strings.Join(components, ".")
+	} else {
+		moduleName = concrete.Path.Value
+	}
+
+	names := make([]Expression, 0, len(concrete.Tail)+1)
+	selects := make([]Expression, 0, len(concrete.Tail)+1)
+
+	for _, name := range append([]*ConcreteIdentifier{concrete.Head}, concrete.Tail...) {
+		abstractName := name.AbstractIdentifier()
+
+		names = append(names, abstractName)
+		selects = append(
+			selects,
+			&Select{
+				Value: newModuleIdentifier(),
+				Field: abstractName,
+				Type:  parser_types.NormalSelect,
+			},
+		)
+	}
+
+	return &Assignment{
+		Names_: []*Identifier{},
+		Patterns: []*Pattern{
+			{
+				Constructor: nil,
+				Elements:    names,
+				position:    position,
+			},
+		},
+
+		Value: &Call{
+			Function: &Function{
+				Name:       nil,
+				Parameters: []*Identifier{newModuleIdentifier()},
+				IsVariadic: false,
+				Body: &ExpressionList{
+					Children_: []Expression{AbstractTuple(selects, position)},
+				},
+
+				position: position,
+			},
+
+			Arguments: []Expression{
+				&Call{
+					Function: &Identifier{
+						Value:    "import",
+						position: position,
+					},
+
+					Arguments: []Expression{
+						&String{
+							Value:    moduleName,
+							position: position,
+						},
+					},
+
+					position: position,
+				},
+			},
+
+			position: position,
+		},
+
+		IsParameter: false,
+	}
+}
+
+func (concrete *ConcreteFromImport) Tokens_() []lexer.Token {
+	return concrete.Tokens
+}
+
+func (*ConcreteFromImport) concreteStatement() {}
+
 type ConcreteFunction struct {
 	Name              *ConcreteIdentifier                `parser:"'fn':FunctionKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken | NewlineToken)*"`
 	ParametersAndBody *ConcreteFunctionParametersAndBody `parser:"@@"`
@@ -137,11 +284,12 @@ type ConcreteFunction struct {
 }
 
 func (concrete *ConcreteFunction) Abstract() Expression {
-	abstractParameters, abstractBody := concrete.ParametersAndBody.Abstract()
+	abstractParameters, isVariadic, abstractBody := concrete.ParametersAndBody.Abstract()
 
 	return &Function{
 		Name:       concrete.Name.AbstractIdentifier(),
 		Parameters: abstractParameters,
+		IsVariadic: isVariadic,
 		Body:       abstractBody,
 		position:   tokenListSyntaxTreePosition(concrete.Tokens),
 	}
@@ -154,14 +302,47 @@ func (concrete *ConcreteFunction) Tokens_() []lexer.Token {
 func (*ConcreteFunction) concreteStatement() {}
 
 type ConcreteFunctionOrStructParameters struct {
-	Head *ConcreteIdentifier                 `parser:"@@"`
-	Tail *ConcreteFunctionOrStructParameters `parser:"((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)?"`
+	Rest        *ConcreteIdentifier                 `parser:"  ('...':EllipsisToken (IndentToken | OutdentToken | NewlineToken)* @@"`
+	HeadPattern *ConcretePattern                    `parser:"   | @@"`
+	Head        *ConcreteIdentifier                 `parser:"   | @@)"`
+	Tail        *ConcreteFunctionOrStructParameters `parser:"  ((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)?"`
+	Tokens      []lexer.Token
 }
 
-func AbstractFunctionOrStructParameters(concrete *ConcreteFunctionOrStructParameters) []*Identifier {
-	result, _ := common.LinkedListToSlice[ConcreteFunctionOrStructParameters, *Identifier](
+/*
+ * Abstract a parameter list into a list of parameters, each of which is either an `*Identifier` or
+ * a `*Pattern`, additionally returning whether its last parameter is a rest parameter
+ * (e.g. `...rest`), which collects any remaining arguments into a tuple.
+ */
+func AbstractFunctionOrStructParameters(
+	concrete *ConcreteFunctionOrStructParameters,
+) ([]Expression, bool) {
+	result, last := common.LinkedListToSlice[ConcreteFunctionOrStructParameters, Expression](
 		concrete,
-		func(child *ConcreteFunctionOrStructParameters) *Identifier {
+		func(child *ConcreteFunctionOrStructParameters) Expression {
+			if child.Rest != nil {
+				rest := child.Rest.AbstractIdentifier()
+
+				if child.Tail != nil {
+					errors.RaisePositionalError(
+						&errors.PositionalError{
+							Error: parser_errors.RestParameterNotLast,
+							Position: &errors.Position{
+								Filename: child.Tokens[0].Pos.Filename,
+								Start:    child.Tokens[0].Pos.Offset,
+								End:      rest.Position().End,
+							},
+						},
+					)
+				}
+
+				return rest
+			}
+
+			if child.HeadPattern != nil {
+				return child.HeadPattern.AbstractPattern()
+			}
+
 			return child.Head.AbstractIdentifier()
 		},
 
@@ -170,7 +351,46 @@ func AbstractFunctionOrStructParameters(concrete *ConcreteFunctionOrStructParame
 		},
 	)
 
-	return result
+	return result, last != nil && last.Rest != nil
+}
+
+/*
+ * Functions can destructure their arguments by accepting patterns in place of parameters. We
+ * desugar each such pattern by replacing it with a placeholder parameter, which can't be
+ * referenced in code, and prepending an assignment of that placeholder to the pattern to the
+ * function's body.
+ */
+func abstractDestructuredParameters(
+	parameters []Expression,
+	body *ExpressionList,
+) ([]*Identifier, *ExpressionList) {
+	abstractParameters := make([]*Identifier, 0, len(parameters))
+	destructurings := []Expression{}
+
+	for i, parameter := range parameters {
+		switch parameter := parameter.(type) {
+		case *Identifier:
+			abstractParameters = append(abstractParameters, parameter)
+
+		case *Pattern:
+			placeholder := &Identifier{
+				Value:    fmt.Sprintf("(parameter #%d)", i+1),
+				position: parameter.Position(),
+			}
+
+			abstractParameters = append(abstractParameters, placeholder)
+			destructurings = append(destructurings, &Assignment{
+				Names_:      []*Identifier{},
+				Patterns:    []*Pattern{parameter},
+				Value:       placeholder,
+				IsParameter: true,
+			})
+		}
+	}
+
+	return abstractParameters, &ExpressionList{
+		Children_: append(destructurings, body.Children_...),
+	}
 }
 
 type ConcreteFunctionParametersAndBody struct {
@@ -178,11 +398,16 @@ type ConcreteFunctionParametersAndBody struct {
 	Body       *ConcreteBlock                      `parser:"@@"`
 }
 
-func (concrete *ConcreteFunctionParametersAndBody) Abstract() ([]*Identifier, *ExpressionList) {
-	abstractParameters := AbstractFunctionOrStructParameters(concrete.Parameters)
-	abstractBody := concrete.Body.AbstractExpressionList()
+func (concrete *ConcreteFunctionParametersAndBody) Abstract() (
+	[]*Identifier,
+	bool,
+	*ExpressionList,
+) {
+	parameters, isVariadic := AbstractFunctionOrStructParameters(concrete.Parameters)
+	abstractParameters, abstractBody :=
+		abstractDestructuredParameters(parameters, concrete.Body.AbstractExpressionList())
 
-	return abstractParameters, abstractBody
+	return abstractParameters, isVariadic, abstractBody
 }
 
 type ConcreteStatementList struct {
@@ -314,14 +539,50 @@ func (concrete *ConcreteStatementList) AbstractExpressionList() *ExpressionList
 
 type ConcreteStruct struct {
 	Name       *ConcreteIdentifier                 `parser:"'struct':StructKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken | NewlineToken)* '(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)*"`
-	Parameters *ConcreteFunctionOrStructParameters `parser:"@@ (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken NewlineToken*"`
+	Parameters *ConcreteFunctionOrStructParameters `parser:"@@ (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken"`
+	Traits     []*ConcreteSelect                   `parser:"((IndentToken | OutdentToken)* 'implements':ImplementsKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@ ((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)*)? NewlineToken*"`
 	Body       *ConcreteBlock                      `parser:"@@"`
 	Tokens     []lexer.Token
 }
 
 func (concrete *ConcreteStruct) Abstract() Expression {
+	if concrete.Parameters.Rest != nil {
+		errors.RaisePositionalError(
+			&errors.PositionalError{
+				Error:    parser_errors.SelfParameterVariadic,
+				Position: tokenListSyntaxTreePosition(concrete.Parameters.Rest.Tokens),
+			},
+		)
+	}
+
+	if concrete.Parameters.HeadPattern != nil {
+		errors.RaisePositionalError(
+			&errors.PositionalError{
+				Error:    parser_errors.StructParameterDestructured,
+				Position: concrete.Parameters.HeadPattern.AbstractPattern().Position(),
+			},
+		)
+	}
+
 	abstractBody := concrete.Body.AbstractExpressionList()
-	abstractParameters := AbstractFunctionOrStructParameters(concrete.Parameters.Tail)
+	parameters, isVariadic := AbstractFunctionOrStructParameters(concrete.Parameters.Tail)
+	abstractParameters := make([]*Identifier, 0, len(parameters))
+
+	for _, parameter := range parameters {
+		identifier, ok := parameter.(*Identifier)
+
+		if !ok {
+			errors.RaisePositionalError(
+				&errors.PositionalError{
+					Error:    parser_errors.StructParameterDestructured,
+					Position: parameter.Position(),
+				},
+			)
+		}
+
+		abstractParameters = append(abstractParameters, identifier)
+	}
+
 	argumentFields := make([]Expression, 0, len(abstractParameters))
 	nonArgumentFields := make([]Expression, 0, len(abstractParameters))
 
@@ -367,6 +628,7 @@ func (concrete *ConcreteStruct) Abstract() Expression {
 			},
 		},
 
+		IsVariadic: false,
 		Body: &ExpressionList{
 			Children_: append(abstractBody.Children(), []Expression{
 				AbstractTuple(nonArgumentFields, nil),
@@ -376,10 +638,17 @@ func (concrete *ConcreteStruct) Abstract() Expression {
 		position: nil,
 	}
 
+	traits := make([]Expression, 0, len(concrete.Traits))
+
+	for _, trait := range concrete.Traits {
+		traits = append(traits, trait.Abstract())
+	}
+
 	resultName := concrete.Name.AbstractIdentifier()
 	result := &Function{
 		Name:       resultName,
 		Parameters: abstractParameters,
+		IsVariadic: isVariadic,
 		Body:       nil,
 		position:   tokenListSyntaxTreePosition(concrete.Tokens),
 	}
@@ -401,6 +670,7 @@ func (concrete *ConcreteStruct) Abstract() Expression {
 					resultName,
 					fieldFactory,
 					AbstractTuple(argumentFields, nil),
+					AbstractTuple(traits, nil),
 				},
 
 				position: nil,
@@ -417,6 +687,103 @@ func (concrete *ConcreteStruct) Tokens_() []lexer.Token {
 
 func (*ConcreteStruct) concreteStatement() {}
 
+/*
+ * Declares a trait: the parameters after `self` name the fields a conforming struct must have, and
+ * the body's declarations are defaults for fields it may omit.
+ */
+type ConcreteTrait struct {
+	Name           *ConcreteIdentifier   `parser:"'trait':TraitKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken | NewlineToken)* '(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)*"`
+	Self           *ConcreteIdentifier   `parser:"@@"`
+	RequiredFields []*ConcreteIdentifier `parser:"((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)* (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken NewlineToken*"`
+	Body           *ConcreteBlock        `parser:"@@"`
+	Tokens         []lexer.Token
+}
+
+func (concrete *ConcreteTrait) Abstract() Expression {
+	parameters := []*Identifier{concrete.Self.AbstractIdentifier()}
+	requiredFieldNames := make([]Expression, 0, len(concrete.RequiredFields))
+
+	for _, field := range concrete.RequiredFields {
+		parameters = append(parameters, field.AbstractIdentifier())
+		requiredFieldNames = append(
+			requiredFieldNames,
+			&String{
+				Value:    field.Value,
+				position: nil,
+			},
+		)
+	}
+
+	abstractBody := concrete.Body.AbstractExpressionList()
+	defaultFields := make([]Expression, 0, len(abstractBody.Children()))
+
+	for _, statement := range abstractBody.Children() {
+		if declaration, ok := statement.(Declaration); ok {
+			for _, name := range declaration.Names() {
+				defaultFields = append(
+					defaultFields,
+					AbstractTuple(
+						[]Expression{
+							&String{
+								Value:    name.Value,
+								position: nil,
+							},
+
+							name,
+						},
+
+						nil,
+					),
+				)
+			}
+		}
+	}
+
+	defaultFieldFactory := &Function{
+		Name:       nil,
+		Parameters: parameters,
+		IsVariadic: false,
+		Body: &ExpressionList{
+			Children_: append(abstractBody.Children(), []Expression{
+				AbstractTuple(defaultFields, nil),
+			}...),
+		},
+
+		position: nil,
+	}
+
+	return &Assignment{
+		Names_:   []*Identifier{concrete.Name.AbstractIdentifier()},
+		Patterns: []*Pattern{},
+		Value: &Call{
+			Function: &Identifier{
+				Value:    "__trait__",
+				position: nil,
+			},
+
+			Arguments: []Expression{
+				&String{
+					Value:    concrete.Name.Value,
+					position: nil,
+				},
+
+				AbstractTuple(requiredFieldNames, nil),
+				defaultFieldFactory,
+			},
+
+			position: tokenListSyntaxTreePosition(concrete.Tokens),
+		},
+
+		IsParameter: false,
+	}
+}
+
+func (concrete *ConcreteTrait) Tokens_() []lexer.Token {
+	return concrete.Tokens
+}
+
+func (*ConcreteTrait) concreteStatement() {}
+
 // Multi-token expressions
 
 type ConcreteInfixMiscellaneous struct {
@@ -447,9 +814,9 @@ func (*ConcreteInfixMiscellaneous) concreteStatement()  {}
 func (*ConcreteInfixMiscellaneous) concreteExpression() {}
 
 type ConcreteInfixMiscellaneousRight struct {
-	OperatorOne *ConcreteOperator      `parser:"(((IndentToken | OutdentToken)* @@ (IndentToken | OutdentToken | NewlineToken)*)"`
-	OperatorTwo *ConcreteOperator      `parser:" | ((IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken)*))"`
-	Operand_    *ConcreteInfixAddition `parser:"@@"`
+	OperatorOne *ConcreteInfixMiscellaneousOperator `parser:"(((IndentToken | OutdentToken)* @@ (IndentToken | OutdentToken | NewlineToken)*)"`
+	OperatorTwo *ConcreteInfixMiscellaneousOperator `parser:" | ((IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken)*))"`
+	Operand_    *ConcreteInfixAddition              `parser:"@@"`
 }
 
 func (concrete *ConcreteInfixMiscellaneousRight) Operator() *Identifier {
@@ -464,6 +831,18 @@ func (concrete *ConcreteInfixMiscellaneousRight) Operand() *ConcreteInfixAdditio
 	return concrete.Operand_
 }
 
+type ConcreteInfixMiscellaneousOperator struct {
+	Identifier string `parser:"@(OperatorToken | ImplementsKeywordToken)"`
+	Tokens     []lexer.Token
+}
+
+func (concrete *ConcreteInfixMiscellaneousOperator) AbstractIdentifier() *Identifier {
+	return &Identifier{
+		Value:    concrete.Identifier,
+		position: tokenSyntaxTreePosition(&concrete.Tokens[0]),
+	}
+}
+
 type ConcreteInfixAddition struct {
 	Left_  *ConcreteInfixMultiplication  `parser:"@@"`
 	Right_ []*ConcreteInfixAdditionRight `parser:"@@*"`
@@ -600,17 +979,17 @@ func (concrete *ConcretePrefixOperation) Abstract() Expression {
 func (*ConcretePrefixOperation) concreteInfixOperand() {}
 
 type ConcreteIf struct {
-	Condition         ConcreteExpression         `parser:"('if':IfKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken | NewlineToken)*"`
-	Body              *ConcreteBlock             `parser:" @@"`
-	ElseIf            []*ConcreteElseIf          `parser:" (NewlineToken+ @@)*"`
-	Else              *ConcreteElse              `parser:" (NewlineToken+ @@)?)"`
-	AnonymousFunction *ConcreteAnonymousFunction `parser:"| @@"`
-	Tokens            []lexer.Token
+	Condition ConcreteExpression `parser:"('if':IfKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken | NewlineToken)*"`
+	Body      *ConcreteBlock     `parser:" @@"`
+	ElseIf    []*ConcreteElseIf  `parser:" (NewlineToken+ @@)*"`
+	Else      *ConcreteElse      `parser:" (NewlineToken+ @@)?)"`
+	Let       *ConcreteLet       `parser:"| @@"`
+	Tokens    []lexer.Token
 }
 
 func (concrete *ConcreteIf) Abstract() Expression {
-	if concrete.AnonymousFunction != nil {
-		return concrete.AnonymousFunction.Abstract()
+	if concrete.Let != nil {
+		return concrete.Let.Abstract()
 	}
 
 	abstractFunctionFromBody := func(concrete *ConcreteBlock) *Function {
@@ -718,6 +1097,52 @@ type ConcreteElse struct {
 	Tokens []lexer.Token
 }
 
+/*
+ * Binds values visible only within the expression following `in`, e.g.
+ * `let width = 2, height = 3 in width * height`. The bindings may also be separated by newlines.
+ */
+type ConcreteLet struct {
+	Head              *ConcreteAssignment        `parser:"('let':LetKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@"`
+	Tail              []*ConcreteAssignment      `parser:" ((((IndentToken | OutdentToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)*) | (IndentToken | OutdentToken | NewlineToken)+) @@)*"`
+	Value             ConcreteExpression         `parser:" (IndentToken | OutdentToken | NewlineToken)* 'in':InKeywordToken (IndentToken | OutdentToken | NewlineToken)* @@)"`
+	AnonymousFunction *ConcreteAnonymousFunction `parser:"| @@"`
+	Tokens            []lexer.Token
+}
+
+func (concrete *ConcreteLet) Abstract() Expression {
+	if concrete.AnonymousFunction != nil {
+		return concrete.AnonymousFunction.Abstract()
+	}
+
+	body := make([]Expression, 0, len(concrete.Tail)+2)
+
+	for _, assignment := range append([]*ConcreteAssignment{concrete.Head}, concrete.Tail...) {
+		body = append(body, assignment.Abstract())
+	}
+
+	position := tokenListSyntaxTreePosition(concrete.Tokens)
+
+	/*
+	 * The bindings are scoped by evaluating them inside an anonymous function that's called
+	 * immediately.
+	 */
+	return &Call{
+		Function: &Function{
+			Name:       nil,
+			Parameters: []*Identifier{},
+			IsVariadic: false,
+			Body: &ExpressionList{
+				Children_: append(body, concrete.Value.Abstract()),
+			},
+
+			position: position,
+		},
+
+		Arguments: []Expression{},
+		position:  position,
+	}
+}
+
 type ConcreteAnonymousFunction struct {
 	ParametersAndBody *ConcreteFunctionParametersAndBody `parser:"  (@@"`
 	Call              *ConcreteCall                      `parser:" | @@)"`
@@ -729,11 +1154,12 @@ func (concrete *ConcreteAnonymousFunction) Abstract() Expression {
 		return concrete.Call.Abstract()
 	}
 
-	abstractParameters, abstractBody := concrete.ParametersAndBody.Abstract()
+	abstractParameters, isVariadic, abstractBody := concrete.ParametersAndBody.Abstract()
 
 	return &Function{
 		Name:       nil,
 		Parameters: abstractParameters,
+		IsVariadic: isVariadic,
 		Body:       abstractBody,
 		position:   tokenListSyntaxTreePosition(concrete.Tokens),
 	}
@@ -748,7 +1174,9 @@ func (concrete *ConcreteCall) Abstract() Expression {
 	result := concrete.Left.Abstract()
 
 	for _, rightHandSide := range concrete.Right {
-		if rightHandSide.Select == nil {
+		if rightHandSide.Copy != nil {
+			result = rightHandSide.Copy.Abstract(result)
+		} else if rightHandSide.Select == nil {
 			arguments, _ := common.LinkedListToSlice[ConcreteCallArguments, Expression](
 				rightHandSide.Arguments,
 				func(child *ConcreteCallArguments) Expression { return child.Head.Abstract() },
@@ -780,15 +1208,102 @@ func (concrete *ConcreteCall) Abstract() Expression {
 
 type ConcreteCallRight struct {
 	Arguments *ConcreteCallArguments `parser:"(IndentToken | OutdentToken)* '(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)* @@? (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken"`
+	Copy      *ConcreteCopyRight     `parser:"| @@"`
 	Select    *ConcreteSelectRight   `parser:"| @@"`
 	Tokens    []lexer.Token
 }
 
+/*
+ * Copies a struct instance, replacing some of its arguments (e.g. `range.(start = 2)`). This is
+ * sugar for `range.__copy__(("start", 2))`.
+ */
+type ConcreteCopyRight struct {
+	Head   *ConcreteCopyField   `parser:"(IndentToken | OutdentToken | NewlineToken)* '.':SelectOperatorToken (IndentToken | OutdentToken | NewlineToken)* '(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)* @@"`
+	Tail   []*ConcreteCopyField `parser:"((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)* (IndentToken | OutdentToken | NewlineToken)* ','? (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken"`
+	Tokens []lexer.Token
+}
+
+func (concrete *ConcreteCopyRight) Abstract(value Expression) Expression {
+	position := &errors.Position{
+		Filename: value.Position().Filename,
+		Start:    value.Position().Start,
+		End:      tokenSyntaxTreePosition(&concrete.Tokens[len(concrete.Tokens)-1]).End,
+	}
+
+	fields := make([]Expression, 0, len(concrete.Tail)+1)
+	fieldNameSet := map[string]bool{}
+
+	for _, field := range append([]*ConcreteCopyField{concrete.Head}, concrete.Tail...) {
+		if fieldNameSet[field.Name.Value] {
+			errors.RaisePositionalError(
+				&errors.PositionalError{
+					Error:    parser_errors.CopiedFieldDuplicated(field.Name.Value),
+					Position: field.Name.Abstract().Position(),
+				},
+			)
+		}
+
+		fieldNameSet[field.Name.Value] = true
+		fields = append(
+			fields,
+			AbstractTuple(
+				[]Expression{
+					&String{
+						Value:    field.Name.Value,
+						position: field.Name.Abstract().Position(),
+					},
+
+					field.Value.Abstract(),
+				},
+
+				nil,
+			),
+		)
+	}
+
+	return &Call{
+		Function: &Select{
+			Value: value,
+			Field: &Identifier{
+				Value:    "__copy__",
+				position: position,
+			},
+
+			Type: parser_types.NormalSelect,
+		},
+
+		Arguments: fields,
+		position:  position,
+	}
+}
+
+type ConcreteCopyField struct {
+	Name  *ConcreteIdentifier `parser:"@@ (IndentToken | OutdentToken | NewlineToken)* '=':AssignmentOperatorToken (IndentToken | OutdentToken | NewlineToken)*"`
+	Value ConcreteExpression  `parser:"@@"`
+}
+
 type ConcreteCallArguments struct {
-	Head ConcreteExpression     `parser:"@@"`
+	Head *ConcreteCallArgument  `parser:"@@"`
 	Tail *ConcreteCallArguments `parser:" ((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)?"`
 }
 
+type ConcreteCallArgument struct {
+	IsSpread bool               `parser:"(@'...':EllipsisToken (IndentToken | OutdentToken | NewlineToken)*)?"`
+	Value    ConcreteExpression `parser:"@@"`
+	Tokens   []lexer.Token
+}
+
+func (concrete *ConcreteCallArgument) Abstract() Expression {
+	if !concrete.IsSpread {
+		return concrete.Value.Abstract()
+	}
+
+	return &Spread{
+		Value:    concrete.Value.Abstract(),
+		position: tokenListSyntaxTreePosition(concrete.Tokens),
+	}
+}
+
 type ConcreteSelect struct {
 	Left  ConcretePrimary        `parser:"@@"`
 	Right []*ConcreteSelectRight `parser:"@@*"`
@@ -809,13 +1324,57 @@ func (concrete *ConcreteSelect) Abstract() Expression {
 }
 
 type ConcreteSelectRight struct {
-	Field *ConcreteIdentifier `parser:"(IndentToken | OutdentToken | NewlineToken)* '.':SelectOperatorToken (IndentToken | OutdentToken | NewlineToken)* @@"`
+	Field  *ConcreteIdentifier `parser:"(IndentToken | OutdentToken | NewlineToken)* '.':SelectOperatorToken (IndentToken | OutdentToken | NewlineToken)* @@"`
+	Tokens []lexer.Token
+}
+
+type ConcretePattern struct {
+	Constructor *ConcreteIdentifier       `parser:"  (@@ (IndentToken | OutdentToken)* '(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)*"`
+	Arguments   []*ConcretePatternElement `parser:"   (@@ ((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)*)? (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken)"`
+	Elements    []*ConcretePatternElement `parser:"| ('(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)* (@@ ((IndentToken | OutdentToken | NewlineToken)* ',':CommaToken (IndentToken | OutdentToken | NewlineToken)* @@)+ | @@? (IndentToken | OutdentToken | NewlineToken)* ',':CommaToken) (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken)"`
+	Tokens      []lexer.Token
+}
+
+func (concrete *ConcretePattern) AbstractPattern() *Pattern {
+	concreteElements := concrete.Elements
+	constructor := (*Identifier)(nil)
+
+	if concrete.Constructor != nil {
+		concreteElements = concrete.Arguments
+		constructor = concrete.Constructor.AbstractIdentifier()
+	}
+
+	abstractElements := make([]Expression, 0, len(concreteElements))
+
+	for _, element := range concreteElements {
+		abstractElements = append(abstractElements, element.Abstract())
+	}
+
+	return &Pattern{
+		Constructor: constructor,
+		Elements:    abstractElements,
+		position:    tokenListSyntaxTreePosition(concrete.Tokens),
+	}
+}
+
+type ConcretePatternElement struct {
+	Pattern *ConcretePattern    `parser:"  @@"`
+	Name    *ConcreteIdentifier `parser:"| @@"`
+}
+
+func (concrete *ConcretePatternElement) Abstract() Expression {
+	if concrete.Pattern != nil {
+		return concrete.Pattern.AbstractPattern()
+	}
+
+	return concrete.Name.AbstractIdentifier()
 }
 
 // Single-token expressions and primaries
 
 type ConcreteParenthesized struct {
-	Value ConcreteExpression `parser:"'(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken"`
+	Value  ConcreteExpression `parser:"'(':LeftParenthesisToken (IndentToken | OutdentToken | NewlineToken)* @@ (IndentToken | OutdentToken | NewlineToken)* ')':RightParenthesisToken"`
+	Tokens []lexer.Token
 }
 
 func (concrete *ConcreteParenthesized) Abstract() Expression {
@@ -938,10 +1497,12 @@ func (*ConcreteString) primary() {}
 var parser = participle.MustBuild[ConcreteStatementList](
 	participle.Lexer(&LexerDefinition{}),
 	participle.Union[ConcreteStatement](
+		&ConcreteFromImport{},
 		&ConcreteAssignment{},
 		&ConcreteFunction{},
 		&ConcreteInfixMiscellaneous{},
 		&ConcreteStruct{},
+		&ConcreteTrait{},
 	),
 
 	participle.Union[ConcreteExpression](&ConcreteInfixMiscellaneous{}),
@@ -962,6 +1523,48 @@ func ParseString(path string, source string) (*ConcreteStatementList, error) {
 	return parser.ParseString(path, source)
 }
 
+/*
+ * Like `ParseString`, but raises a positional error if the source couldn't be parsed.
+ */
+func ParseSource(path string, source string) *ConcreteStatementList {
+	concreteResult, err := ParseString(path, source)
+
+	if err != nil {
+		var participleError participle.Error
+		var participleErrorPosition *errors.Position
+
+		switch err := err.(type) {
+		case *participle.ParseError:
+			participleError = err
+			participleErrorPosition = &errors.Position{
+				Filename: path,
+				Start:    err.Pos.Offset,
+				End:      err.Pos.Offset + 1,
+			}
+
+		case *participle.UnexpectedTokenError:
+			participleError = err
+			participleErrorPosition = &errors.Position{
+				Filename: path,
+				Start:    err.Unexpected.Pos.Offset,
+				End:      err.Unexpected.Pos.Offset + len(err.Unexpected.Value),
+			}
+
+		default:
+			panic(err)
+		}
+
+		errors.RaisePositionalError(
+			&errors.PositionalError{
+				Error:    parser_errors.ParserFailed(participleError),
+				Position: participleErrorPosition,
+			},
+		)
+	}
+
+	return concreteResult
+}
+
 func tokenListSyntaxTreePosition(tokens []lexer.Token) *errors.Position {
 	lastToken := tokens[len(tokens)-1]
 
@@ -15,34 +15,95 @@ type Declaration interface {
 }
 
 type Assignment struct {
-	Names_ []*Identifier
-	Value  Expression
+	Names_   []*Identifier
+	Patterns []*Pattern
+	Value    Expression
+
+	/*
+	 * Whether this assignment destructures a function's parameter, in which case, like other
+	 * parameters, the names it declares can shadow those declared outside of the function.
+	 */
+	IsParameter bool
 }
 
 func (assignment *Assignment) Children() []Expression {
-	result := make([]Expression, 0, len(assignment.Names_)+1)
+	result := make([]Expression, 0, len(assignment.Names_)+len(assignment.Patterns)+1)
 
 	for _, name := range assignment.Names_ {
 		result = append(result, name)
 	}
 
+	for _, pattern := range assignment.Patterns {
+		result = append(result, pattern)
+	}
+
 	result = append(result, assignment.Value)
 
 	return result
 }
 
 func (assignment *Assignment) Names() []*Identifier {
-	return assignment.Names_
+	result := append([]*Identifier{}, assignment.Names_...)
+
+	for _, pattern := range assignment.Patterns {
+		result = append(result, pattern.Names()...)
+	}
+
+	return result
 }
 
 func (assignment *Assignment) Position() *errors.Position {
+	start := assignment.Value.Position()
+
+	for _, name := range assignment.Names_ {
+		if name.Position().Start < start.Start {
+			start = name.Position()
+		}
+	}
+
+	for _, pattern := range assignment.Patterns {
+		if pattern.Position().Start < start.Start {
+			start = pattern.Position()
+		}
+	}
+
 	return &errors.Position{
-		Filename: assignment.Names_[0].Position().Filename,
-		Start:    assignment.Names_[0].Position().Start,
+		Filename: start.Filename,
+		Start:    start.Start,
 		End:      assignment.Value.Position().End,
 	}
 }
 
+/*
+ * Traits are desugared into assignments of a call to `__trait__` (see `ConcreteTrait.Abstract`),
+ * which is passed a function accepting the trait's required fields and returning its defaults.
+ */
+func (assignment *Assignment) TraitDefaultFieldFactory() (*Function, bool) {
+	call, ok := assignment.Value.(*Call)
+
+	if !ok || len(assignment.Names_) != 1 || !call.IsBuiltInCall("__trait__") ||
+		len(call.Arguments) != 3 {
+		return nil, false
+	}
+
+	result, ok := call.Arguments[2].(*Function)
+
+	return result, ok && len(result.Parameters) > 0
+}
+
+// See `ConcreteFromImport.Abstract`.
+func (assignment *Assignment) IsFromImport() bool {
+	call, ok := assignment.Value.(*Call)
+
+	if !ok || len(assignment.Patterns) != 1 {
+		return false
+	}
+
+	function, ok := call.Function.(*Function)
+
+	return ok && len(function.Parameters) == 1 && function.Parameters[0].Value == "(module)"
+}
+
 type ExpressionList struct {
 	Children_ []Expression
 }
@@ -121,6 +182,16 @@ func (call *Call) Position() *errors.Position {
 	return call.position
 }
 
+/*
+ * Whether the call is to a built-in function, as generated by desugaring (which leaves the built-in's
+ * identifier without a position).
+ */
+func (call *Call) IsBuiltInCall(name string) bool {
+	identifier, ok := call.Function.(*Identifier)
+
+	return ok && identifier.Value == name && identifier.Position() == nil
+}
+
 type Float struct {
 	Value    float64
 	position *errors.Position
@@ -137,6 +208,9 @@ func (float *Float) Position() *errors.Position {
 type Function struct {
 	Name       *Identifier
 	Parameters []*Identifier
+
+	// Whether the last parameter collects any remaining arguments into a tuple
+	IsVariadic bool
 	Body       *ExpressionList
 	position   *errors.Position
 }
@@ -171,6 +245,26 @@ func (function *Function) Position() *errors.Position {
 	return function.position
 }
 
+/*
+ * Structs are desugared into functions returning a call to `__struct__` (see
+ * `ConcreteStruct.Abstract`), which is passed the function creating their non-argument fields.
+ */
+func (function *Function) StructFieldFactory() (*Function, bool) {
+	if function == nil || function.Body == nil || len(function.Body.Children_) != 1 {
+		return nil, false
+	}
+
+	call, ok := function.Body.Children_[0].(*Call)
+
+	if !ok || !call.IsBuiltInCall("__struct__") || len(call.Arguments) != 5 {
+		return nil, false
+	}
+
+	result, ok := call.Arguments[2].(*Function)
+
+	return result, ok && len(result.Parameters) == 1
+}
+
 type Identifier struct {
 	Value    string
 	position *errors.Position
@@ -197,6 +291,47 @@ func (integer *Integer) Position() *errors.Position {
 	return integer.position
 }
 
+/*
+ * A tuple pattern (e.g. `(first, second)`) or struct pattern (e.g. `Range(start, end)`), which
+ * destructures a value into the elements of a tuple or the arguments of a struct, binding each
+ * element to a name or nested pattern.
+ */
+type Pattern struct {
+	Constructor *Identifier  // Should be nil if this is a tuple pattern
+	Elements    []Expression // Each element is either an `*Identifier` or a `*Pattern`
+	position    *errors.Position
+}
+
+func (pattern *Pattern) Children() []Expression {
+	result := make([]Expression, 0, len(pattern.Elements)+1)
+
+	if pattern.Constructor != nil {
+		result = append(result, pattern.Constructor)
+	}
+
+	return append(result, pattern.Elements...)
+}
+
+func (pattern *Pattern) Names() []*Identifier {
+	result := []*Identifier{}
+
+	for _, element := range pattern.Elements {
+		switch element := element.(type) {
+		case *Identifier:
+			result = append(result, element)
+
+		case *Pattern:
+			result = append(result, element.Names()...)
+		}
+	}
+
+	return result
+}
+
+func (pattern *Pattern) Position() *errors.Position {
+	return pattern.position
+}
+
 type Select struct {
 	Value Expression
 	Field *Identifier
@@ -215,6 +350,23 @@ func (select_ *Select) Position() *errors.Position {
 	}
 }
 
+/*
+ * A tuple whose elements are passed as individual arguments (e.g. `...arguments`). Spreads are only
+ * valid as call arguments.
+ */
+type Spread struct {
+	Value    Expression
+	position *errors.Position
+}
+
+func (spread *Spread) Children() []Expression {
+	return []Expression{spread.Value}
+}
+
+func (spread *Spread) Position() *errors.Position {
+	return spread.position
+}
+
 type String struct {
 	Value    string
 	position *errors.Position
@@ -0,0 +1,13 @@
+package parser_types
+
+import "strings"
+
+/*
+ * Names beginning with an underscore are private: they're visible to the code declaring them, but
+ * aren't exposed as fields. Built-in fields (e.g. `__to_str__`) are the exception.
+ */
+func IsPrivateName(name string) bool {
+	isBuiltIn := len(name) > 4 && strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__")
+
+	return strings.HasPrefix(name, "_") && !isBuiltIn
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"project_umbrella/interpreter/profiler"
+	"project_umbrella/interpreter/runtime"
+)
+
+/*
+ * `interpreter run --profile <output> <file>` runs the given file, then writes a profile of the
+ * Krait functions it called to the output file: a `pprof` profile, or folded stacks for flame
+ * graphs if `--folded` is also given. The profile is written even if the program fails.
+ */
+func profileRun(
+	instruments *runtime.Instruments,
+	outputPath string,
+	isFolded bool,
+) instrumentOutput {
+	profiler_ := profiler.NewProfiler()
+	instruments.Profiler = profiler_
+
+	if isFolded {
+		return instrumentOutput{
+			path:  outputPath,
+			write: profiler_.WriteFoldedStacks,
+		}
+	}
+
+	return instrumentOutput{
+		path:  outputPath,
+		write: profiler_.WritePprof,
+	}
+}
@@ -0,0 +1,249 @@
+package profiler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"project_umbrella/interpreter/errors"
+)
+
+/*
+ * Write every stack called from, one per line, as its functions' names separated by semicolons
+ * followed by the exclusive wall time (in nanoseconds) spent there: the "folded stacks" format read
+ * by flame graph tools.
+ */
+func (profiler *Profiler) WriteFoldedStacks(writer io.Writer) error {
+	bufferedWriter := bufio.NewWriter(writer)
+
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+
+	profiler.root.walk(func(node *stackNode) {
+		names := []string{}
+
+		for _, function := range node.functions() {
+			names = append(names, function.Name)
+		}
+
+		fmt.Fprintf(bufferedWriter, "%s %d\n", strings.Join(names, ";"), node.wallTime)
+	})
+
+	return bufferedWriter.Flush()
+}
+
+/*
+ * Write a gzipped `profile.proto` message (see https://github.com/google/pprof), as read by
+ * `go tool pprof`, whose samples are the stacks called from. Each function has a single location:
+ * the line declaring it.
+ */
+func (profiler *Profiler) WritePprof(writer io.Writer) error {
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+
+	strings_ := newStringTable()
+	functionIDs := map[Function]uint64{}
+	functions := []Function{}
+	profile := &protobufMessage{}
+
+	for _, sampleType := range [][2]string{
+		{"calls", "count"},
+		{"wall", "nanoseconds"},
+		{"alloc_space", "bytes"},
+		{"alloc_objects", "count"},
+	} {
+		profile.message(1, func(valueType *protobufMessage) {
+			valueType.integer(1, strings_.id(sampleType[0]))
+			valueType.integer(2, strings_.id(sampleType[1]))
+		})
+	}
+
+	profiler.root.walk(func(node *stackNode) {
+		stack := node.functions()
+		locationIDs := make([]int64, 0, len(stack))
+
+		// Samples' locations are listed from the function called last
+		for i := len(stack) - 1; i >= 0; i-- {
+			id, ok := functionIDs[stack[i]]
+
+			if !ok {
+				functions = append(functions, stack[i])
+				id = uint64(len(functions))
+				functionIDs[stack[i]] = id
+			}
+
+			locationIDs = append(locationIDs, int64(id))
+		}
+
+		profile.message(2, func(sample *protobufMessage) {
+			sample.packedIntegers(1, locationIDs)
+			sample.packedIntegers(2, []int64{
+				node.calls,
+				int64(node.wallTime),
+				node.allocatedBytes,
+				node.allocatedObjects,
+			})
+		})
+	})
+
+	for i, function := range functions {
+		id := int64(i + 1)
+		line := function.line()
+
+		profile.message(4, func(location *protobufMessage) {
+			location.integer(1, id)
+			location.message(4, func(line_ *protobufMessage) {
+				line_.integer(1, id)
+				line_.integer(2, line)
+			})
+		})
+
+		profile.message(5, func(function_ *protobufMessage) {
+			function_.integer(1, id)
+			function_.integer(2, strings_.id(function.Name))
+			function_.integer(3, strings_.id(function.Name))
+			function_.integer(4, strings_.id(function.Filename))
+			function_.integer(5, line)
+		})
+	}
+
+	for _, string_ := range strings_.strings {
+		profile.string(6, string_)
+	}
+
+	profile.integer(9, profiler.start.UnixNano())
+	profile.integer(10, int64(time.Since(profiler.start)))
+	profile.message(11, func(valueType *protobufMessage) {
+		valueType.integer(1, strings_.id("wall"))
+		valueType.integer(2, strings_.id("nanoseconds"))
+	})
+
+	gzipWriter := gzip.NewWriter(writer)
+
+	if _, err := gzipWriter.Write(profile.bytes); err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+func sortedChildren(node *stackNode) []*stackNode {
+	result := make([]*stackNode, 0, len(node.children))
+
+	for _, child := range node.children {
+		result = append(result, child)
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		if result[i].function.Name != result[j].function.Name {
+			return result[i].function.Name < result[j].function.Name
+		}
+
+		if result[i].function.Filename != result[j].function.Filename {
+			return result[i].function.Filename < result[j].function.Filename
+		}
+
+		return result[i].function.Offset < result[j].function.Offset
+	})
+
+	return result
+}
+
+// `profile.proto` refers to strings by their index in a table starting with the empty string.
+type stringTable struct {
+	strings []string
+	ids     map[string]int64
+}
+
+func newStringTable() *stringTable {
+	return &stringTable{
+		strings: []string{""},
+		ids:     map[string]int64{"": 0},
+	}
+}
+
+func (table *stringTable) id(string_ string) int64 {
+	if result, ok := table.ids[string_]; ok {
+		return result
+	}
+
+	result := int64(len(table.strings))
+
+	table.strings = append(table.strings, string_)
+	table.ids[string_] = result
+
+	return result
+}
+
+// The line declaring the function, or 0 if its file can't be read
+func (function Function) line() int64 {
+	line, _ := (&errors.Position{
+		Filename: function.Filename,
+		Start:    function.Offset,
+		End:      function.Offset,
+	}).StartLineAndColumn()
+
+	return int64(line)
+}
+
+/*
+ * A protocol buffer message, encoded as its fields are added. Only the wire types `profile.proto`
+ * uses are supported.
+ */
+type protobufMessage struct {
+	bytes []byte
+}
+
+const (
+	varintWireType          = 0
+	lengthDelimitedWireType = 2
+)
+
+func (message *protobufMessage) varint(value uint64) {
+	for value >= 0x80 {
+		message.bytes = append(message.bytes, byte(value)|0x80)
+		value >>= 7
+	}
+
+	message.bytes = append(message.bytes, byte(value))
+}
+
+func (message *protobufMessage) key(field int, wireType int) {
+	message.varint(uint64(field<<3 | wireType))
+}
+
+func (message *protobufMessage) integer(field int, value int64) {
+	message.key(field, varintWireType)
+	message.varint(uint64(value))
+}
+
+func (message *protobufMessage) packedIntegers(field int, values []int64) {
+	packed := &protobufMessage{}
+
+	for _, value := range values {
+		packed.varint(uint64(value))
+	}
+
+	message.key(field, lengthDelimitedWireType)
+	message.varint(uint64(len(packed.bytes)))
+	message.bytes = append(message.bytes, packed.bytes...)
+}
+
+func (message *protobufMessage) string(field int, value string) {
+	message.key(field, lengthDelimitedWireType)
+	message.varint(uint64(len(value)))
+	message.bytes = append(message.bytes, value...)
+}
+
+func (message *protobufMessage) message(field int, build func(submessage *protobufMessage)) {
+	submessage := &protobufMessage{}
+
+	build(submessage)
+	message.key(field, lengthDelimitedWireType)
+	message.varint(uint64(len(submessage.bytes)))
+	message.bytes = append(message.bytes, submessage.bytes...)
+}
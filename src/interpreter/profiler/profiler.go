@@ -0,0 +1,192 @@
+/*
+ * The Profiler:
+ *
+ * When profiling, each call to a function declared in Krait is recorded at the stack of functions
+ * it was called from, along with the wall time elapsed and the memory allocated during the call.
+ * Time and allocations are exclusive (i.e. those of the functions a function calls are recorded at
+ * their own stacks), so a function's inclusive figures are the sum of those recorded at every
+ * stack containing it.
+ *
+ * Functions generated while desugaring (e.g. the branches of `if` expressions) and functions
+ * implemented in Go (e.g. `print`) aren't recorded; their time and allocations are attributed to
+ * the Krait function calling them.
+ *
+ * Allocations are those the Go runtime reports for the whole process, so they're only precise when
+ * a single module is being evaluated (modules are imported concurrently).
+ */
+package profiler
+
+import (
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// A function declared in Krait source
+type Function struct {
+	Name     string
+	Filename string
+	Offset   int // Where the function is declared in its file
+}
+
+type Profiler struct {
+	start time.Time
+	mutex sync.Mutex
+
+	// The tree of every stack called from so far, rooted at each module being evaluated
+	root *stackNode
+}
+
+func NewProfiler() *Profiler {
+	return &Profiler{
+		start: time.Now(),
+		root:  newStackNode(nil, Function{}),
+	}
+}
+
+/*
+ * A stack of functions, identified by the function called last (`function`) and the stack it was
+ * called from (`parent`), holding the exclusive figures recorded at that stack.
+ */
+type stackNode struct {
+	parent   *stackNode
+	function Function
+	children map[Function]*stackNode
+
+	calls            int64
+	wallTime         time.Duration
+	allocatedBytes   int64
+	allocatedObjects int64
+}
+
+func newStackNode(parent *stackNode, function Function) *stackNode {
+	return &stackNode{
+		parent:   parent,
+		function: function,
+		children: map[Function]*stackNode{},
+	}
+}
+
+// The stack's functions, from the first called to the last
+func (node *stackNode) functions() []Function {
+	result := []Function{}
+
+	for ; node.parent != nil; node = node.parent {
+		result = append([]Function{node.function}, result...)
+	}
+
+	return result
+}
+
+// Visit every stack in the tree rooted at `node` that has been called, in order of their functions
+func (node *stackNode) walk(visit func(node *stackNode)) {
+	if node.calls > 0 {
+		visit(node)
+	}
+
+	for _, child := range sortedChildren(node) {
+		child.walk(visit)
+	}
+}
+
+/*
+ * The calls being made by a single runtime. Each module is evaluated by its own runtime (and
+ * goroutine), so functions are called in a strictly nested order within one.
+ */
+type CallStack struct {
+	profiler *Profiler
+	frames   []*frame
+
+	// Reused to read the allocation metrics without allocating
+	metricSamples []metrics.Sample
+}
+
+type frame struct {
+	node       *stackNode
+	start      time.Time
+	allocation allocation
+
+	// The inclusive figures of the calls made during this one, to be excluded from its own
+	childWallTime   time.Duration
+	childAllocation allocation
+}
+
+type allocation struct {
+	bytes   int64
+	objects int64
+}
+
+func (profiler *Profiler) NewCallStack() *CallStack {
+	return &CallStack{
+		profiler: profiler,
+		frames:   []*frame{},
+		metricSamples: []metrics.Sample{
+			{Name: "/gc/heap/allocs:bytes"},
+			{Name: "/gc/heap/allocs:objects"},
+		},
+	}
+}
+
+// Record the start of a call to `function`, which must be followed by a call to `Pop`.
+func (stack *CallStack) Push(function *Function) {
+	parent := stack.profiler.root
+
+	if len(stack.frames) > 0 {
+		parent = stack.frames[len(stack.frames)-1].node
+	}
+
+	stack.profiler.mutex.Lock()
+
+	node, ok := parent.children[*function]
+
+	if !ok {
+		node = newStackNode(parent, *function)
+		parent.children[*function] = node
+	}
+
+	stack.profiler.mutex.Unlock()
+
+	stack.frames = append(stack.frames, &frame{
+		node:       node,
+		allocation: stack.allocation(),
+		start:      time.Now(),
+	})
+}
+
+// Record the end of the call most recently started.
+func (stack *CallStack) Pop() {
+	end := time.Now()
+	endAllocation := stack.allocation()
+	frame_ := stack.frames[len(stack.frames)-1]
+	stack.frames = stack.frames[:len(stack.frames)-1]
+
+	wallTime := end.Sub(frame_.start)
+	allocation_ := allocation{
+		bytes:   endAllocation.bytes - frame_.allocation.bytes,
+		objects: endAllocation.objects - frame_.allocation.objects,
+	}
+
+	if len(stack.frames) > 0 {
+		parent := stack.frames[len(stack.frames)-1]
+		parent.childWallTime += wallTime
+		parent.childAllocation.bytes += allocation_.bytes
+		parent.childAllocation.objects += allocation_.objects
+	}
+
+	stack.profiler.mutex.Lock()
+	defer stack.profiler.mutex.Unlock()
+
+	frame_.node.calls++
+	frame_.node.wallTime += wallTime - frame_.childWallTime
+	frame_.node.allocatedBytes += allocation_.bytes - frame_.childAllocation.bytes
+	frame_.node.allocatedObjects += allocation_.objects - frame_.childAllocation.objects
+}
+
+func (stack *CallStack) allocation() allocation {
+	metrics.Read(stack.metricSamples)
+
+	return allocation{
+		bytes:   int64(stack.metricSamples[0].Value.Uint64()),
+		objects: int64(stack.metricSamples[1].Value.Uint64()),
+	}
+}
@@ -0,0 +1,68 @@
+package project
+
+import (
+	standard_errors "errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/project_errors"
+)
+
+const lockfileHeader = "# Generated by `interpreter deps lock`. Don't edit this file by hand.\n\n"
+
+/*
+ * A `krait.lock` lockfile, recording every package the project transitively depends on. Paths are
+ * relative to the project directory.
+ */
+type Lockfile struct {
+	Packages []*LockedPackage `toml:"package"`
+}
+
+type LockedPackage struct {
+	Name         string   `toml:"name"`
+	Version      string   `toml:"version"`
+	Path         string   `toml:"path,omitempty"`
+	Archive      string   `toml:"archive,omitempty"`
+	Checksum     string   `toml:"checksum"`
+	Dependencies []string `toml:"dependencies,omitempty"`
+}
+
+func (project *Project) ReadLockfile() *Lockfile {
+	path := filepath.Join(project.Directory, LockfileFileName)
+	lockfile := &Lockfile{}
+
+	if _, err := toml.DecodeFile(path, lockfile); err != nil {
+		if standard_errors.Is(err, fs.ErrNotExist) {
+			errors.RaiseError(project_errors.LockfileNotFound)
+		}
+
+		errors.RaiseError(project_errors.ManifestInvalid(path, err))
+	}
+
+	return lockfile
+}
+
+func (project *Project) WriteLockfile(lockfile *Lockfile) {
+	file, err := os.Create(filepath.Join(project.Directory, LockfileFileName))
+
+	if err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	defer file.Close()
+
+	if _, err := file.WriteString(lockfileHeader); err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	encoder := toml.NewEncoder(file)
+	encoder.Indent = ""
+
+	if err := encoder.Encode(lockfile); err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+}
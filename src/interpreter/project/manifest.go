@@ -0,0 +1,189 @@
+package project
+
+import (
+	standard_errors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/project_errors"
+)
+
+const ManifestFileName = "krait.toml"
+const LockfileFileName = "krait.lock"
+const VendorDirectoryName = "krait_modules"
+
+/*
+ * A `krait.toml` manifest, e.g.:
+ *
+ *     [package]
+ *     name = "application"
+ *     version = "0.1.0"
+ *     sources = ["src"]
+ *
+ *     [dependencies]
+ *     utilities = { path = "../utilities" }
+ *     json = { archive = "archives/json.tar.gz", version = "1.2.0" }
+ *
+ * Dependency paths are relative to the directory containing the manifest (or, for a package
+ * inside an archive, the directory containing the archive).
+ */
+type Manifest struct {
+	Package      *PackageMetadata                    `toml:"package"`
+	Dependencies map[string]*DependencySpecification `toml:"dependencies"`
+}
+
+type PackageMetadata struct {
+	Name    string   `toml:"name"`
+	Version string   `toml:"version"`
+	Sources []string `toml:"sources"`
+}
+
+/*
+ * Exactly one of `Path` (a directory containing a manifest) and `Archive` (a `.tar.gz` file with
+ * a manifest at its root) is set. `Version`, if set, must match the dependency's own manifest.
+ */
+type DependencySpecification struct {
+	Path    string `toml:"path"`
+	Archive string `toml:"archive"`
+	Version string `toml:"version"`
+}
+
+func LoadManifest(path string) *Manifest {
+	file, err := os.Open(path)
+
+	if err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	defer file.Close()
+
+	return parseManifest(path, file)
+}
+
+func parseManifest(path string, reader io.Reader) *Manifest {
+	manifest := &Manifest{}
+
+	if _, err := toml.NewDecoder(reader).Decode(manifest); err != nil {
+		errors.RaiseError(project_errors.ManifestInvalid(path, err))
+	}
+
+	if err := manifest.validate(); err != nil {
+		errors.RaiseError(project_errors.ManifestInvalid(path, err))
+	}
+
+	if len(manifest.Package.Sources) == 0 {
+		manifest.Package.Sources = []string{"."}
+	}
+
+	return manifest
+}
+
+func (manifest *Manifest) validate() error {
+	if manifest.Package == nil {
+		return standard_errors.New("the [package] table is missing")
+	}
+
+	if manifest.Package.Name == "" || manifest.Package.Version == "" {
+		return standard_errors.New("packages must have a name and a version")
+	}
+
+	for _, source := range manifest.Package.Sources {
+		if !filepath.IsLocal(source) {
+			return fmt.Errorf("the source directory \"%s\" is outside of the package", source)
+		}
+	}
+
+	for name, dependency := range manifest.Dependencies {
+		if (dependency.Path == "") == (dependency.Archive == "") {
+			return fmt.Errorf(
+				"the dependency \"%s\" must specify exactly one of a path and an archive",
+				name,
+			)
+		}
+	}
+
+	return nil
+}
+
+type Project struct {
+	Directory string
+	Manifest  *Manifest
+}
+
+/*
+ * Find the project containing `directory` by searching it and its ancestors for a manifest.
+ */
+func FindProject(directory string) (*Project, bool) {
+	currentDirectory, err := filepath.Abs(directory)
+
+	if err != nil {
+		return nil, false
+	}
+
+	for {
+		manifestPath := filepath.Join(currentDirectory, ManifestFileName)
+
+		if info, err := os.Stat(manifestPath); err == nil && info.Mode().IsRegular() {
+			return &Project{
+				Directory: currentDirectory,
+				Manifest:  LoadManifest(manifestPath),
+			}, true
+		}
+
+		parentDirectory := filepath.Dir(currentDirectory)
+
+		if parentDirectory == currentDirectory {
+			return nil, false
+		}
+
+		currentDirectory = parentDirectory
+	}
+}
+
+/*
+ * The directories searched for modules imported from a file in `importingDirectory`, within the
+ * project, in order: vendored dependencies come first, followed by the project's own source
+ * directories. Files in a vendored package search its own directory before either, so that its
+ * modules can import each other as they did before being vendored.
+ */
+func (project *Project) ModuleDirectories(importingDirectory string) []string {
+	vendorDirectory := filepath.Join(project.Directory, VendorDirectoryName)
+	result := []string{vendorDirectory}
+
+	if packageDirectory, ok := vendoredPackageDirectory(vendorDirectory, importingDirectory); ok {
+		result = append([]string{packageDirectory}, result...)
+	}
+
+	for _, source := range project.Manifest.Package.Sources {
+		result = append(result, filepath.Join(project.Directory, source))
+	}
+
+	return result
+}
+
+/*
+ * Find the directory of the vendored package (in `vendorDirectory`) containing `directory`.
+ */
+func vendoredPackageDirectory(vendorDirectory string, directory string) (string, bool) {
+	absoluteDirectory, err := filepath.Abs(directory)
+
+	if err != nil {
+		return "", false
+	}
+
+	relativePath, err := filepath.Rel(vendorDirectory, absoluteDirectory)
+
+	if err != nil || relativePath == "." || !filepath.IsLocal(relativePath) {
+		return "", false
+	}
+
+	packageName, _, _ := strings.Cut(filepath.ToSlash(relativePath), "/")
+
+	return filepath.Join(vendorDirectory, packageName), true
+}
@@ -0,0 +1,270 @@
+package project
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/project_errors"
+)
+
+/*
+ * Where a dependency's files come from: either a directory or a `.tar.gz` archive.
+ */
+type packageSource interface {
+	manifest() *Manifest
+	checksum() string
+
+	/*
+	 * Call `callback` with each file inside the given source directories of the package, along
+	 * with its slash-separated path relative to the source directory containing it.
+	 */
+	walkSourceFiles(sources []string, callback func(relativePath string, reader io.Reader))
+
+	/*
+	 * The directory that paths in the package's own dependency specifications are relative to.
+	 */
+	dependencyBaseDirectory() string
+}
+
+func newPackageSource(
+	baseDirectory string,
+	dependencyName string,
+	dependency *DependencySpecification,
+) packageSource {
+	if dependency.Path != "" {
+		path_ := filepath.Join(baseDirectory, dependency.Path)
+
+		if info, err := os.Stat(filepath.Join(path_, ManifestFileName)); err != nil ||
+			!info.Mode().IsRegular() {
+			errors.RaiseError(project_errors.DependencyNotFound(dependencyName, path_))
+		}
+
+		return &directorySource{
+			directory: path_,
+		}
+	}
+
+	path_ := filepath.Join(baseDirectory, dependency.Archive)
+
+	if info, err := os.Stat(path_); err != nil || !info.Mode().IsRegular() {
+		errors.RaiseError(project_errors.DependencyNotFound(dependencyName, path_))
+	}
+
+	return &archiveSource{
+		path: path_,
+	}
+}
+
+type directorySource struct {
+	directory string
+}
+
+func (source *directorySource) manifest() *Manifest {
+	return LoadManifest(filepath.Join(source.directory, ManifestFileName))
+}
+
+/*
+ * The checksum of a directory covers the names and contents of every file in it, except for
+ * files generated by `interpreter deps`.
+ */
+func (source *directorySource) checksum() string {
+	hash := sha256.New()
+
+	source.walkDirectory(source.directory, func(relativePath string, reader io.Reader) {
+		fmt.Fprintf(hash, "%s\x00", relativePath)
+
+		if _, err := io.Copy(hash, reader); err != nil {
+			errors.RaiseError(project_errors.ProjectIOFailed(err))
+		}
+	})
+
+	return fmt.Sprintf("sha256:%s", hex.EncodeToString(hash.Sum(nil)))
+}
+
+func (source *directorySource) walkSourceFiles(
+	sources []string,
+	callback func(relativePath string, reader io.Reader),
+) {
+	for _, sourceDirectory := range sources {
+		source.walkDirectory(filepath.Join(source.directory, sourceDirectory), callback)
+	}
+}
+
+func (source *directorySource) walkDirectory(
+	directory string,
+	callback func(relativePath string, reader io.Reader),
+) {
+	err := filepath.WalkDir(directory, func(path_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path_ != directory && entry.Name() == VendorDirectoryName {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || entry.Name() == LockfileFileName {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(directory, path_)
+
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path_)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		callback(filepath.ToSlash(relativePath), file)
+
+		return nil
+	})
+
+	if err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+}
+
+func (source *directorySource) dependencyBaseDirectory() string {
+	return source.directory
+}
+
+type archiveSource struct {
+	path string
+}
+
+func (source *archiveSource) manifest() *Manifest {
+	var result *Manifest
+
+	source.walkArchive(func(name string, reader io.Reader) {
+		if name == ManifestFileName {
+			result = parseManifest(path.Join(source.path, ManifestFileName), reader)
+		}
+	})
+
+	if result == nil {
+		errors.RaiseError(
+			project_errors.DependencyArchiveInvalid(
+				source.path,
+				fmt.Errorf("the archive doesn't contain %s at its root", ManifestFileName),
+			),
+		)
+	}
+
+	return result
+}
+
+func (source *archiveSource) checksum() string {
+	file, err := os.Open(source.path)
+
+	if err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, file); err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	return fmt.Sprintf("sha256:%s", hex.EncodeToString(hash.Sum(nil)))
+}
+
+func (source *archiveSource) walkSourceFiles(
+	sources []string,
+	callback func(relativePath string, reader io.Reader),
+) {
+	source.walkArchive(func(name string, reader io.Reader) {
+		for _, sourceDirectory := range sources {
+			sourceDirectory = path.Clean(filepath.ToSlash(sourceDirectory))
+
+			if sourceDirectory == "." {
+				callback(name, reader)
+
+				return
+			}
+
+			if relativePath, ok := strings.CutPrefix(name, sourceDirectory+"/"); ok {
+				callback(relativePath, reader)
+
+				return
+			}
+		}
+	})
+}
+
+/*
+ * Call `callback` with each regular file in the archive, along with its cleaned path.
+ */
+func (source *archiveSource) walkArchive(callback func(name string, reader io.Reader)) {
+	file, err := os.Open(source.path)
+
+	if err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+
+	if err != nil {
+		errors.RaiseError(project_errors.DependencyArchiveInvalid(source.path, err))
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			errors.RaiseError(project_errors.DependencyArchiveInvalid(source.path, err))
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+
+		if !filepath.IsLocal(name) {
+			errors.RaiseError(
+				project_errors.DependencyArchiveInvalid(
+					source.path,
+					fmt.Errorf("the entry \"%s\" is outside of the archive", header.Name),
+				),
+			)
+		}
+
+		callback(name, tarReader)
+	}
+}
+
+func (source *archiveSource) dependencyBaseDirectory() string {
+	return filepath.Dir(source.path)
+}
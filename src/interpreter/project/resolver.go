@@ -0,0 +1,228 @@
+package project
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/project_errors"
+)
+
+type pendingDependency struct {
+	requirerName   string
+	baseDirectory  string
+	dependencyName string
+	dependency     *DependencySpecification
+}
+
+/*
+ * Resolve the project's dependencies, and theirs, into a lockfile.
+ *
+ * Vendored packages share a single directory, so every package requiring a given dependency must
+ * agree on its version.
+ */
+func (project *Project) Resolve() *Lockfile {
+	resolved := map[string]*LockedPackage{}
+	requirers := map[string][][2]string{}
+	queue := project.pendingDependencies(
+		project.Manifest.Package.Name,
+		project.Directory,
+		project.Manifest,
+	)
+
+	for len(queue) > 0 {
+		pending := queue[0]
+		queue = queue[1:]
+
+		source := newPackageSource(
+			pending.baseDirectory,
+			pending.dependencyName,
+			pending.dependency,
+		)
+
+		manifest := source.manifest()
+		version := manifest.Package.Version
+
+		if manifest.Package.Name != pending.dependencyName {
+			errors.RaiseError(
+				project_errors.DependencyNameMismatch(
+					pending.dependencyName,
+					manifest.Package.Name,
+				),
+			)
+		}
+
+		if pending.dependency.Version != "" && pending.dependency.Version != version {
+			errors.RaiseError(
+				project_errors.DependencyVersionMismatch(
+					pending.dependencyName,
+					pending.dependency.Version,
+					version,
+				),
+			)
+		}
+
+		requirers[pending.dependencyName] = append(
+			requirers[pending.dependencyName],
+			[2]string{pending.requirerName, version},
+		)
+
+		if existing, ok := resolved[pending.dependencyName]; ok {
+			if existing.Version != version {
+				errors.RaiseError(
+					project_errors.DependencyVersionConflict(
+						pending.dependencyName,
+						requirers[pending.dependencyName],
+					),
+				)
+			}
+
+			continue
+		}
+
+		locked := &LockedPackage{
+			Name:     pending.dependencyName,
+			Version:  version,
+			Checksum: source.checksum(),
+		}
+
+		if pending.dependency.Path != "" {
+			locked.Path = project.relativePath(source.dependencyBaseDirectory())
+		} else {
+			locked.Archive = project.relativePath(
+				filepath.Join(pending.baseDirectory, pending.dependency.Archive),
+			)
+		}
+
+		dependencies := project.pendingDependencies(
+			pending.dependencyName,
+			source.dependencyBaseDirectory(),
+			manifest,
+		)
+
+		for _, dependency := range dependencies {
+			locked.Dependencies = append(locked.Dependencies, dependency.dependencyName)
+		}
+
+		resolved[pending.dependencyName] = locked
+		queue = append(queue, dependencies...)
+	}
+
+	result := &Lockfile{
+		Packages: make([]*LockedPackage, 0, len(resolved)),
+	}
+
+	for _, locked := range resolved {
+		result.Packages = append(result.Packages, locked)
+	}
+
+	slices.SortFunc(result.Packages, func(left *LockedPackage, right *LockedPackage) int {
+		if left.Name < right.Name {
+			return -1
+		} else if left.Name > right.Name {
+			return 1
+		}
+
+		return 0
+	})
+
+	return result
+}
+
+func (project *Project) pendingDependencies(
+	requirerName string,
+	baseDirectory string,
+	manifest *Manifest,
+) []*pendingDependency {
+	names := make([]string, 0, len(manifest.Dependencies))
+
+	for name := range manifest.Dependencies {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	result := make([]*pendingDependency, 0, len(names))
+
+	for _, name := range names {
+		result = append(result, &pendingDependency{
+			requirerName:   requirerName,
+			baseDirectory:  baseDirectory,
+			dependencyName: name,
+			dependency:     manifest.Dependencies[name],
+		})
+	}
+
+	return result
+}
+
+func (project *Project) relativePath(path string) string {
+	result, err := filepath.Rel(project.Directory, path)
+
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(result)
+}
+
+/*
+ * Copy the source directories of every locked package into the project's vendor directory,
+ * replacing whatever it previously contained. Each package's modules are then importable as
+ * `<package name>.<module name>`.
+ */
+func (project *Project) Vendor(lockfile *Lockfile, onVendored func(*LockedPackage)) {
+	vendorDirectory := filepath.Join(project.Directory, VendorDirectoryName)
+
+	if err := os.RemoveAll(vendorDirectory); err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	for _, locked := range lockfile.Packages {
+		source := newPackageSource(project.Directory, locked.Name, &DependencySpecification{
+			Path:    filepath.FromSlash(locked.Path),
+			Archive: filepath.FromSlash(locked.Archive),
+		})
+
+		if source.checksum() != locked.Checksum {
+			errors.RaiseError(project_errors.LockfileOutdated(locked.Name))
+		}
+
+		packageDirectory := filepath.Join(vendorDirectory, locked.Name)
+
+		source.walkSourceFiles(
+			source.manifest().Package.Sources,
+			func(relativePath string, reader io.Reader) {
+				// Vendored manifests would make the vendored package look like a project of its
+				// own when searching for the project containing an imported module.
+				if relativePath == ManifestFileName {
+					return
+				}
+
+				writeVendoredFile(filepath.Join(packageDirectory, relativePath), reader)
+			},
+		)
+
+		onVendored(locked)
+	}
+}
+
+func writeVendoredFile(path string, reader io.Reader) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	file, err := os.Create(path)
+
+	if err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		errors.RaiseError(project_errors.ProjectIOFailed(err))
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !unix
+
+package main
+
+import (
+	standard_errors "errors"
+	"os"
+	"os/exec"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+)
+
+/*
+ * Processes can't replace themselves on every platform, so the new instance of the interpreter is
+ * run as a child instead, and its exit code is exited with.
+ */
+func restartInterpreter() {
+	executablePath, err := os.Executable()
+
+	if err != nil {
+		errors.RaiseError(entry_errors.InterpreterNotRestarted)
+	}
+
+	command := exec.Command(executablePath, os.Args[1:]...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	err = command.Run()
+
+	if exitError := (*exec.ExitError)(nil); standard_errors.As(err, &exitError) {
+		os.Exit(exitError.ExitCode())
+	} else if err != nil {
+		errors.RaiseError(entry_errors.InterpreterNotRestarted)
+	}
+
+	os.Exit(0)
+}
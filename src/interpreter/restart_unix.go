@@ -0,0 +1,20 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+)
+
+func restartInterpreter() {
+	// Replacing the interpreter only returns if it fails.
+	if executablePath, err := os.Executable(); err == nil {
+		syscall.Exec(executablePath, os.Args, os.Environ())
+	}
+
+	errors.RaiseError(entry_errors.InterpreterNotRestarted)
+}
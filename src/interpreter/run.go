@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/loader/module_loader"
+	"project_umbrella/interpreter/runtime"
+)
+
+// How often the files a watched program opened are checked for changes
+const watchInterval = 250 * time.Millisecond
+
+/*
+ * `interpreter run <file>` runs the given file, like `interpreter <file>`. With `--watch`, it keeps
+ * running it again whenever any of the files it opened (its own, those of the modules it imported,
+ * the startup file and native libraries) changes, loading only the modules affected again. Errors
+ * are reported without stopping. Instruments observing the program are enabled by options naming
+ * the file to write their output to (`--profile <output>`, `--trace <output>` and
+ * `--coverage <data>`), and may be combined.
+ */
+func runRunCommand(arguments []string) {
+	isWatch := false
+	isFolded := false
+	profilePath := ""
+	tracePath := ""
+	coveragePath := ""
+	paths := []string{}
+
+	for i := 0; i < len(arguments); i++ {
+		switch arguments[i] {
+		case "--watch":
+			isWatch = true
+
+		case "--folded":
+			isFolded = true
+
+		case "--profile":
+			profilePath = optionValue(arguments, &i, entry_errors.ProfilePathsNotSpecified)
+
+		case "--trace":
+			tracePath = optionValue(arguments, &i, entry_errors.TracePathsNotSpecified)
+
+		case "--coverage":
+			coveragePath = optionValue(arguments, &i, entry_errors.CoveragePathsNotSpecified)
+
+		default:
+			paths = append(paths, arguments[i])
+		}
+	}
+
+	if len(paths) != 1 {
+		errors.RaiseError(entry_errors.FileNotSpecified)
+	}
+
+	if isFolded && profilePath == "" {
+		errors.RaiseError(entry_errors.ProfilePathsNotSpecified)
+	}
+
+	instruments := runtime.Instruments{
+		Profiler: nil,
+		Tracer:   nil,
+		Debugger: nil,
+		Coverage: nil,
+	}
+
+	outputs := []instrumentOutput{}
+
+	if profilePath != "" {
+		outputs = append(outputs, profileRun(&instruments, profilePath, isFolded))
+	}
+
+	if tracePath != "" {
+		outputs = append(outputs, traceRun(&instruments, tracePath))
+	}
+
+	if coveragePath != "" {
+		outputs = append(outputs, coverRun(&instruments, coveragePath))
+	}
+
+	if len(outputs) > 0 {
+		if isWatch {
+			errors.RaiseError(entry_errors.InstrumentedProgramWatched)
+		}
+
+		runInstrumentedFile(paths[0], instruments, outputs)
+
+		return
+	}
+
+	loader := module_loader.NewModuleLoader()
+
+	if !isWatch {
+		loader.LoadFile(paths[0])
+
+		return
+	}
+
+	for {
+		if err := errors.Catch(func() {
+			loader.LoadFile(paths[0])
+		}); err != nil {
+			fmt.Fprint(os.Stderr, err.String())
+		}
+
+		changedPaths := waitForChanges(loader.OpenedFiles())
+
+		// Plugins can't be unloaded, so the interpreter replaces itself with a new instance instead.
+		for _, path := range changedPaths {
+			if filepath.Ext(path) == ".so" {
+				restartInterpreter()
+			}
+		}
+
+		fmt.Fprintf(
+			os.Stderr,
+			"\nRunning again, since %s changed\n\n",
+			strings.Join(changedPaths, ", "),
+		)
+
+		loader.Invalidate(changedPaths)
+	}
+}
+
+/*
+ * The value of the option at `arguments[*i]`, which follows it, advancing `*i` past it. `err` is
+ * raised if there's none.
+ */
+func optionValue(arguments []string, i *int, err *errors.Error) string {
+	if *i+1 >= len(arguments) {
+		errors.RaiseError(err)
+	}
+
+	*i++
+
+	return arguments[*i]
+}
+
+/*
+ * Wait until any of the files in `modificationTimes` is modified, created or removed, returning
+ * those that were, in ascending order.
+ */
+func waitForChanges(modificationTimes map[string]time.Time) []string {
+	for {
+		time.Sleep(watchInterval)
+
+		result := []string{}
+
+		for path, modificationTime := range modificationTimes {
+			currentModificationTime := time.Time{}
+
+			if info, err := os.Stat(path); err == nil {
+				currentModificationTime = info.ModTime()
+			}
+
+			if !currentModificationTime.Equal(modificationTime) {
+				result = append(result, path)
+			}
+		}
+
+		if len(result) > 0 {
+			sort.Strings(result)
+
+			return result
+		}
+	}
+}
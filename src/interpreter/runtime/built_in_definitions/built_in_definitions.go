@@ -3,6 +3,7 @@ package built_in_definitions
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"project_umbrella/interpreter/bytecode_generator/built_in_declarations"
@@ -14,12 +15,43 @@ import (
 	"project_umbrella/interpreter/runtime/value"
 	"project_umbrella/interpreter/runtime/value_types"
 	"project_umbrella/interpreter/runtime/value_types/function"
+	"project_umbrella/interpreter/runtime/value_types/trait"
 	"project_umbrella/interpreter/runtime/value_util"
 )
 
 var BuiltInValues = map[built_in_declarations.BuiltInValueID]value.Value{
+	built_in_declarations.FailFunctionID: function.NewBuiltInFunction(
+		function.NewFixedFunctionArgumentValidator("fail", nil),
+		fail,
+		parser_types.NormalFunction,
+	),
+
 	built_in_declarations.FalseValueID: value_types.BooleanValue(false),
 	built_in_declarations.TrueValueID:  value_types.BooleanValue(true),
+	built_in_declarations.DestructureStructFunctionID: function.NewBuiltInFunction(
+		function.NewFixedFunctionArgumentValidator(
+			"__destructure_struct__",
+			nil,
+			reflect.TypeOf(*new(value_types.IntegerValue)),
+			reflect.TypeOf(&function.Function{}),
+			reflect.TypeOf(*new(value_types.StringValue)),
+		),
+
+		destructureStruct,
+		parser_types.NormalFunction,
+	),
+
+	built_in_declarations.DestructureTupleFunctionID: function.NewBuiltInFunction(
+		function.NewFixedFunctionArgumentValidator(
+			"__destructure_tuple__",
+			nil,
+			reflect.TypeOf(*new(value_types.IntegerValue)),
+		),
+
+		destructureTuple,
+		parser_types.NormalFunction,
+	),
+
 	built_in_declarations.IfElseFunctionID: function.NewBuiltInFunction(
 		function.NewFixedFunctionArgumentValidator(
 			"__if_else__",
@@ -32,6 +64,17 @@ var BuiltInValues = map[built_in_declarations.BuiltInValueID]value.Value{
 		parser_types.NormalFunction,
 	),
 
+	built_in_declarations.ImplementsFunctionID: function.NewBuiltInFunction(
+		function.NewFixedFunctionArgumentValidator(
+			"__implements__",
+			nil,
+			reflect.TypeOf(&trait.Trait{}),
+		),
+
+		implements,
+		parser_types.NormalFunction,
+	),
+
 	built_in_declarations.ImportFunctionID: function.NewBuiltInFunction(
 		function.NewFixedFunctionArgumentValidator(
 			"import",
@@ -69,15 +112,20 @@ var BuiltInValues = map[built_in_declarations.BuiltInValueID]value.Value{
 	),
 
 	built_in_declarations.StructFunctionID: function.NewBuiltInFunction(
+		newStructArgumentValidator(),
+		struct_,
+		parser_types.NormalFunction,
+	),
+
+	built_in_declarations.TraitFunctionID: function.NewBuiltInFunction(
 		function.NewFixedFunctionArgumentValidator(
-			"__struct__",
+			"__trait__",
 			reflect.TypeOf(*new(value_types.StringValue)),
-			reflect.TypeOf(&function.Function{}),
-			reflect.TypeOf(&function.Function{}),
 			reflect.TypeOf(&value_types.TupleValue{}),
+			reflect.TypeOf(&function.Function{}),
 		),
 
-		struct_,
+		trait_,
 		parser_types.NormalFunction,
 	),
 
@@ -87,26 +135,46 @@ var BuiltInValues = map[built_in_declarations.BuiltInValueID]value.Value{
 		parser_types.NormalFunction,
 	),
 
+	built_in_declarations.TryFunctionID: function.NewBuiltInFunction(
+		function.NewFixedFunctionArgumentValidator(
+			"try",
+			reflect.TypeOf(&function.Function{}),
+			reflect.TypeOf(&function.Function{}),
+		),
+
+		try,
+		parser_types.NormalFunction,
+	),
+
 	built_in_declarations.UnitValueID: value_types.UnitValue{},
 }
 
 func builtInStructFields(
 	structName string,
-	structConstructor *function.Function,
-	structArgumentNames []string,
-	structArgumentValues []value.Value,
+	instance *lookupFunctionEvaluator,
+	structTraits []value.Value,
 ) map[string]value.Value {
+	structConstructor := instance.structConstructor
+	structArgumentValues := instance.structArguments
 	equalsMethodEvaluator :=
 		func(runtime_ *runtime.Runtime, arguments ...value.Value) value_types.BooleanValue {
 			return structEquals(
 				runtime_,
 				structConstructor,
-				structArgumentNames,
 				structArgumentValues,
 				arguments...,
 			)
 		}
 
+	// Like other private fields, private arguments are hidden by `__arguments__` and `__to_str__`.
+	publicArgumentValues := make([]value.Value, 0, len(structArgumentValues))
+
+	for i, argumentValue := range structArgumentValues {
+		if !parser_types.IsPrivateName(instance.structArgumentNames[i]) {
+			publicArgumentValues = append(publicArgumentValues, argumentValue)
+		}
+	}
+
 	return map[string]value.Value{
 		built_in_declarations.UniversalEqualsMethod.Name: function.NewBuiltInFunction(
 			function.NewFixedFunctionArgumentValidator(
@@ -147,16 +215,37 @@ func builtInStructFields(
 			built_in_declarations.StructIsInstanceOfMethod.Type,
 		),
 
+		built_in_declarations.StructArgumentsField.Name: &value_types.TupleValue{
+			Elements: publicArgumentValues,
+		},
+
+		built_in_declarations.StructTraitsField.Name: &value_types.TupleValue{
+			Elements: structTraits,
+		},
+
 		built_in_declarations.StructConstructorMethod.Name: structConstructor,
+		built_in_declarations.StructCopyMethod.Name: function.NewBuiltInFunction(
+			function.NewVariadicFunctionArgumentValidator(
+				built_in_declarations.StructCopyMethod.Name,
+				nil,
+			),
+
+			func(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
+				return structCopy(runtime_, structName, instance, arguments...)
+			},
+
+			built_in_declarations.StructCopyMethod.Type,
+		),
+
 		built_in_declarations.UniversalToStringMethod.Name: function.NewBuiltInFunction(
 			function.NewFixedFunctionArgumentValidator(
 				built_in_declarations.UniversalToStringMethod.Name,
 			),
 
 			func(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
-				argumentsAsStrings := make([]string, 0, len(structArgumentValues))
+				argumentsAsStrings := make([]string, 0, len(publicArgumentValues))
 
-				for _, argument := range structArgumentValues {
+				for _, argument := range publicArgumentValues {
 					argumentsAsStrings = append(
 						argumentsAsStrings,
 						string(value_util.CallToStringMethod(runtime_, argument)),
@@ -177,6 +266,99 @@ func builtInStructFields(
 	}
 }
 
+func destructureStruct(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
+	patternLength := int(arguments[1].(value_types.IntegerValue))
+	constructor := arguments[2].(*function.Function)
+	constructorName := string(arguments[3].(value_types.StringValue))
+	instance, ok := arguments[0].(*function.Function)
+	evaluator := (*lookupFunctionEvaluator)(nil)
+
+	if ok {
+		evaluator, ok = instance.FunctionEvaluator.(*lookupFunctionEvaluator)
+	}
+
+	/*
+	 * The constructor is read from the evaluator's fields rather than by calling the instance, since
+	 * other lookup functions (e.g. modules) raise an error for fields they don't have.
+	 */
+	if !ok || evaluator.fields[value_types.StringValue(
+		built_in_declarations.StructConstructorMethod.Name,
+	)] != constructor {
+		valueDescription := "a module"
+
+		if !ok || evaluator.fields[value_types.StringValue(
+			built_in_declarations.UniversalToStringMethod.Name,
+		)] != nil {
+			valueDescription = string(value_util.CallToStringMethod(runtime_, arguments[0]))
+		}
+
+		errors.RaiseError(
+			runtime_errors.DestructuredValueNotStructInstance(valueDescription, constructorName),
+		)
+	}
+
+	structArguments, ok := instance.Evaluate(
+		runtime_,
+		value_types.StringValue(built_in_declarations.StructArgumentsField.Name),
+	).(*value_types.TupleValue)
+
+	if !ok {
+		errors.RaiseError(
+			runtime_errors.IncorrectBuiltInFunctionArgumentType("__destructure_struct__", 0),
+		)
+	}
+
+	if len(structArguments.Elements) != patternLength {
+		errors.RaiseError(
+			runtime_errors.DestructuringArityMismatch(
+				string(value_util.CallToStringMethod(runtime_, instance)),
+				"argument",
+				len(structArguments.Elements),
+				patternLength,
+			),
+		)
+	}
+
+	return structArguments
+}
+
+func destructureTuple(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
+	patternLength := int(arguments[1].(value_types.IntegerValue))
+	tuple, ok := arguments[0].(*value_types.TupleValue)
+
+	if !ok {
+		errors.RaiseError(
+			runtime_errors.DestructuredValueNotTuple(
+				string(value_util.CallToStringMethod(runtime_, arguments[0])),
+			),
+		)
+	}
+
+	if len(tuple.Elements) != patternLength {
+		errors.RaiseError(
+			runtime_errors.DestructuringArityMismatch(
+				string(value_util.CallToStringMethod(runtime_, tuple)),
+				"element",
+				len(tuple.Elements),
+				patternLength,
+			),
+		)
+	}
+
+	return tuple
+}
+
+func fail(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
+	errors.RaiseError(
+		runtime_errors.Failure(
+			string(value_util.CallToStringMethod(runtime_, arguments[0])),
+			arguments[0],
+		),
+	)
+
+	return nil
+}
+
 func ifElse(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 	var branchIndex int
 
@@ -189,13 +371,28 @@ func ifElse(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 	return arguments[branchIndex].(*function.Function).Evaluate(runtime_)
 }
 
-func import_(runtime_ *runtime.Runtime, type_ loader.LoaderRequestType, arguments ...value.Value) value.Value {
-	runtime_.LoaderChannel.LoadRequest <- &loader.LoaderRequest{
-		Type: type_,
-		Name: string(arguments[0].(value_types.StringValue)),
+func implements(_ *runtime.Runtime, arguments ...value.Value) value.Value {
+	instance, ok := arguments[0].(*function.Function)
+
+	if !ok {
+		return value_types.BooleanValue(false)
 	}
 
-	return <-runtime_.LoaderChannel.LoadResponse
+	evaluator, ok := instance.FunctionEvaluator.(*lookupFunctionEvaluator)
+
+	if !ok {
+		return value_types.BooleanValue(false)
+	}
+
+	traits, ok := evaluator.fields[value_types.StringValue(
+		built_in_declarations.StructTraitsField.Name,
+	)].(*value_types.TupleValue)
+
+	return value_types.BooleanValue(ok && slices.Contains(traits.Elements, arguments[1]))
+}
+
+func import_(runtime_ *runtime.Runtime, type_ loader.LoaderRequestType, arguments ...value.Value) value.Value {
+	return runtime_.LoaderChannel.Load(type_, string(arguments[0].(value_types.StringValue)))
 }
 
 func module(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
@@ -205,7 +402,13 @@ func module(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 		errors.RaiseError(runtime_errors.IncorrectBuiltInFunctionArgumentType("__module__", 0))
 	}
 
-	return newLookupFunction(fields)
+	for name := range fields {
+		if parser_types.IsPrivateName(string(name)) {
+			delete(fields, name)
+		}
+	}
+
+	return NewLookupFunction(fields)
 }
 
 func moduleOrStructFieldsToMap(
@@ -232,34 +435,108 @@ func moduleOrStructFieldsToMap(
 	return result, true
 }
 
-func newLookupFunction(fields map[value_types.StringValue]value.Value) *function.Function {
-	return function.NewBuiltInFunction(
-		function.NewFixedFunctionArgumentValidator(
-			function.BuiltInFunctionName,
-			reflect.TypeOf(*new(value_types.StringValue)),
-		),
+/*
+ * Evaluates the lookup functions that back modules and struct instances. Its fields are exposed so
+ * built-ins can inspect them without raising an error on unknown fields.
+ */
+type lookupFunctionEvaluator struct {
+	fields map[value_types.StringValue]value.Value
 
-		func(_ *runtime.Runtime, resultArguments ...value.Value) value.Value {
-			fieldName := resultArguments[0].(value_types.StringValue)
-			fieldValue, ok := fields[fieldName]
+	// The constructor of the struct instance it backs (nil for modules)
+	structConstructor *function.Function
 
-			if !ok {
-				errors.RaiseError(runtime_errors.UnknownField(string(fieldName)))
-			}
+	// The instance's private fields, which only its struct's own code can select (nil for modules)
+	privateFields map[value_types.StringValue]value.Value
+
+	// The names and values of the instance's arguments, including private ones (nil for modules)
+	structArgumentNames []string
+	structArguments     []value.Value
+}
+
+func (evaluator *lookupFunctionEvaluator) Evaluator(
+	runtime_ *runtime.Runtime,
+	arguments ...value.Value,
+) value.Value {
+	fieldName := arguments[0].(value_types.StringValue)
+	fieldValue, ok := evaluator.fields[fieldName]
+
+	if !ok && evaluator.structConstructor != nil &&
+		runtime_.Struct == value.Value(evaluator.structConstructor) {
+		fieldValue, ok = evaluator.privateFields[fieldName]
+	}
+
+	if !ok {
+		if parser_types.IsPrivateName(string(fieldName)) {
+			errors.RaiseError(runtime_errors.PrivateFieldSelected(string(fieldName)))
+		}
 
-			return fieldValue
+		errors.RaiseError(runtime_errors.UnknownField(string(fieldName)))
+	}
+
+	return fieldValue
+}
+
+/*
+ * Create a function returning the value of the field with the given name, as modules and struct
+ * instances do.
+ */
+func NewLookupFunction(fields map[value_types.StringValue]value.Value) *function.Function {
+	return &function.Function{
+		FunctionEvaluator: &lookupFunctionEvaluator{
+			fields:              fields,
+			structConstructor:   nil,
+			privateFields:       nil,
+			structArgumentNames: nil,
+			structArguments:     nil,
 		},
 
-		&parser_types.FunctionType{
+		ArgumentValidator: function.NewFixedFunctionArgumentValidator(
+			function.BuiltInFunctionName,
+			reflect.TypeOf(*new(value_types.StringValue)),
+		),
+
+		Name: function.BuiltInFunctionName,
+		Type_: &parser_types.FunctionType{
 			IsInfix:  false,
 			IsPrefix: false,
 			IsLookup: true,
 		},
+	}
+}
+
+/*
+ * `__struct__` accepts an optional tuple of the traits the struct implements after its argument
+ * fields.
+ */
+func newStructArgumentValidator() function.FunctionArgumentValidator {
+	parameterTypes := []reflect.Type{
+		reflect.TypeOf(*new(value_types.StringValue)),
+		reflect.TypeOf(&function.Function{}),
+		reflect.TypeOf(&function.Function{}),
+		reflect.TypeOf(&value_types.TupleValue{}),
+		reflect.TypeOf(&value_types.TupleValue{}),
+	}
+
+	withoutTraits := function.NewFixedFunctionArgumentValidator("__struct__", parameterTypes[:4]...)
+	withTraits := function.NewFixedFunctionArgumentValidator("__struct__", parameterTypes...)
+
+	return function.NewIntersectionFunctionArgumentValidator(
+		func(argumentTypes []reflect.Type) *errors.Error {
+			if len(argumentTypes) == 5 {
+				return withTraits(argumentTypes)
+			}
+
+			return withoutTraits(argumentTypes)
+		},
+
+		withoutTraits,
+		withTraits,
 	)
 }
 
 func struct_(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 	allFields := map[value_types.StringValue]value.Value{}
+	privateFields := map[value_types.StringValue]value.Value{}
 
 	raiseIncorrectArgumentTypeError := func(i int) {
 		errors.RaiseError(runtime_errors.IncorrectBuiltInFunctionArgumentType("__struct__", i))
@@ -270,15 +547,31 @@ func struct_(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 
 		if ok {
 			for name, value := range newFields {
-				allFields[name] = value
+				if parser_types.IsPrivateName(string(name)) {
+					privateFields[name] = value
+				} else {
+					allFields[name] = value
+				}
 			}
 		} else {
 			raiseIncorrectArgumentTypeError(i)
 		}
 	}
 
-	result := newLookupFunction(allFields)
+	result := NewLookupFunction(allFields)
+	structConstructor := arguments[1].(*function.Function)
 	fieldFactory := arguments[2].(*function.Function)
+
+	/*
+	 * The function creating the struct's fields is the struct's own code, as is any function it
+	 * defines.
+	 */
+	if evaluator, ok := fieldFactory.FunctionEvaluator.(function.StructCodeEvaluator); ok {
+		structFieldFactory := *fieldFactory
+		structFieldFactory.FunctionEvaluator = evaluator.InStruct(structConstructor)
+		fieldFactory = &structFieldFactory
+	}
+
 	fieldEntries, ok := fieldFactory.Evaluate(runtime_, result).(*value_types.TupleValue)
 
 	if !ok {
@@ -291,7 +584,22 @@ func struct_(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 	populateFields(argumentFieldEntries, 3)
 
 	structName := string(arguments[0].(value_types.StringValue))
-	structConstructor := arguments[1].(*function.Function)
+	structTraits := &value_types.TupleValue{Elements: []value.Value{}}
+
+	if len(arguments) == 5 {
+		structTraits = arguments[4].(*value_types.TupleValue)
+	}
+
+	for _, element := range structTraits.Elements {
+		trait_, ok := element.(*trait.Trait)
+
+		if !ok {
+			raiseIncorrectArgumentTypeError(4)
+		}
+
+		populateDefaultFields(runtime_, structName, result, allFields, trait_)
+	}
+
 	argumentFieldNames := make([]string, 0, len(argumentFieldEntries.Elements))
 	argumentFieldValues := make([]value.Value, 0, len(argumentFieldEntries.Elements))
 
@@ -304,11 +612,16 @@ func struct_(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 		argumentFieldValues = append(argumentFieldValues, entry.Elements[1])
 	}
 
+	evaluator := result.FunctionEvaluator.(*lookupFunctionEvaluator)
+	evaluator.structConstructor = structConstructor
+	evaluator.privateFields = privateFields
+	evaluator.structArgumentNames = argumentFieldNames
+	evaluator.structArguments = argumentFieldValues
+
 	for fieldName, fieldValue := range builtInStructFields(
 		structName,
-		structConstructor,
-		argumentFieldNames,
-		argumentFieldValues,
+		evaluator,
+		structTraits.Elements,
 	) {
 		allFields[value_types.StringValue(fieldName)] = fieldValue
 	}
@@ -316,10 +629,111 @@ func struct_(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
 	return result
 }
 
+/*
+ * Check that `fields` has each field required by `trait_`, then add the trait's default fields that
+ * the struct doesn't define itself.
+ */
+func populateDefaultFields(
+	runtime_ *runtime.Runtime,
+	structName string,
+	instance *function.Function,
+	fields map[value_types.StringValue]value.Value,
+	trait_ *trait.Trait,
+) {
+	defaultFieldFactoryArguments := []value.Value{instance}
+
+	for _, fieldName := range trait_.RequiredFieldNames {
+		fieldValue, ok := fields[value_types.StringValue(fieldName)]
+
+		if !ok {
+			errors.RaiseError(runtime_errors.TraitFieldMissing(structName, trait_.Name, fieldName))
+		}
+
+		defaultFieldFactoryArguments = append(defaultFieldFactoryArguments, fieldValue)
+	}
+
+	defaultFieldEntries, ok := trait_.DefaultFieldFactory.Evaluate(
+		runtime_,
+		defaultFieldFactoryArguments...,
+	).(*value_types.TupleValue)
+
+	if !ok {
+		errors.RaiseError(runtime_errors.IncorrectBuiltInFunctionArgumentType("__trait__", 2))
+	}
+
+	defaultFields, ok := moduleOrStructFieldsToMap(defaultFieldEntries)
+
+	if !ok {
+		errors.RaiseError(runtime_errors.IncorrectBuiltInFunctionArgumentType("__trait__", 2))
+	}
+
+	for name, value := range defaultFields {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+}
+
+/*
+ * Re-run the constructor of `instance` with its arguments, replacing those named by the (name,
+ * value) pairs in `arguments`, so that every other field is derived from the new arguments. Private
+ * arguments can only be replaced by copies made within the struct's own code.
+ */
+func structCopy(
+	runtime_ *runtime.Runtime,
+	structName string,
+	instance *lookupFunctionEvaluator,
+	arguments ...value.Value,
+) value.Value {
+	structConstructor := instance.structConstructor
+	structArgumentNames := instance.structArgumentNames
+	isPermitted := runtime_.Struct == value.Value(structConstructor)
+	replacements, ok := moduleOrStructFieldsToMap(&value_types.TupleValue{Elements: arguments})
+
+	if !ok {
+		errors.RaiseError(
+			runtime_errors.IncorrectBuiltInFunctionArgumentType(
+				built_in_declarations.StructCopyMethod.Name,
+				0,
+			),
+		)
+	}
+
+	newArgumentValues := slices.Clone(instance.structArguments)
+
+	for name, value := range replacements {
+		if parser_types.IsPrivateName(string(name)) && !isPermitted {
+			errors.RaiseError(runtime_errors.PrivateFieldSelected(string(name)))
+		}
+
+		i := slices.Index(structArgumentNames, string(name))
+
+		if i == -1 {
+			errors.RaiseError(runtime_errors.CopiedFieldNotArgument(structName, string(name)))
+		}
+
+		newArgumentValues[i] = value
+	}
+
+	if structConstructor.HasRestParameter && len(newArgumentValues) > 0 {
+		restArgument, ok := newArgumentValues[len(newArgumentValues)-1].(*value_types.TupleValue)
+
+		if !ok {
+			errors.RaiseError(runtime_errors.NonTupleSpread)
+		}
+
+		newArgumentValues = append(
+			newArgumentValues[:len(newArgumentValues)-1],
+			restArgument.Elements...,
+		)
+	}
+
+	return structConstructor.Evaluate(runtime_, newArgumentValues...)
+}
+
 func structEquals(
 	runtime_ *runtime.Runtime,
 	leftConstructor *function.Function,
-	leftArgumentNames []string,
 	leftArgumentValues []value.Value,
 	arguments ...value.Value,
 ) value_types.BooleanValue {
@@ -338,17 +752,97 @@ func structEquals(
 		return false
 	}
 
-	for i := 0; i < len(leftArgumentNames); i++ {
-		leftArgument := leftArgumentValues[i]
-		rightArgument :=
-			rightHandSide.Evaluate(runtime_, value_types.StringValue(leftArgumentNames[i]))
+	/*
+	 * The arguments are compared through the instance's evaluator rather than `__arguments__` or
+	 * by name, since private arguments can't be looked up.
+	 */
+	rightEvaluator, ok := rightHandSide.FunctionEvaluator.(*lookupFunctionEvaluator)
+
+	if !ok {
+		return false
+	}
+
+	return value_util.CallEqualsMethod(
+		runtime_,
+		&value_types.TupleValue{Elements: leftArgumentValues},
+		&value_types.TupleValue{Elements: rightEvaluator.structArguments},
+	)
+}
 
-		if !value_util.CallEqualsMethod(runtime_, leftArgument, rightArgument) {
-			return false
+func trait_(_ *runtime.Runtime, arguments ...value.Value) value.Value {
+	requiredFieldEntries := arguments[1].(*value_types.TupleValue)
+	requiredFieldNames := make([]string, 0, len(requiredFieldEntries.Elements))
+
+	for _, element := range requiredFieldEntries.Elements {
+		fieldName, ok := element.(value_types.StringValue)
+
+		if !ok {
+			errors.RaiseError(runtime_errors.IncorrectBuiltInFunctionArgumentType("__trait__", 1))
 		}
+
+		requiredFieldNames = append(requiredFieldNames, string(fieldName))
+	}
+
+	return &trait.Trait{
+		Name:                string(arguments[0].(value_types.StringValue)),
+		RequiredFieldNames:  requiredFieldNames,
+		DefaultFieldFactory: arguments[2].(*function.Function),
+	}
+}
+
+/*
+ * Call the first argument, and should it raise an error (whether through `fail` or a built-in), call
+ * the second argument with a value describing the error instead.
+ */
+func try(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
+	var result value.Value
+
+	caught := errors.Catch(func() {
+		result = arguments[0].(*function.Function).Evaluate(runtime_)
+	})
+
+	if caught == nil {
+		return result
+	}
+
+	return arguments[1].(*function.Function).Evaluate(runtime_, newErrorValue(caught))
+}
+
+func newErrorValue(error_ *errors.Error) *function.Function {
+	failedValue, ok := error_.Payload.(value.Value)
+
+	if !ok {
+		failedValue = value_types.UnitValue{}
+	}
+
+	fields := map[*built_in_declarations.BuiltInField]value.Value{
+		built_in_declarations.ErrorSectionField:     value_types.StringValue(error_.Section),
+		built_in_declarations.ErrorCodeField:        value_types.IntegerValue(error_.Code),
+		built_in_declarations.ErrorNameField:        value_types.StringValue(error_.Name),
+		built_in_declarations.ErrorDescriptionField: value_types.StringValue(error_.Description),
+		built_in_declarations.ErrorValueField:       failedValue,
+		built_in_declarations.UniversalToStringMethod: function.NewBuiltInFunction(
+			function.NewFixedFunctionArgumentValidator(
+				built_in_declarations.UniversalToStringMethod.Name,
+			),
+
+			func(_ *runtime.Runtime, _ ...value.Value) value.Value {
+				return value_types.StringValue(
+					fmt.Sprintf("Error (%s-%d): %s", error_.Section, error_.Code, error_.Name),
+				)
+			},
+
+			built_in_declarations.UniversalToStringMethod.Type,
+		),
+	}
+
+	namedFields := make(map[value_types.StringValue]value.Value, len(fields))
+
+	for field, fieldValue := range fields {
+		namedFields[value_types.StringValue(field.Name)] = fieldValue
 	}
 
-	return true
+	return NewLookupFunction(namedFields)
 }
 
 func tuple(_ *runtime.Runtime, arguments ...value.Value) value.Value {
@@ -24,7 +24,12 @@ package runtime
 import (
 	"project_umbrella/interpreter/bytecode_generator"
 	"project_umbrella/interpreter/common"
+	"project_umbrella/interpreter/coverage"
+	"project_umbrella/interpreter/debugger"
 	"project_umbrella/interpreter/loader"
+	"project_umbrella/interpreter/profiler"
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/tracer"
 )
 
 type BytecodeFunctionBlock interface {
@@ -37,6 +42,16 @@ type BytecodeFunctionBlockGraph struct {
 	ValueID        int // Should be -1 if this is the root block graph
 	FirstValueID   int
 	ParameterCount int
+	IsVariadic     bool
+
+	// Where the function is declared, or nil if it was generated while desugaring
+	Source *profiler.Function
+
+	// The value IDs of the values named within the function, keyed by their names
+	ValueNames map[string]int
+
+	// The branch of an `if` expression the function is, if it is one and coverage is being measured
+	Branch *coverage.Branch
 }
 
 func (*BytecodeFunctionBlockGraph) BytecodeFunctionBlock() {}
@@ -87,4 +102,39 @@ func (InstructionList) BytecodeFunctionBlock() {}
 
 type Runtime struct {
 	LoaderChannel *loader.LoaderChannel
+
+	// The calls being made, or nil if the program isn't being profiled
+	CallStack *profiler.CallStack
+
+	// Records the blocks evaluated, unless it's nil
+	Tracer *tracer.Tracer
+
+	// The runtime as observed by the debugger, or nil if the program isn't being debugged
+	DebuggerThread *debugger.Thread
+
+	// Counts the statements executed and branches taken, unless it's nil
+	Coverage *coverage.Coverage
+
+	/*
+	 * The constructor of the struct whose code is being evaluated, or nil outside of structs. Only
+	 * code within a struct can select the private fields of its instances and replace their private
+	 * arguments by copies.
+	 */
+	Struct value.Value
+}
+
+// A copy of the runtime evaluating the code of the struct constructed by `structConstructor`
+func (runtime_ *Runtime) InStruct(structConstructor value.Value) *Runtime {
+	result := *runtime_
+	result.Struct = structConstructor
+
+	return &result
+}
+
+// Tools observing programs as they're evaluated, each nil unless it's enabled
+type Instruments struct {
+	Profiler *profiler.Profiler
+	Tracer   *tracer.Tracer
+	Debugger *debugger.Debugger
+	Coverage *coverage.Coverage
 }
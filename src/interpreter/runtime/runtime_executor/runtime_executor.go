@@ -3,11 +3,15 @@ package runtime_executor
 import (
 	"bytes"
 	"encoding/binary"
+	"path/filepath"
+	"strings"
 
 	"project_umbrella/interpreter/bytecode_generator"
 	"project_umbrella/interpreter/bytecode_generator/built_in_declarations"
 	"project_umbrella/interpreter/common"
+	"project_umbrella/interpreter/coverage"
 	"project_umbrella/interpreter/loader"
+	"project_umbrella/interpreter/profiler"
 	"project_umbrella/interpreter/runtime"
 	"project_umbrella/interpreter/runtime/built_in_definitions"
 	"project_umbrella/interpreter/runtime/value"
@@ -15,9 +19,12 @@ import (
 	"project_umbrella/interpreter/runtime/value_types/bytecode_function"
 )
 
+// Execute the bytecode translated from the file at `path`, observed by `instruments`.
 func ExecuteBytecode(
+	path string,
 	bytecode *bytecode_generator.Bytecode,
 	loaderChannel *loader.LoaderChannel,
+	instruments runtime.Instruments,
 ) value.Value {
 	constants := make([]value.Value, 0, len(bytecode.Constants))
 
@@ -25,20 +32,43 @@ func ExecuteBytecode(
 		constants = append(constants, newValueFromConstant(constant))
 	}
 
+	runtime_ := &runtime.Runtime{
+		LoaderChannel:  loaderChannel,
+		CallStack:      nil,
+		Tracer:         instruments.Tracer,
+		DebuggerThread: nil,
+		Coverage:       instruments.Coverage,
+		Struct:         nil,
+	}
+
+	if instruments.Profiler != nil {
+		runtime_.CallStack = instruments.Profiler.NewCallStack()
+	}
+
+	if instruments.Debugger != nil {
+		runtime_.DebuggerThread = instruments.Debugger.NewThread(nameOfModule(path))
+		defer runtime_.DebuggerThread.Exited()
+	}
+
 	return bytecode_function.
-		NewBytecodeFunction(0, &bytecode_function.BytecodeFunctionEvaluator{
+		NewBytecodeFunction(0, false, &bytecode_function.BytecodeFunctionEvaluator{
 			Constants:       constants,
 			ContainingScope: nil,
-			BlockGraph:      newBlockGraphFromBytecode(bytecode),
+			BlockGraph:      newBlockGraphFromBytecode(path, bytecode, instruments.Coverage),
+			Struct:          nil,
 		}).
-		Evaluate(&runtime.Runtime{
-			LoaderChannel: loaderChannel,
-		})
+		Evaluate(runtime_)
 }
 
+// Instructions and branches are added to `coverage_` to be counted, unless it's nil.
 func newBlockGraphFromBytecode(
+	path string,
 	bytecode *bytecode_generator.Bytecode,
+	coverage_ *coverage.Coverage,
 ) *runtime.BytecodeFunctionBlockGraph {
+	// Functions' names are qualified by that of their module.
+	moduleName := nameOfModule(path)
+
 	type runtimeConstructorScope struct {
 		nextValueID              int
 		valueIDBlockMap          map[int]int
@@ -59,6 +89,15 @@ func newBlockGraphFromBytecode(
 				ValueID:           -1,
 				FirstValueID:      0,
 				ParameterCount:    0,
+				IsVariadic:        false,
+				Source: &profiler.Function{
+					Name:     moduleName,
+					Filename: path,
+					Offset:   0,
+				},
+
+				ValueNames: map[string]int{},
+				Branch:     nil,
 			},
 		},
 	}
@@ -108,6 +147,10 @@ func newBlockGraphFromBytecode(
 	}
 
 	addValuedInstruction := func(instruction *bytecode_generator.Instruction) {
+		if coverage_ != nil {
+			coverage_.AddInstruction(instruction)
+		}
+
 		addSingleValuedBlock(
 			func(valueID int) runtime.BytecodeFunctionBlock {
 				return runtime.InstructionList{
@@ -128,6 +171,22 @@ func newBlockGraphFromBytecode(
 				ValueID:           0,
 				FirstValueID:      0,
 				ParameterCount:    instruction.Arguments[0],
+				IsVariadic:        instruction.Arguments[1] == 1,
+				Source:            nil,
+				ValueNames:        map[string]int{},
+				Branch:            nil,
+			}
+
+			if nameConstantID := instruction.Arguments[2]; nameConstantID != -1 {
+				newBlockGraph.Source = &profiler.Function{
+					Name:     moduleName + "." + bytecode.Constants[nameConstantID].Encoded,
+					Filename: "",
+					Offset:   instruction.Arguments[4],
+				}
+
+				if filenameConstantID := instruction.Arguments[3]; filenameConstantID != -1 {
+					newBlockGraph.Source.Filename = bytecode.Constants[filenameConstantID].Encoded
+				}
 			}
 
 			addSingleValuedBlock(
@@ -153,7 +212,8 @@ func newBlockGraphFromBytecode(
 
 	for _, instruction := range bytecode.Instructions {
 		switch instruction.Type {
-		case bytecode_generator.PushArgumentInstruction:
+		case bytecode_generator.PushArgumentInstruction,
+			bytecode_generator.PushArgumentsInstruction:
 			currentScope().pushArgumentInstructions =
 				append(currentScope().pushArgumentInstructions, instruction)
 
@@ -199,6 +259,10 @@ func newBlockGraphFromBytecode(
 			addDependencyForLatestBlock(instruction.Arguments[0])
 
 		case bytecode_generator.ValueFromCallInstruction:
+			if coverage_ != nil {
+				coverage_.AddInstruction(instruction)
+			}
+
 			instructionList :=
 				make(runtime.InstructionList, 0, len(currentScope().pushArgumentInstructions)+1)
 
@@ -234,6 +298,22 @@ func newBlockGraphFromBytecode(
 		case bytecode_generator.ValueFromStructValueInstruction:
 			addValuedInstruction(instruction)
 			addDependencyForLatestBlock(instruction.Arguments[0])
+
+		case bytecode_generator.NameValueInstruction:
+			name := bytecode.Constants[instruction.Arguments[1]].Encoded
+			currentScope().blockGraph.ValueNames[name] = instruction.Arguments[0]
+
+		case bytecode_generator.BranchInstruction:
+			if coverage_ != nil {
+				branch := coverage.Branch{
+					Filename: bytecode.Constants[instruction.Arguments[0]].Encoded,
+					Offset:   instruction.Arguments[1],
+					Index:    instruction.Arguments[2],
+				}
+
+				coverage_.AddBranch(branch)
+				currentScope().blockGraph.Branch = &branch
+			}
 		}
 	}
 
@@ -243,6 +323,11 @@ func newBlockGraphFromBytecode(
 	return result
 }
 
+// Modules are named after their files.
+func nameOfModule(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
 func newValueFromConstant(constant bytecode_generator.Constant) value.Value {
 	switch constant.Type {
 	case bytecode_generator.FloatConstant:
@@ -2,6 +2,8 @@ package bytecode_function
 
 import (
 	"reflect"
+	"slices"
+	"time"
 
 	"project_umbrella/interpreter/bytecode_generator"
 	"project_umbrella/interpreter/bytecode_generator/built_in_declarations"
@@ -15,12 +17,30 @@ import (
 	"project_umbrella/interpreter/runtime/value_types"
 	"project_umbrella/interpreter/runtime/value_types/function"
 	"project_umbrella/interpreter/runtime/value_util"
+	"project_umbrella/interpreter/tracer"
 )
 
 type BytecodeFunctionEvaluator struct {
 	Constants       []value.Value
 	ContainingScope *scope
 	BlockGraph      *runtime.BytecodeFunctionBlockGraph
+
+	/*
+	 * The constructor of the struct the function's code belongs to, or nil if it doesn't belong to
+	 * one. Functions defined in a struct's code belong to it too.
+	 */
+	Struct value.Value
+}
+
+func (evaluator *BytecodeFunctionEvaluator) InStruct(
+	structConstructor value.Value,
+) function.FunctionEvaluator {
+	return &BytecodeFunctionEvaluator{
+		Constants:       evaluator.Constants,
+		ContainingScope: evaluator.ContainingScope,
+		BlockGraph:      evaluator.BlockGraph,
+		Struct:          structConstructor,
+	}
 }
 
 // TODO: Make this concurrent
@@ -28,6 +48,15 @@ func (evaluator *BytecodeFunctionEvaluator) Evaluator(
 	runtime_ *runtime.Runtime,
 	arguments ...value.Value,
 ) value.Value {
+	if runtime_.Struct != evaluator.Struct {
+		runtime_ = runtime_.InStruct(evaluator.Struct)
+	}
+
+	if runtime_.CallStack != nil && evaluator.BlockGraph.Source != nil {
+		runtime_.CallStack.Push(evaluator.BlockGraph.Source)
+		defer runtime_.CallStack.Pop()
+	}
+
 	firstValueID := 0
 
 	if evaluator.ContainingScope != nil {
@@ -36,16 +65,64 @@ func (evaluator *BytecodeFunctionEvaluator) Evaluator(
 
 	scope_ := &scope{
 		parent:       evaluator.ContainingScope,
+		blockGraph:   evaluator.BlockGraph,
 		firstValueID: firstValueID,
 		values:       map[int]value.Value{},
 	}
 
+	if evaluator.BlockGraph.IsVariadic {
+		fixedParameterCount := evaluator.BlockGraph.ParameterCount - 1
+		restArguments := &value_types.TupleValue{
+			Elements: slices.Clone(arguments[fixedParameterCount:]),
+		}
+
+		arguments = append(slices.Clone(arguments[:fixedParameterCount]), restArguments)
+	}
+
 	for i, argument := range arguments {
 		scope_.values[scope_.firstValueID+i] = argument
 	}
 
-	isAcyclic := evaluator.BlockGraph.Evaluate(
+	if runtime_.Coverage != nil && evaluator.BlockGraph.Branch != nil {
+		runtime_.Coverage.BranchTaken(*evaluator.BlockGraph.Branch)
+	}
+
+	if runtime_.DebuggerThread != nil {
+		runtime_.DebuggerThread.Enter(
+			scope_.functionName(),
+			evaluator.BlockGraph.Source == nil,
+			&debuggerScope{
+				scope:    scope_,
+				runtime_: runtime_,
+			},
+		)
+
+		defer runtime_.DebuggerThread.Exit()
+	}
+
+	// Errors unwinding through the function are traced to the statement of the block raising them.
+	var currentNode *common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock]
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if error_, ok := recovered.(*errors.Error); ok && currentNode != nil {
+				if position := evaluator.blockPosition(currentNode); position != nil {
+					recovered = error_.Traced(position)
+				}
+			}
+
+			panic(recovered)
+		}
+	}()
+
+	evaluateBlock :=
 		func(consolidatedNode *common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock]) {
+			currentNode = consolidatedNode
+
+			if runtime_.DebuggerThread != nil {
+				runtime_.DebuggerThread.BeforeBlock(evaluator.blockPosition(consolidatedNode))
+			}
+
 			functions := []*runtime.BytecodeFunctionBlockGraph{}
 			instructionList := runtime.InstructionList(nil)
 
@@ -76,8 +153,25 @@ func (evaluator *BytecodeFunctionEvaluator) Evaluator(
 			} else {
 				scope_.addInstructionList(runtime_, evaluator, instructionList)
 			}
-		},
-	)
+		}
+
+	var isAcyclic bool
+
+	switch {
+	// Blocks are evaluated in the order of their statements, so that stepping follows the source.
+	case runtime_.DebuggerThread != nil:
+		isAcyclic = evaluator.BlockGraph.EvaluateInOrder(evaluateBlock)
+
+	case runtime_.Tracer != nil:
+		isAcyclic = evaluator.evaluateTracedBlocks(
+			runtime_.Tracer,
+			scope_.functionName(),
+			evaluateBlock,
+		)
+
+	default:
+		isAcyclic = evaluator.BlockGraph.Evaluate(evaluateBlock)
+	}
 
 	if !isAcyclic {
 		errors.RaiseError(runtime_errors.ValueCycle)
@@ -98,12 +192,108 @@ func (evaluator *BytecodeFunctionEvaluator) Evaluator(
 	return scope_.values[lastValueID]
 }
 
+/*
+ * Like `BlockGraph.Evaluate`, but record each block evaluated with `tracer_`. Every block of a
+ * function is evaluated on the goroutine calling it.
+ */
+func (evaluator *BytecodeFunctionEvaluator) evaluateTracedBlocks(
+	tracer_ *tracer.Tracer,
+	functionName string,
+	evaluateBlock func(*common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock]),
+) bool {
+	goroutine := tracer.CurrentGoroutine()
+	readyTimes := map[*common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock]]time.Time{}
+
+	return evaluator.BlockGraph.EvaluateWithReadiness(
+		func(consolidatedNode *common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock]) {
+			start := tracer_.BlockStarted()
+
+			evaluateBlock(consolidatedNode)
+			tracer_.BlockEvaluated(&tracer.Block{
+				Function:     functionName,
+				Instructions: evaluator.blockInstructions(consolidatedNode),
+				Goroutine:    goroutine,
+				Ready:        readyTimes[consolidatedNode],
+				Start:        start,
+				End:          time.Now(),
+			})
+		},
+
+		func(consolidatedNode *common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock]) {
+			readyTimes[consolidatedNode] = tracer_.BlockReady()
+		},
+	)
+}
+
+/*
+ * Where the statement a block was translated from starts, or nil if none of its instructions were
+ * translated from one (e.g. if it defines functions).
+ */
+func (evaluator *BytecodeFunctionEvaluator) blockPosition(
+	consolidatedNode *common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock],
+) *errors.Position {
+	for _, i := range consolidatedNode.Nodes() {
+		if instructionList, ok :=
+			evaluator.BlockGraph.ConsolidatedGraph.GetNode(i).(runtime.InstructionList); ok {
+			for _, element := range instructionList {
+				if element.Instruction.Position != nil {
+					return element.Instruction.Position
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// The instructions of a block, with functions defined by it represented by their `PUSH_FN`s
+func (evaluator *BytecodeFunctionEvaluator) blockInstructions(
+	consolidatedNode *common.ConsolidatedGraphNode[runtime.BytecodeFunctionBlock],
+) []string {
+	result := []string{}
+
+	for _, i := range consolidatedNode.Nodes() {
+		switch node := evaluator.BlockGraph.ConsolidatedGraph.GetNode(i).(type) {
+		case *runtime.BytecodeFunctionBlockGraph:
+			instruction := "PUSH_FN"
+
+			if node.Source != nil {
+				instruction += " " + node.Source.Name
+			}
+
+			result = append(result, instruction)
+
+		case runtime.InstructionList:
+			for _, element := range node {
+				result = append(result, element.Instruction.String())
+			}
+		}
+	}
+
+	return result
+}
+
 type scope struct {
 	parent       *scope
+	blockGraph   *runtime.BytecodeFunctionBlockGraph
 	firstValueID int
 	values       map[int]value.Value
 }
 
+/*
+ * The qualified name of the scope's function, or of the function containing it if it was generated
+ * while desugaring (e.g. as the branch of an `if` expression).
+ */
+func (scope_ *scope) functionName() string {
+	for ; scope_ != nil; scope_ = scope_.parent {
+		if scope_.blockGraph.Source != nil {
+			return scope_.blockGraph.Source.Name
+		}
+	}
+
+	return "(function)"
+}
+
 func (scope_ *scope) addFunctions(
 	evaluator *BytecodeFunctionEvaluator,
 	functions []*runtime.BytecodeFunctionBlockGraph,
@@ -111,10 +301,12 @@ func (scope_ *scope) addFunctions(
 	for _, blockGraph := range functions {
 		scope_.values[blockGraph.ValueID] = NewBytecodeFunction(
 			blockGraph.ParameterCount,
+			blockGraph.IsVariadic,
 			&BytecodeFunctionEvaluator{
 				Constants:       evaluator.Constants,
 				ContainingScope: scope_,
 				BlockGraph:      blockGraph,
+				Struct:          evaluator.Struct,
 			},
 		)
 	}
@@ -128,11 +320,24 @@ func (scope_ *scope) addInstructionList(
 	callArguments := []value.Value{}
 
 	for _, element := range instructionList {
+		if runtime_.Coverage != nil {
+			runtime_.Coverage.InstructionEvaluated(element.Instruction)
+		}
+
 		switch element.Instruction.Type {
 		case bytecode_generator.PushArgumentInstruction:
 			callArguments =
 				append(callArguments, scope_.getValue(element.Instruction.Arguments[0]))
 
+		case bytecode_generator.PushArgumentsInstruction:
+			tuple, ok := scope_.getValue(element.Instruction.Arguments[0]).(*value_types.TupleValue)
+
+			if !ok {
+				errors.RaiseError(runtime_errors.NonTupleSpread)
+			}
+
+			callArguments = append(callArguments, tuple.Elements...)
+
 		case bytecode_generator.ValueCopyInstruction:
 			scope_.values[element.InstructionValueID] =
 				scope_.getValue(element.Instruction.Arguments[0])
@@ -190,18 +395,32 @@ func (scope_ *scope) getValue(valueID int) value.Value {
 
 func NewBytecodeFunction(
 	parameterCount int,
+	isVariadic bool,
 	evaluator *BytecodeFunctionEvaluator,
 ) *function.Function {
 	name := "(function)"
 
-	return &function.Function{
-		FunctionEvaluator: evaluator,
-		ArgumentValidator: function.NewFixedFunctionArgumentValidator(
+	var argumentValidator function.FunctionArgumentValidator
+
+	if isVariadic {
+		argumentValidator = function.NewRestFunctionArgumentValidator(
+			name,
+			nil,
+			common.Repeat[reflect.Type](nil, parameterCount-1)...,
+		)
+	} else {
+		argumentValidator = function.NewFixedFunctionArgumentValidator(
 			name,
 			common.Repeat[reflect.Type](nil, parameterCount)...,
-		),
+		)
+	}
+
+	return &function.Function{
+		FunctionEvaluator: evaluator,
+		ArgumentValidator: argumentValidator,
 
-		Name:  name,
-		Type_: parser_types.NormalFunction,
+		Name:             name,
+		Type_:            parser_types.NormalFunction,
+		HasRestParameter: isVariadic,
 	}
 }
@@ -0,0 +1,87 @@
+package bytecode_function
+
+import (
+	"sort"
+	"strconv"
+
+	"project_umbrella/interpreter/debugger"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/runtime"
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types"
+	"project_umbrella/interpreter/runtime/value_util"
+)
+
+// A scope as inspected by the debugger, whose values are displayed with `runtime_`
+type debuggerScope struct {
+	scope    *scope
+	runtime_ *runtime.Runtime
+}
+
+func (debuggerScope_ *debuggerScope) Function() string {
+	return debuggerScope_.scope.functionName()
+}
+
+func (debuggerScope_ *debuggerScope) Variables() []*debugger.Value {
+	result := []*debugger.Value{}
+
+	for name, valueID := range debuggerScope_.scope.blockGraph.ValueNames {
+		if value_ := debuggerScope_.scope.getValue(valueID); value_ != nil {
+			result = append(result, &debugger.Value{
+				Name:  name,
+				Value: debuggerScope_.display(value_),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func (debuggerScope_ *debuggerScope) Parent() debugger.Scope {
+	// A nil `*debuggerScope` wouldn't be a nil `debugger.Scope`.
+	if debuggerScope_.scope.parent == nil {
+		return nil
+	}
+
+	return &debuggerScope{
+		scope:    debuggerScope_.scope.parent,
+		runtime_: debuggerScope_.runtime_,
+	}
+}
+
+/*
+ * Display a value as `__to_str__` does, quoting strings. `__to_str__` is called by a runtime that
+ * isn't being debugged, since the thread being inspected is stopped.
+ */
+func (debuggerScope_ *debuggerScope) display(value_ value.Value) string {
+	if string_, ok := value_.(value_types.StringValue); ok {
+		return strconv.Quote(string(string_))
+	}
+
+	var result string
+
+	err := errors.Catch(func() {
+		result = string(value_util.CallToStringMethod(
+			&runtime.Runtime{
+				LoaderChannel:  debuggerScope_.runtime_.LoaderChannel,
+				CallStack:      nil,
+				Tracer:         nil,
+				DebuggerThread: nil,
+				Coverage:       nil,
+				Struct:         nil,
+			},
+
+			value_,
+		))
+	})
+
+	if err != nil {
+		return "(" + err.Name + ")"
+	}
+
+	return result
+}
@@ -1,6 +1,7 @@
 package function
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 
@@ -17,6 +18,12 @@ type Function struct {
 	ArgumentValidator FunctionArgumentValidator
 	Name              string
 	Type_             *parser_types.FunctionType
+
+	/*
+	 * Whether the function's last parameter collects any remaining arguments into a tuple. This is
+	 * only ever set for functions defined in Krait.
+	 */
+	HasRestParameter bool
 }
 
 func (function *Function) Definition() *value.ValueDefinition {
@@ -79,6 +86,46 @@ func NewIntersectionFunctionArgumentValidator(
 	}
 }
 
+/*
+ * Like `NewFixedFunctionArgumentValidator`, but accept any number of additional arguments after
+ * those corresponding to `parameterTypes`, each of which must be assignable to `restParameterType`
+ * (unless it's `nil`).
+ */
+func NewRestFunctionArgumentValidator(
+	name string,
+	restParameterType reflect.Type,
+	parameterTypes ...reflect.Type,
+) FunctionArgumentValidator {
+	return func(argumentTypes []reflect.Type) *errors.Error {
+		if len(argumentTypes) < len(parameterTypes) {
+			return runtime_errors.IncorrectCallArgumentCount(
+				fmt.Sprintf("at least %d", len(parameterTypes)),
+				len(parameterTypes) != 1,
+				len(argumentTypes),
+			)
+		}
+
+		err := NewFixedFunctionArgumentValidator(name, parameterTypes...)(
+			argumentTypes[:len(parameterTypes)],
+		)
+
+		if err != nil {
+			return err
+		}
+
+		for i, argumentType := range argumentTypes[len(parameterTypes):] {
+			if restParameterType != nil && !argumentType.AssignableTo(restParameterType) {
+				return runtime_errors.IncorrectBuiltInFunctionArgumentType(
+					name,
+					len(parameterTypes)+i,
+				)
+			}
+		}
+
+		return nil
+	}
+}
+
 func NewVariadicFunctionArgumentValidator(
 	name string,
 	parameterType reflect.Type,
@@ -101,3 +148,12 @@ func NewVariadicFunctionArgumentValidator(
 type FunctionEvaluator interface {
 	Evaluator(*runtime.Runtime, ...value.Value) value.Value
 }
+
+/*
+ * Implemented by the evaluators of functions defined in Krait, whose code belongs to the struct
+ * (if any) in whose code they're defined.
+ */
+type StructCodeEvaluator interface {
+	// A copy of the evaluator whose code belongs to the struct constructed by `structConstructor`
+	InStruct(structConstructor value.Value) FunctionEvaluator
+}
@@ -2,6 +2,7 @@ package library
 
 import (
 	"reflect"
+	"slices"
 
 	"project_umbrella/interpreter/bytecode_generator/built_in_declarations"
 	"project_umbrella/interpreter/errors"
@@ -15,9 +16,25 @@ import (
 type Library struct {
 	Path     string
 	GetField func(string) (value.Value, bool)
+
+	/*
+	 * The names of the symbols the library exports, in ascending order. Unlike `GetField`, this
+	 * includes symbols that aren't values. It may omit symbols if the library can't list them
+	 * reliably, in which case `HasSymbol` is set.
+	 */
+	SymbolNames []string
+
+	// Whether the library exports a symbol missing from `SymbolNames` (nil if it lists every one)
+	HasSymbol func(string) bool
 }
 
 func (library *Library) Definition() *value.ValueDefinition {
+	symbolNames := make([]value.Value, 0, len(library.SymbolNames))
+
+	for _, name := range library.SymbolNames {
+		symbolNames = append(symbolNames, value_types.StringValue(name))
+	}
+
 	return &value.ValueDefinition{
 		Fields: map[string]value.Value{
 			built_in_declarations.LibraryGetMethod.Name: function.NewBuiltInFunction(
@@ -39,6 +56,45 @@ func (library *Library) Definition() *value.ValueDefinition {
 
 				built_in_declarations.LibraryGetMethod.Type,
 			),
+
+			built_in_declarations.LibraryHasMethod.Name: function.NewBuiltInFunction(
+				function.NewFixedFunctionArgumentValidator(
+					built_in_declarations.LibraryHasMethod.Name,
+					reflect.TypeOf(*new(value_types.StringValue)),
+				),
+
+				func(_ *runtime.Runtime, arguments ...value.Value) value.Value {
+					name := string(arguments[0].(value_types.StringValue))
+					_, ok := slices.BinarySearch(library.SymbolNames, name)
+
+					if !ok && library.HasSymbol != nil {
+						ok = library.HasSymbol(name)
+					}
+
+					return value_types.BooleanValue(ok)
+				},
+
+				built_in_declarations.LibraryHasMethod.Type,
+			),
+
+			built_in_declarations.LibrarySymbolsField.Name: &value_types.TupleValue{
+				Elements: symbolNames,
+			},
 		},
 	}
 }
+
+/*
+ * The sorted names of the given fields, for `Library.SymbolNames`.
+ */
+func SortedSymbolNames[Value any](fields map[string]Value) []string {
+	result := make([]string, 0, len(fields))
+
+	for name := range fields {
+		result = append(result, name)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
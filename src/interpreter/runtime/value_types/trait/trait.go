@@ -0,0 +1,27 @@
+package trait
+
+import (
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types/function"
+)
+
+/*
+ * A set of fields that a struct declaring conformance must have, alongside defaults for any other
+ * fields it may omit.
+ */
+type Trait struct {
+	Name               string
+	RequiredFieldNames []string
+
+	/*
+	 * Called with an instance of a conforming struct and the values of its required fields,
+	 * returning the default fields as a tuple of (name, value) pairs.
+	 */
+	DefaultFieldFactory *function.Function
+}
+
+func (*Trait) Definition() *value.ValueDefinition {
+	return &value.ValueDefinition{
+		Fields: map[string]value.Value{},
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"project_umbrella/interpreter/runtime/value_types"
 	"project_umbrella/interpreter/runtime/value_types/function"
 	"project_umbrella/interpreter/runtime/value_types/library"
+	"project_umbrella/interpreter/runtime/value_types/trait"
 )
 
 func newEqualsMethod(value_ value.Value) *function.Function {
@@ -67,6 +68,9 @@ func newToStringMethod(value_ value.Value) *function.Function {
 			case value_types.StringValue:
 				result = stringToString(value_)
 
+			case *trait.Trait:
+				result = "(trait)"
+
 			case *value_types.TupleValue:
 				result = tupleToString(runtime_, *value_)
 
@@ -1,4 +1,4 @@
-package main
+package native_io
 
 import (
 	"fmt"
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 
+	"project_umbrella/interpreter/loader/library_registry"
 	"project_umbrella/interpreter/parser/parser_types"
 	"project_umbrella/interpreter/runtime"
 	"project_umbrella/interpreter/runtime/value"
@@ -75,3 +76,11 @@ var ReadFile = function.NewBuiltInFunction(
 
 	parser_types.NormalFunction,
 )
+
+func init() {
+	library_registry.Register("io", map[string]value.Value{
+		"Print":    Print,
+		"Println":  Println,
+		"ReadFile": ReadFile,
+	})
+}
@@ -1,4 +1,4 @@
-package main
+package native_math
 
 import (
 	"math"
@@ -6,6 +6,7 @@ import (
 
 	"project_umbrella/interpreter/errors"
 	"project_umbrella/interpreter/errors/runtime_errors"
+	"project_umbrella/interpreter/loader/library_registry"
 	"project_umbrella/interpreter/parser/parser_types"
 	"project_umbrella/interpreter/runtime"
 	"project_umbrella/interpreter/runtime/value"
@@ -44,3 +45,9 @@ var SquareRoot = function.NewBuiltInFunction(
 
 	parser_types.NormalFunction,
 )
+
+func init() {
+	library_registry.Register("math", map[string]value.Value{
+		"SquareRoot": SquareRoot,
+	})
+}
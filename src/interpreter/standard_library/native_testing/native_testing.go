@@ -0,0 +1,86 @@
+package native_testing
+
+import (
+	"reflect"
+	"strings"
+
+	"project_umbrella/interpreter/loader/library_registry"
+	"project_umbrella/interpreter/parser/parser_types"
+	"project_umbrella/interpreter/runtime"
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types"
+	"project_umbrella/interpreter/runtime/value_types/function"
+)
+
+/*
+ * Compare two strings line by line, returning the lines of the first prefixed with "-", those of
+ * the second prefixed with "+", and those they share (found by their longest common subsequence)
+ * prefixed with " ".
+ */
+var Diff = function.NewBuiltInFunction(
+	function.NewFixedFunctionArgumentValidator(
+		"diff",
+		reflect.TypeOf(*new(value_types.StringValue)),
+		reflect.TypeOf(*new(value_types.StringValue)),
+	),
+
+	func(_ *runtime.Runtime, arguments ...value.Value) value.Value {
+		return value_types.StringValue(
+			diff(
+				strings.Split(string(arguments[0].(value_types.StringValue)), "\n"),
+				strings.Split(string(arguments[1].(value_types.StringValue)), "\n"),
+			),
+		)
+	},
+
+	parser_types.NormalFunction,
+)
+
+func diff(removedLines []string, addedLines []string) string {
+	// The length of the longest common subsequence of the lines after `i` and `j`
+	commonLengths := make([][]int, len(removedLines)+1)
+
+	for i := range commonLengths {
+		commonLengths[i] = make([]int, len(addedLines)+1)
+	}
+
+	for i := len(removedLines) - 1; i >= 0; i-- {
+		for j := len(addedLines) - 1; j >= 0; j-- {
+			if removedLines[i] == addedLines[j] {
+				commonLengths[i][j] = commonLengths[i+1][j+1] + 1
+			} else {
+				commonLengths[i][j] = max(commonLengths[i+1][j], commonLengths[i][j+1])
+			}
+		}
+	}
+
+	result := []string{}
+	i := 0
+	j := 0
+
+	for i < len(removedLines) || j < len(addedLines) {
+		switch {
+		case i < len(removedLines) && j < len(addedLines) && removedLines[i] == addedLines[j]:
+			result = append(result, " "+removedLines[i])
+			i++
+			j++
+
+		case j == len(addedLines) ||
+			i < len(removedLines) && commonLengths[i+1][j] >= commonLengths[i][j+1]:
+			result = append(result, "-"+removedLines[i])
+			i++
+
+		default:
+			result = append(result, "+"+addedLines[j])
+			j++
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
+
+func init() {
+	library_registry.Register("testing", map[string]value.Value{
+		"Diff": Diff,
+	})
+}
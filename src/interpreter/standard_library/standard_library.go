@@ -0,0 +1,67 @@
+package standard_library
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+/*
+ * The Krait components of the standard library and the startup file are embedded into the
+ * interpreter, so that it can run without them being installed. Its native components are
+ * registered with `library_registry` by the `native_*` packages instead.
+ */
+//go:embed krait/*.krait startup_file.krait
+var embeddedFiles embed.FS
+
+/*
+ * Embedded files are referred to by their path within `embeddedFiles` prefixed with
+ * `EmbeddedPathPrefix`, which can't be mistaken for a relative path on the filesystem.
+ */
+const EmbeddedPathPrefix = "<embedded>/"
+
+const StartupFilePath = EmbeddedPathPrefix + "startup_file.krait"
+
+func IsEmbeddedPath(path_ string) bool {
+	return strings.HasPrefix(path_, EmbeddedPathPrefix)
+}
+
+/*
+ * Read a source file, which may be embedded.
+ */
+func ReadFile(path_ string) ([]byte, error) {
+	if embeddedPath, ok := strings.CutPrefix(path_, EmbeddedPathPrefix); ok {
+		return embeddedFiles.ReadFile(embeddedPath)
+	}
+
+	return os.ReadFile(path_)
+}
+
+func IsEmbeddedFile(path_ string) bool {
+	embeddedPath, ok := strings.CutPrefix(path_, EmbeddedPathPrefix)
+
+	if !ok {
+		return false
+	}
+
+	info, err := fs.Stat(embeddedFiles, embeddedPath)
+
+	return err == nil && info.Mode().IsRegular()
+}
+
+/*
+ * Find an embedded Krait module given the components of its dot-separated name.
+ */
+func ModulePath(moduleComponents []string) (string, bool) {
+	embeddedPath := path.Join(
+		append([]string{"krait"}, moduleComponents...)...,
+	) + ".krait"
+
+	if !IsEmbeddedFile(EmbeddedPathPrefix + embeddedPath) {
+		return "", false
+	}
+
+	return EmbeddedPathPrefix + embeddedPath, true
+}
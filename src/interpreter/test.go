@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/test_runner"
+)
+
+const testFileSuffix = "_test.krait"
+
+/*
+ * `interpreter test [<path>...]` runs the tests of the given Krait files, and of the files ending in
+ * `_test.krait` in the given directories (by default, the current one), failing if any fail.
+ */
+func runTestCommand(arguments []string) {
+	if len(arguments) == 0 {
+		arguments = []string{"."}
+	}
+
+	explicitPathSet := map[string]bool{}
+
+	for _, argument := range arguments {
+		explicitPathSet[argument] = true
+	}
+
+	paths := []string{}
+
+	for _, path := range kraitFilesInPaths(arguments) {
+		if explicitPathSet[path] || strings.HasSuffix(path, testFileSuffix) {
+			paths = append(paths, path)
+		}
+	}
+
+	summary := test_runner.RunFiles(paths, os.Stdout)
+
+	if summary.FailedCount > 0 {
+		errors.RaiseError(entry_errors.TestsFailed(summary.FailedCount))
+	}
+}
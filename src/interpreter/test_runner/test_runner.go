@@ -0,0 +1,242 @@
+/*
+ * The Test Runner:
+ *
+ * Test files are Krait files whose names end in `_test.krait`. Their tests are their top-level
+ * functions whose names begin with `test_` and take no arguments, and their top-level values
+ * implementing the `testing` module's `Test` trait (or tuples of them), in the order they're
+ * declared. A test passes unless calling it (or its `run` field) raises an error.
+ *
+ * Each file is loaded by a module loader of its own, and each test is called separately, so a
+ * failing test doesn't stop the others from running. Since values are immutable, tests can't
+ * otherwise affect each other.
+ */
+package test_runner
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"project_umbrella/interpreter/bytecode_generator/built_in_declarations"
+	"project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/errors/entry_errors"
+	"project_umbrella/interpreter/errors/runtime_errors"
+	"project_umbrella/interpreter/loader/module_loader"
+	"project_umbrella/interpreter/parser"
+	"project_umbrella/interpreter/parser/parser_types"
+	"project_umbrella/interpreter/runtime"
+	"project_umbrella/interpreter/runtime/built_in_definitions"
+	"project_umbrella/interpreter/runtime/value"
+	"project_umbrella/interpreter/runtime/value_types"
+	"project_umbrella/interpreter/runtime/value_types/function"
+	"project_umbrella/interpreter/runtime/value_util"
+	"project_umbrella/interpreter/standard_library"
+)
+
+const testFunctionPrefix = "test_"
+
+type test struct {
+	name     string
+	position *errors.Position // Where it's declared
+	run      value.Value
+}
+
+// The number of tests that passed and failed. Test files that couldn't be loaded count as failures.
+type Summary struct {
+	PassedCount int
+	FailedCount int
+}
+
+/*
+ * Run the tests of the files at `paths`, reporting each test's result to `writer` followed by how
+ * many passed and failed.
+ */
+func RunFiles(paths []string, writer io.Writer) *Summary {
+	result := &Summary{
+		PassedCount: 0,
+		FailedCount: 0,
+	}
+
+	for _, path := range paths {
+		runFile(filepath.Clean(path), writer, result)
+	}
+
+	fmt.Fprintf(writer, "\n%d passed, %d failed\n", result.PassedCount, result.FailedCount)
+
+	return result
+}
+
+func runFile(path string, writer io.Writer, summary *Summary) {
+	loader := module_loader.NewModuleLoader()
+
+	err := errors.Catch(func() {
+		module := loader.LoadFile(path)
+		source, err := standard_library.ReadFile(path)
+
+		if err != nil {
+			errors.RaiseError(entry_errors.FileNotOpened(path))
+		}
+
+		// Tests are found among the file's own statements, excluding those of the startup file.
+		expressionList := parser.ParseSource(path, string(source)).AbstractExpressionList()
+		testTrait := testTrait(loader, path)
+
+		loader.WithRuntime(path, func(runtime_ *runtime.Runtime) {
+			for _, test_ := range tests(runtime_, expressionList, module, testTrait) {
+				err := errors.Catch(func() {
+					run, ok := test_.run.(*function.Function)
+
+					if !ok {
+						errors.RaiseError(runtime_errors.NonFunctionCalled)
+					}
+
+					run.Evaluate(runtime_)
+				})
+
+				if err == nil {
+					summary.PassedCount++
+					fmt.Fprintf(writer, "PASS %s: %s\n", location(test_.position), test_.name)
+				} else {
+					summary.FailedCount++
+					fmt.Fprintf(writer, "FAIL %s: %s\n", location(test_.position), test_.name)
+					writeError(writer, path, err)
+				}
+			}
+		})
+	})
+
+	if err != nil {
+		summary.FailedCount++
+		fmt.Fprintf(writer, "FAIL %s\n", path)
+		writeError(writer, path, err)
+	}
+}
+
+/*
+ * The `Test` trait, as declared by the `testing` module the test file at `path` would import (if
+ * there is one).
+ */
+func testTrait(loader *module_loader.ModuleLoader, path string) value.Value {
+	testingPath, ok := loader.ModulePath("testing", filepath.Dir(path))
+
+	if !ok {
+		return nil
+	}
+
+	var result value.Value
+
+	loader.WithRuntime(path, func(runtime_ *runtime.Runtime) {
+		result = value_util.LookupField(
+			runtime_,
+			loader.LoadFile(testingPath),
+			"Test",
+			parser_types.NormalSelect,
+		)
+	})
+
+	return result
+}
+
+// The tests declared by the statements of a test file, whose module is `module`
+func tests(
+	runtime_ *runtime.Runtime,
+	expressionList *parser.ExpressionList,
+	module value.Value,
+	testTrait value.Value,
+) []*test {
+	result := []*test{}
+
+	for _, statement := range expressionList.Children_ {
+		if function_, ok := statement.(*parser.Function); ok && function_.Name != nil &&
+			strings.HasPrefix(function_.Name.Value, testFunctionPrefix) &&
+			len(function_.Parameters) == 0 {
+			result = append(result, &test{
+				name:     function_.Name.Value,
+				position: function_.Name.Position(),
+				run:      lookUp(runtime_, module, function_.Name.Value),
+			})
+
+			continue
+		}
+
+		declaration, ok := statement.(parser.Declaration)
+
+		if !ok || testTrait == nil {
+			continue
+		}
+
+		for _, name := range declaration.Names() {
+			if parser_types.IsPrivateName(name.Value) {
+				continue
+			}
+
+			value_ := lookUp(runtime_, module, name.Value)
+			elements := []value.Value{value_}
+
+			if tuple, ok := value_.(*value_types.TupleValue); ok {
+				elements = tuple.Elements
+			}
+
+			for _, element := range elements {
+				if !implements(runtime_, element, testTrait) {
+					continue
+				}
+
+				result = append(result, &test{
+					name: string(
+						value_util.CallToStringMethod(runtime_, lookUp(runtime_, element, "name")),
+					),
+
+					position: name.Position(),
+					run:      lookUp(runtime_, element, "run"),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+func lookUp(runtime_ *runtime.Runtime, value_ value.Value, fieldName string) value.Value {
+	return value_util.LookupField(runtime_, value_, fieldName, parser_types.NormalSelect)
+}
+
+func implements(runtime_ *runtime.Runtime, value_ value.Value, trait value.Value) bool {
+	implementsFunction :=
+		built_in_definitions.BuiltInValues[built_in_declarations.ImplementsFunctionID]
+
+	return bool(
+		implementsFunction.(*function.Function).
+			Evaluate(runtime_, value_, trait).(value_types.BooleanValue),
+	)
+}
+
+// Positions are located by their file and line (e.g. "math_test.krait:3").
+func location(position *errors.Position) string {
+	line, _ := position.StartLineAndColumn()
+
+	return fmt.Sprintf("%s:%d", position.Filename, line)
+}
+
+/*
+ * Write an error raised by a test in the file at `path`, indented beneath the test's result and
+ * preceded by the innermost statement in the file being evaluated when it was raised (if any).
+ */
+func writeError(writer io.Writer, path string, error_ *errors.Error) {
+	for _, position := range error_.Trace {
+		if position.Filename == path {
+			fmt.Fprintf(writer, "    at %s\n", location(position))
+
+			break
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(error_.String(), "\n"), "\n") {
+		if line == "" {
+			fmt.Fprintln(writer)
+		} else {
+			fmt.Fprintf(writer, "    %s\n", line)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"project_umbrella/interpreter/runtime"
+	"project_umbrella/interpreter/tracer"
+)
+
+/*
+ * `interpreter run --trace <output> <file>` runs the given file, then writes a trace of the blocks
+ * the runtime evaluated to the output file, in the Chrome trace event format. The trace is written
+ * even if the program fails.
+ */
+func traceRun(instruments *runtime.Instruments, outputPath string) instrumentOutput {
+	tracer_ := tracer.NewTracer()
+	instruments.Tracer = tracer_
+
+	return instrumentOutput{
+		path:  outputPath,
+		write: tracer_.WriteJSON,
+	}
+}
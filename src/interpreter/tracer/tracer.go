@@ -0,0 +1,162 @@
+/*
+ * The Tracer:
+ *
+ * When tracing, each block the runtime evaluates (i.e. each consolidated node of a function's block
+ * graph) is recorded with the function it belongs to, its instructions, the goroutine evaluating it
+ * and when it became ready (once the blocks it depends on were evaluated), started and ended.
+ *
+ * Traces are written in the Chrome trace event format, viewable in `chrome://tracing` or Perfetto.
+ * Each goroutine is shown as a thread, so the blocks evaluated at once show the parallelism
+ * realised, while the "Ready blocks" counter (the number of blocks ready but not yet started) shows
+ * the parallelism available.
+ */
+package tracer
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Tracer struct {
+	start time.Time
+	mutex sync.Mutex
+
+	events          []*traceEvent
+	readyBlockCount int
+
+	// The goroutines that have evaluated blocks, each named in the trace once
+	goroutines map[int64]bool
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{
+		start:           time.Now(),
+		events:          []*traceEvent{},
+		readyBlockCount: 0,
+		goroutines:      map[int64]bool{},
+	}
+}
+
+// A block the runtime evaluated
+type Block struct {
+	Function     string // The qualified name of the function the block belongs to
+	Instructions []string
+	Goroutine    int64
+	Ready        time.Time
+	Start        time.Time
+	End          time.Time
+}
+
+// An event in the trace event format, whose fields are named as they're encoded
+type traceEvent struct {
+	Name      string         `json:"name"`
+	Category  string         `json:"cat,omitempty"`
+	Phase     string         `json:"ph"`
+	Timestamp float64        `json:"ts"`
+	Duration  float64        `json:"dur,omitempty"`
+	ProcessID int            `json:"pid"`
+	ThreadID  int64          `json:"tid"`
+	Arguments map[string]any `json:"args,omitempty"`
+}
+
+// Record that a block has become ready, returning the time it did so.
+func (tracer *Tracer) BlockReady() time.Time {
+	return tracer.changeReadyBlockCount(1)
+}
+
+// Record that a ready block has started, returning the time it did so.
+func (tracer *Tracer) BlockStarted() time.Time {
+	return tracer.changeReadyBlockCount(-1)
+}
+
+func (tracer *Tracer) changeReadyBlockCount(change int) time.Time {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	result := time.Now()
+	tracer.readyBlockCount += change
+	tracer.events = append(tracer.events, &traceEvent{
+		Name:      "Ready blocks",
+		Phase:     "C",
+		Timestamp: tracer.timestamp(result),
+		ProcessID: 1,
+		ThreadID:  0,
+		Arguments: map[string]any{
+			"ready": tracer.readyBlockCount,
+		},
+	})
+
+	return result
+}
+
+// Record a block once it has been evaluated.
+func (tracer *Tracer) BlockEvaluated(block *Block) {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	if !tracer.goroutines[block.Goroutine] {
+		tracer.goroutines[block.Goroutine] = true
+		tracer.events = append(tracer.events, &traceEvent{
+			Name:      "thread_name",
+			Phase:     "M",
+			ProcessID: 1,
+			ThreadID:  block.Goroutine,
+			Arguments: map[string]any{
+				"name": "Goroutine " + strconv.FormatInt(block.Goroutine, 10),
+			},
+		})
+	}
+
+	tracer.events = append(tracer.events, &traceEvent{
+		Name:      block.Function,
+		Category:  "block",
+		Phase:     "X",
+		Timestamp: tracer.timestamp(block.Start),
+		Duration:  float64(block.End.Sub(block.Start).Nanoseconds()) / 1000,
+		ProcessID: 1,
+		ThreadID:  block.Goroutine,
+		Arguments: map[string]any{
+			"instructions": block.Instructions,
+			"ready":        tracer.timestamp(block.Ready),
+			"waited":       float64(block.Start.Sub(block.Ready).Nanoseconds()) / 1000,
+		},
+	})
+}
+
+// Trace events' times are in microseconds since the trace started.
+func (tracer *Tracer) timestamp(time_ time.Time) float64 {
+	return float64(time_.Sub(tracer.start).Nanoseconds()) / 1000
+}
+
+func (tracer *Tracer) WriteJSON(writer io.Writer) error {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	return json.NewEncoder(writer).Encode(map[string]any{
+		"traceEvents":     tracer.events,
+		"displayTimeUnit": "ns",
+	})
+}
+
+/*
+ * The ID of the calling goroutine. Go doesn't expose it other than in stack traces, which begin
+ * with "goroutine <ID> [<status>]:".
+ */
+func CurrentGoroutine() int64 {
+	buffer := make([]byte, 64)
+	buffer = buffer[:runtime.Stack(buffer, false)]
+	fields := strings.Fields(string(buffer))
+
+	if len(fields) < 2 {
+		return 0
+	}
+
+	result, _ := strconv.ParseInt(fields[1], 10, 64)
+
+	return result
+}
@@ -1,7 +1,9 @@
 """
 Defines a rule generating the Krait standard library from its components written in Krait
-(located in `src/standard_library/krait`) and its native library components
-(located in `src/standard_library/native`).
+(located in `src/interpreter/standard_library/krait`, which are also embedded into the
+interpreter) and any native library components built as plugins. The standard library's own
+native components (located in `src/interpreter/standard_library/native_*`) are compiled into the
+interpreter instead.
 """
 
 def _krait_standard_library_impl(ctx):
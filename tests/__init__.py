@@ -1,11 +1,18 @@
 import contextlib
+import json
 import os
 import subprocess
 import tempfile
 
 REPOSITORY_DIRECTORY = os.environ["BUILD_WORKING_DIRECTORY"]
 STANDARD_LIBRARY_DIRECTORY = os.path.join("src", "standard_library", "standard_library")
-STARTUP_FILE_PATH = os.path.join(REPOSITORY_DIRECTORY, "src", "startup_file.krait")
+STARTUP_FILE_PATH = os.path.join(
+	REPOSITORY_DIRECTORY,
+	"src",
+	"interpreter",
+	"standard_library",
+	"startup_file.krait"
+)
 
 def output_from_code(
 	code: str,
@@ -66,3 +73,376 @@ def output_from_multiple_files(
 			)
 
 		return process.stdout
+
+def interpreter_path() -> str:
+	return os.path.abspath(os.path.join("src", "interpreter", "interpreter_", "interpreter"))
+
+def interpreter_environment(krait_path_directories: list[str]) -> dict[str, str]:
+	"""
+	The environment the interpreter is run in: modules are searched for in `krait_path_directories`,
+	then in the standard library, whose modules aren't preceded by the startup file.
+	"""
+
+	standard_library_directory = os.path.abspath(STANDARD_LIBRARY_DIRECTORY)
+
+	return {
+		**os.environ,
+		"KRAIT_PATH": ":".join([*krait_path_directories, standard_library_directory]),
+		"KRAIT_STARTUP": STARTUP_FILE_PATH,
+		"KRAIT_STARTUP_EXCLUDE": standard_library_directory
+	}
+
+def write_files(directory: str, files: dict[str, str]) -> None:
+	"""
+	Write `files`, keyed by their paths relative to `directory`, creating any missing directories.
+	"""
+
+	for path, code in files.items():
+		full_path = os.path.join(directory, path)
+
+		os.makedirs(os.path.dirname(full_path), exist_ok=True)
+
+		with open(full_path, mode="w") as file:
+			file.write(code)
+
+def run_interpreter(
+	arguments: list[str],
+	expected_return_code=0,
+	**options
+) -> subprocess.CompletedProcess:
+	"""
+	Run the interpreter with `arguments`, failing unless it returns `expected_return_code`. Its
+	stdout and stderr are captured together as text, unless `options` (passed on to
+	`subprocess.run`) say otherwise.
+	"""
+
+	process = subprocess.run(
+		[interpreter_path(), *arguments],
+		**{"stdout": subprocess.PIPE, "stderr": subprocess.STDOUT, "text": True, **options}
+	)
+
+	if process.returncode != expected_return_code:
+		print(process.stdout, end="")
+
+		raise AssertionError(
+			f"Expected a return code of {expected_return_code}; got {process.returncode}"
+		)
+
+	return process
+
+def output_from_commands(
+	files: dict[str, str],
+	commands: list[list[str]],
+	working_directory: str = ".",
+	expected_return_code=0,
+	krait_path_directories: list[str] = [],
+	environment: dict[str, str | None] = {}
+) -> str:
+	"""
+	Run the interpreter once per command, from `working_directory` (relative to the directory
+	containing `files`). Every command but the last must succeed. Environment variables mapped to
+	`None` in `environment` are unset.
+	"""
+
+	with tempfile.TemporaryDirectory() as directory:
+		write_files(directory, files)
+
+		command_environment = {
+			**interpreter_environment(
+				[*(os.path.abspath(path) for path in krait_path_directories), directory]
+			),
+
+			**environment
+		}
+
+		output = ""
+
+		for i, command in enumerate(commands):
+			output += run_interpreter(
+				command,
+				expected_return_code if i == len(commands) - 1 else 0,
+				cwd=os.path.join(directory, working_directory),
+				env={
+					name: value for name, value in command_environment.items() if value is not None
+				}
+			).stdout
+
+		return output
+
+def formatted_code(code: str, expected_return_code=0, arguments: list[str] = []) -> str:
+	"""
+	Format `code` with `interpreter fmt`, returning the formatted source (or, if formatting is
+	expected to fail, the interpreter's output).
+	"""
+
+	with tempfile.TemporaryDirectory() as directory:
+		path = os.path.join(directory, "main.krait")
+
+		write_files(directory, {"main.krait": code})
+
+		process = run_interpreter(["fmt", *arguments, path], expected_return_code)
+
+		if expected_return_code != 0:
+			return process.stdout
+
+		with open(path) as file:
+			return file.read()
+
+def documentation_pages(files: dict[str, str], arguments: list[str] = []) -> dict[str, str]:
+	"""
+	Document the modules in `files`, and any given in `arguments`, with `interpreter doc`, returning
+	the content of each page written, keyed by its file name.
+	"""
+
+	with tempfile.TemporaryDirectory() as directory:
+		source_directory = os.path.join(directory, "source")
+		output_directory = os.path.join(directory, "output")
+		os.mkdir(source_directory)
+
+		write_files(source_directory, files)
+
+		run_interpreter(
+			[
+				"doc",
+				"--output",
+				output_directory,
+				*arguments,
+				*([source_directory] if files else [])
+			],
+			env=interpreter_environment([source_directory])
+		)
+
+		result = {}
+
+		for name in os.listdir(output_directory):
+			with open(os.path.join(output_directory, name)) as file:
+				result[name] = file.read()
+
+		return result
+
+def lint_output(code: str, expected_return_code=1) -> str:
+	"""
+	Lint `code` with `interpreter lint`, returning the interpreter's output.
+	"""
+
+	with tempfile.TemporaryDirectory() as directory:
+		write_files(directory, {"main.krait": code})
+
+		return run_interpreter(
+			["lint", os.path.join(directory, "main.krait")],
+			expected_return_code,
+			env=interpreter_environment([directory])
+		).stdout
+
+def language_server_messages(files: dict[str, str], messages: list[dict]) -> list[dict]:
+	"""
+	Send `messages` to `interpreter lsp` between initializing it and shutting it down, returning
+	the messages it sends in response (excluding those to initialization and shutdown).
+	`$DIRECTORY` in either refers to the directory containing `files`.
+	"""
+
+	with tempfile.TemporaryDirectory() as directory:
+		write_files(directory, files)
+
+		input_ = b""
+
+		for message in [
+			{"jsonrpc": "2.0", "id": "initialize", "method": "initialize", "params": {}},
+			*messages,
+			{"jsonrpc": "2.0", "id": "shutdown", "method": "shutdown"},
+			{"jsonrpc": "2.0", "method": "exit"}
+		]:
+			encoded = json.dumps(message).replace("$DIRECTORY", directory).encode()
+			input_ += f"Content-Length: {len(encoded)}\r\n\r\n".encode() + encoded
+
+		# Responses shouldn't list the standard library's modules, so it isn't in `KRAIT_PATH`.
+		process = run_interpreter(
+			["lsp"],
+			input=input_,
+			stderr=None,
+			text=False,
+			env={**interpreter_environment([]), "KRAIT_PATH": directory}
+		)
+
+		result = []
+		output = process.stdout
+
+		while output:
+			header, output = output.split(b"\r\n\r\n", 1)
+			length = int(header.removeprefix(b"Content-Length: "))
+			result.append(json.loads(output[:length].decode().replace(directory, "$DIRECTORY")))
+			output = output[length:]
+
+		return [message for message in result if message.get("id") not in ("initialize", "shutdown")]
+
+def instrumented_output(code: str, arguments: list[str], expected_return_code=0) -> bytes:
+	"""
+	Run `code` with `interpreter <arguments> <output> main.krait` (e.g. with `run --profile` as
+	the arguments), returning what was written to the output file.
+	"""
+
+	with tempfile.TemporaryDirectory() as directory:
+		output_path = os.path.join(directory, "output")
+		write_files(directory, {"main.krait": code})
+
+		run_interpreter(
+			[*arguments, output_path, os.path.join(directory, "main.krait")],
+			expected_return_code,
+			env=interpreter_environment([directory])
+		)
+
+		with open(output_path, mode="rb") as file:
+			return file.read()
+
+def debugger_output(code: str, commands: list[str], expected_return_code=0) -> str:
+	"""
+	Debug `code` with `interpreter debug main.krait`, entering `commands` one per line, returning
+	the interpreter's output. `$DIRECTORY` in the output refers to the directory containing
+	`main.krait`.
+	"""
+
+	with tempfile.TemporaryDirectory() as directory:
+		write_files(directory, {"main.krait": code})
+
+		process = run_interpreter(
+			["debug", os.path.join(directory, "main.krait")],
+			expected_return_code,
+			input="".join(f"{command}\n" for command in commands),
+			timeout=60,
+			env=interpreter_environment([directory])
+		)
+
+		return process.stdout.replace(os.path.realpath(directory), "$DIRECTORY").replace(
+			directory,
+			"$DIRECTORY"
+		)
+
+class DebugAdapterSession:
+	"""
+	A client of `interpreter debug --dap`, debugging `main.krait` in a directory of its own.
+	"""
+
+	def __init__(self, code: str):
+		self.directory = tempfile.TemporaryDirectory()
+		self.path = os.path.join(self.directory.name, "main.krait")
+		self.next_seq = 1
+
+		# Events received while waiting for responses, in order
+		self.events = []
+
+		write_files(self.directory.name, {"main.krait": code})
+
+		self.process = subprocess.Popen(
+			[interpreter_path(), "debug", "--dap"],
+			stdin=subprocess.PIPE,
+			stdout=subprocess.PIPE,
+			env=interpreter_environment([self.directory.name])
+		)
+
+	def __enter__(self) -> "DebugAdapterSession":
+		return self
+
+	def __exit__(self, *_) -> None:
+		self.process.kill()
+		self.process.wait()
+		self.process.stdin.close()
+		self.process.stdout.close()
+		self.directory.cleanup()
+
+	def receive(self) -> dict:
+		header = b""
+
+		while not header.endswith(b"\r\n\r\n"):
+			byte = self.process.stdout.read(1)
+
+			if not byte:
+				raise AssertionError("The debug adapter exited unexpectedly")
+
+			header += byte
+
+		length = int(header.strip().removeprefix(b"Content-Length: "))
+
+		return json.loads(self.process.stdout.read(length))
+
+	def request(self, command: str, arguments: dict = {}) -> dict:
+		"""
+		Send a request, returning the response to it.
+		"""
+
+		seq = self.next_seq
+		self.next_seq += 1
+		encoded = json.dumps(
+			{"seq": seq, "type": "request", "command": command, "arguments": arguments}
+		).encode()
+
+		self.process.stdin.write(f"Content-Length: {len(encoded)}\r\n\r\n".encode() + encoded)
+		self.process.stdin.flush()
+
+		while True:
+			message = self.receive()
+
+			if message["type"] == "event":
+				self.events.append(message)
+			elif message["request_seq"] == seq:
+				return message
+
+	def wait_for_event(self, name: str) -> dict:
+		"""
+		Return the first event named `name` not yet waited for, receiving messages until it's sent.
+		"""
+
+		while True:
+			for i, event in enumerate(self.events):
+				if event["event"] == name:
+					return self.events.pop(i)
+
+			self.events.append(self.receive())
+
+class WatchSession:
+	"""
+	A run of `interpreter run --watch main.krait` in a directory of its own, containing `files`.
+	"""
+
+	def __init__(self, files: dict[str, str]):
+		self.directory = tempfile.TemporaryDirectory()
+
+		for path, code in files.items():
+			self.write(path, code)
+
+		self.process = subprocess.Popen(
+			[interpreter_path(), "run", "--watch", "main.krait"],
+			cwd=self.directory.name,
+			stdout=subprocess.PIPE,
+			stderr=subprocess.STDOUT,
+			text=True,
+			env=interpreter_environment([])
+		)
+
+	def __enter__(self) -> "WatchSession":
+		return self
+
+	def __exit__(self, *_) -> None:
+		self.process.kill()
+		self.process.wait()
+		self.process.stdout.close()
+		self.directory.cleanup()
+
+	def write(self, path: str, code: str) -> None:
+		write_files(self.directory.name, {path: code})
+
+	def read_until(self, last_line: str) -> list[str]:
+		"""
+		Return the lines written by the program up to and including `last_line`.
+		"""
+
+		result = []
+
+		while not result or result[-1] != last_line:
+			line = self.process.stdout.readline()
+
+			if not line:
+				raise AssertionError(f"The program exited unexpectedly after: {result}")
+
+			result.append(line.removesuffix("\n"))
+
+		return result
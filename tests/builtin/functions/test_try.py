@@ -0,0 +1,65 @@
+from tests import output_from_code
+
+def test_try() -> None:
+	assert output_from_code("println(try((): 42, (_): 0))\n") == "42\n"
+	assert output_from_code(
+		"""\
+println(try((): fail(("oops", 3)), (error): (error, error.description, error.value)))
+"""
+	) == "(Error (RUNTIME-25): A failure was raised, (oops, 3), (oops, 3))\n"
+
+	assert output_from_code(
+		"""\
+println(
+	try(
+		(): try((): fail(1), (error): fail(error.value + 1)),
+		(error): error.value
+	)
+)
+"""
+	) == "2\n"
+
+def test_try_built_in_errors() -> None:
+	assert output_from_code(
+		"""\
+println(
+	try(
+		(): (1, 2).get(5),
+		(error): (error.section, error.code, error.value)
+	)
+)
+"""
+	) == "(RUNTIME, 14, (unit))\n"
+
+	assert output_from_code(
+		"""\
+println(try((): 1 / 0, (error): error.name))
+"""
+	) == "Cannot divide by zero\n"
+
+def test_attempt() -> None:
+	assert output_from_code(
+		"""\
+either = import("either")
+
+println(
+	(
+		either.attempt((): 1).fold((_): "failed", (value): value),
+		either.attempt((): fail(2)).fold((error): error.value, (_): "succeeded")
+	)
+)
+"""
+	) == "(1, 2)\n"
+
+def test_fail() -> None:
+	assert output_from_code("fail(\"Something went wrong\")\n", expected_return_code=1) == """\
+Error (RUNTIME-25): A failure was raised
+
+Something went wrong
+"""
+
+	assert output_from_code("try((): 1, 2)\n", expected_return_code=1) == """\
+Error (RUNTIME-2): A built-in function was called with an argument of incorrect type
+
+try expected argument #2 to be of a different type.
+"""
@@ -0,0 +1,172 @@
+from tests import output_from_code
+
+def test_tuple_destructuring() -> None:
+	assert output_from_code(
+		"""\
+(first, second) = ("foo", "bar")
+
+println(first, second)
+"""
+	) == "foo bar\n"
+
+	assert output_from_code(
+		"""\
+(only,) = ("foo",)
+
+println(only)
+"""
+	) == "foo\n"
+
+	assert output_from_code(
+		"""\
+(first, (second, third)) = (1, (2, 3))
+
+println(first + second + third)
+"""
+	) == "6\n"
+
+	assert output_from_code(
+		"""\
+pair = (first, second) = (1, 2)
+
+println(pair, first, second)
+"""
+	) == "(1, 2) 1 2\n"
+
+def test_struct_destructuring() -> None:
+	assert output_from_code(
+		"""\
+struct Range(self, start, end):
+	length = end - start
+
+Range(start, end) = Range(2, 5)
+
+println(start, end)
+"""
+	) == "2 5\n"
+
+	assert output_from_code(
+		"""\
+struct Box(self, value):
+
+Box((first, Box(second))) = Box((1, Box(2)))
+
+println(first, second)
+"""
+	) == "1 2\n"
+
+def test_parameter_destructuring() -> None:
+	assert output_from_code(
+		"""\
+fn add((number1, number2), number3):
+	number1 + number2 + number3
+
+println(add((1, 2), 3))
+"""
+	) == "6\n"
+
+	assert output_from_code(
+		"""\
+struct Range(self, start, end):
+
+println(((Range(start, end)): end - start)(Range(2, 5)))
+"""
+	) == "3\n"
+
+	assert output_from_code(
+		"""\
+value = 1
+
+fn first((value, _)):
+	value
+
+println(first((2, 3)))
+"""
+	) == "2\n"
+
+	assert output_from_code("struct Struct(self, (first, second)):\n", expected_return_code=1) == """\
+Error (PARSER-8): A struct's parameters cannot be destructured
+
+  1  │ struct Struct(self, (first, second)):
+     │                     ^^^^^^^^^^^^^^^
+
+Consider destructuring the parameter in the struct's body instead.
+"""
+
+def test_invalid_destructuring() -> None:
+	assert output_from_code("(first, second) = (1, 2, 3)\n", expected_return_code=1) == """\
+Error (RUNTIME-20): A pattern's length doesn't match that of the value it destructures
+
+(1, 2, 3) has 3 elements, but the pattern has 2.
+"""
+
+	assert output_from_code(
+		"""\
+struct Box(self, value):
+
+Box(first, second) = Box(1)
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-20): A pattern's length doesn't match that of the value it destructures
+
+Box(1) has 1 argument, but the pattern has 2.
+"""
+
+	assert output_from_code("(first, second) = 1\n", expected_return_code=1) == """\
+Error (RUNTIME-21): A tuple pattern was used to destructure a non-tuple
+
+Expected a tuple, but got 1.
+"""
+
+	assert output_from_code(
+		"""\
+struct Box1(self, value):
+struct Box2(self, value):
+
+Box1(value) = Box2(1)
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-22): A struct pattern was used to destructure a value of a different type
+
+Expected an instance of Box1, but got Box2(1).
+"""
+
+	assert output_from_code(
+		"""\
+struct Point(self, x, y):
+
+Point(x, y) = import("option")
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-22): A struct pattern was used to destructure a value of a different type
+
+Expected an instance of Point, but got a module.
+"""
+
+	assert output_from_code("(value, value) = (1, 2)\n", expected_return_code=1) == """\
+Error (PARSER-5): Reassigning to an already declared value is impossible
+
+  1  │ (value, value) = (1, 2)
+     │ ^^^^^^^^^^^^^^^^^^^^^^^
+
+Consider assigning to a new value.
+"""
+
+	assert output_from_code(
+		"""\
+value = 1
+(value, other) = (1, 2)
+""",
+		expected_return_code=1
+	) == """\
+Error (PARSER-5): Reassigning to an already declared value is impossible
+
+  1  │ value = 1
+  2  │ (value, other) = (1, 2)
+     │ ^^^^^^^^^^^^^^^^^^^^^^^
+
+Consider assigning to a new value.
+"""
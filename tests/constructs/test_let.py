@@ -0,0 +1,44 @@
+from tests import output_from_code
+
+def test_let() -> None:
+	assert output_from_code("println(let width = 2, height = 3 in width * height)\n") == "6\n"
+	assert output_from_code(
+		"""\
+perimeter = let
+	width = 2
+	height = 3
+in 2 * (width + height)
+
+println(perimeter)
+"""
+	) == "10\n"
+
+	assert output_from_code(
+		"""\
+fn describe(number):
+	let
+		is_negative = number < 0
+		is_even = (number % 2) == 0
+	in (is_negative, is_even)
+
+println(describe(-3))
+"""
+	) == "(true, false)\n"
+
+def test_let_scope() -> None:
+	assert output_from_code(
+		"""\
+area = let width = 2 in width * width
+
+println(width)
+""",
+		expected_return_code=1
+	) == """\
+Error (PARSER-6): Unknown value: `width`
+
+  1  │ area = let width = 2 in width * width
+  2  │ 
+  3  │ println(width)
+     │         ^^^^^
+
+"""
@@ -248,3 +248,268 @@ Error (RUNTIME-2): A built-in function was called with an argument of incorrect
 
 __is_instance_of__ expected argument #1 to be of a different type.
 """
+
+def test_copy() -> None:
+	assert output_from_code(
+		"""\
+struct Range(self, start, end):
+	length = end - start
+
+range = Range(2, 5)
+wider = range.(end = 10)
+moved = range.(
+	start = 0,
+	end = 1,
+)
+
+println((range, wider, wider.length, moved, moved.length))
+"""
+	) == "(Range(2, 5), Range(2, 10), 8, Range(0, 1), 1)\n"
+
+	assert output_from_code(
+		"""\
+struct Bag(self, label, ...items):
+	count = items.length
+
+bag = Bag("a", 1, 2).(label = "b")
+
+println((bag, bag.count, bag.(items = (1, 2, 3)).count))
+"""
+	) == "(Bag(b, (1, 2)), 2, 3)\n"
+
+	assert output_from_code(
+		"""\
+struct Box(self, value):
+
+println(Box(1).__copy__(("value", 2)))
+"""
+	) == "Box(2)\n"
+
+def test_invalid_copy() -> None:
+	assert output_from_code(
+		"""\
+struct Range(self, start, end):
+	length = end - start
+
+Range(2, 5).(length = 1)
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-23): Only a struct's arguments can be replaced when copying it
+
+`length` isn't an argument of Range. Its other fields are recomputed from its arguments.
+"""
+
+	assert output_from_code(
+		"""\
+struct Bag(self, ...items):
+
+Bag(1).(items = 2)
+""",
+		expected_return_code=1
+	) == "Error (RUNTIME-19): A non-tuple was spread into a call's arguments\n"
+
+	assert output_from_code(
+		"""\
+struct Point(self, x, y):
+
+Point(1, 2).(x = 5, x = 7)
+""",
+		expected_return_code=1
+	) == """\
+Error (PARSER-12): Argument replaced more than once by a copy: `x`
+
+  1  │ struct Point(self, x, y):
+  2  │ 
+  3  │ Point(1, 2).(x = 5, x = 7)
+     │                     ^
+
+"""
+
+def test_private_fields() -> None:
+	assert output_from_code(
+		"""\
+struct Circle(self, radius):
+	_pi = 3
+	area = _pi * radius * radius
+
+	fn describe():
+		"Circle with an area of " + area.__to_str__()
+
+println((Circle(2).describe(), Circle(2), Circle(2) == Circle(2)))
+"""
+	) == "(Circle with an area of 12, Circle(2), true)\n"
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+	fn can_afford(price):
+		_balance >= price
+
+	fn withdraw(amount):
+		self.(_balance = _balance - amount)
+
+account = Account(10)
+
+println((
+	account.can_afford(5),
+	account.withdraw(9).can_afford(5),
+	account == Account(10),
+	account == Account(5),
+	account.__arguments__
+))
+"""
+	) == "(true, false, true, false, (,))\n"
+
+	assert output_from_code(
+		"""\
+struct Account(self, owner, _balance):
+	_pin = 1234
+
+println((Account("bob", 5), Account("bob", 5).__arguments__))
+"""
+	) == "(Account(bob), (bob,))\n"
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+	fn balance():
+		_balance
+
+println(Account(5).(_balance = 100).balance())
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-26): Private field selected: `_balance`
+
+Fields whose names begin with an underscore can only be referenced by name from within the code declaring them.
+"""
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+	fn applied(function):
+		function(self)
+
+Account(5).applied((account): account.(_balance = 100))
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-26): Private field selected: `_balance`
+
+Fields whose names begin with an underscore can only be referenced by name from within the code declaring them.
+"""
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+	fn balance():
+		_balance
+
+	fn emptied():
+		account = self
+		account.(_balance = 0)
+
+	fn merged(other):
+		other.(_balance = _balance + other.balance())
+
+println((Account(5).emptied().balance(), Account(5).merged(Account(2)).balance()))
+"""
+	) == "(0, 7)\n"
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+	_cents = _balance * 100
+
+	fn richer_than(other):
+		self._cents > other._cents
+
+	fn deposited(amount):
+		self.(_balance = self._balance + amount)
+
+println((Account(5).richer_than(Account(2)), Account(5).richer_than(Account(5).deposited(1))))
+"""
+	) == "(true, false)\n"
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+
+struct Thief(self, account):
+	fn stolen():
+		account._balance
+
+Thief(Account(5)).stolen()
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-26): Private field selected: `_balance`
+
+Fields whose names begin with an underscore can only be referenced by name from within the code declaring them.
+"""
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+
+name = "_balance"
+
+Account(5).__copy__((name, 100))
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-26): Private field selected: `_balance`
+
+Fields whose names begin with an underscore can only be referenced by name from within the code declaring them.
+"""
+
+	assert output_from_code(
+		"""\
+struct Account(self, _balance):
+
+struct Thief(self, account):
+	emptied = account.(_balance = 0)
+
+Thief(Account(5))
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-26): Private field selected: `_balance`
+
+Fields whose names begin with an underscore can only be referenced by name from within the code declaring them.
+"""
+
+	assert output_from_code(
+		"""\
+struct Circle(self, radius):
+	_pi = 3
+
+println(Circle(1)("_pi"))
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-26): Private field selected: `_pi`
+
+Fields whose names begin with an underscore can only be referenced by name from within the code declaring them.
+"""
+
+	assert output_from_code(
+		"""\
+struct Circle(self, radius):
+	_pi = 3
+
+println(Circle(1)._pi)
+""",
+		expected_return_code=1
+	) == """\
+Error (PARSER-9): Private field selected: `_pi`
+
+  1  │ struct Circle(self, radius):
+  2  │     _pi = 3
+  3  │ 
+  4  │ println(Circle(1)._pi)
+     │                   ^^^
+
+Names beginning with an underscore are private to the module or struct declaring them.
+"""
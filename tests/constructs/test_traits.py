@@ -0,0 +1,88 @@
+from tests import output_from_code
+
+def test_traits() -> None:
+	assert output_from_code(
+		"""\
+trait Shape(self, area):
+	fn describe():
+		"Shape with an area of " + area.__to_str__()
+
+	fn doubled_area():
+		self.area * 2
+
+struct Square(self, side) implements Shape:
+	area = side * side
+
+println((Square(3).describe(), Square(3).doubled_area()))
+"""
+	) == "(Shape with an area of 9, 18)\n"
+
+	assert output_from_code(
+		"""\
+trait Shape(self, area):
+	fn describe():
+		"Shape"
+
+struct Circle(self, radius) implements Shape:
+	area = radius * radius * 3
+
+	fn describe():
+		"Circle"
+
+println(Circle(1).describe())
+"""
+	) == "Circle\n"
+
+	assert output_from_code(
+		"""\
+trait Shape(self, area):
+	fn describe():
+		"Shape with an area of " + area.__to_str__()
+
+trait Named(self, name):
+
+struct Square(self, side) implements Shape, Named:
+	area = side * side
+	name = "square"
+
+println((Square(3).name, Square(3).(side = 2).describe()))
+"""
+	) == "(square, Shape with an area of 4)\n"
+
+def test_implements() -> None:
+	assert output_from_code(
+		"""\
+trait Shape(self, area):
+trait Named(self, name):
+
+struct Square(self, side) implements Shape:
+	area = side * side
+
+square = Square(3)
+
+if square implements Shape:
+	println((square implements Named, 1 implements Shape, Square implements Shape))
+"""
+	) == "(false, false, false)\n"
+
+def test_missing_trait_fields() -> None:
+	assert output_from_code(
+		"""\
+trait Shape(self, area):
+
+struct Square(self, side) implements Shape:
+
+Square(1)
+""",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-24): A struct is missing a field required by a trait it implements
+
+Square implements Shape, but has no `area` field.
+"""
+
+	assert output_from_code("println(1 implements 2)\n", expected_return_code=1) == """\
+Error (RUNTIME-2): A built-in function was called with an argument of incorrect type
+
+__implements__ expected argument #2 to be of a different type.
+"""
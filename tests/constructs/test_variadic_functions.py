@@ -0,0 +1,119 @@
+from tests import output_from_code
+
+def test_rest_parameters() -> None:
+	assert output_from_code(
+		"""\
+fn count(...values):
+	values.length
+
+println(count())
+"""
+	) == "0\n"
+
+	assert output_from_code(
+		"""\
+fn describe(first, ...rest):
+	println(first, rest)
+
+describe(1, 2, 3)
+"""
+	) == "1 (2, 3)\n"
+
+	assert output_from_code(
+		"""\
+fn describe(first, ...rest):
+	println(first, rest)
+
+describe(1)
+"""
+	) == "1 (,)\n"
+
+	assert output_from_code('println(((...values): values)("foo", "bar"))\n') == "(foo, bar)\n"
+
+def test_rest_parameters_in_structs() -> None:
+	assert output_from_code(
+		"""\
+struct Sum(self, first, ...rest):
+	value = first + rest.length
+
+println(Sum(1, 2, 3), Sum(1, 2, 3).value)
+"""
+	) == "Sum(1, (2, 3)) 3\n"
+
+	assert output_from_code("struct Struct(...self):\n", expected_return_code=1) == """\
+Error (PARSER-7): A struct's first parameter cannot be a rest parameter
+
+  1  │ struct Struct(...self):
+     │                  ^^^^
+
+The first parameter of a struct refers to the struct itself. Consider adding a rest parameter after it.
+"""
+
+def test_spread_arguments() -> None:
+	assert output_from_code(
+		"""\
+fn add(number1, number2, number3):
+	number1 + number2 + number3
+
+numbers = (2, 3)
+
+println(add(1, ...numbers), add(...(1, 2), 3), add(...(1, 2, 3)))
+"""
+	) == "6 6 6\n"
+
+	assert output_from_code(
+		"""\
+fn max(first, ...rest):
+	if rest.length == 0:
+		first
+	else:
+		rest_max = max(...rest)
+
+		if first > rest_max:
+			first
+		else:
+			rest_max
+
+println(max(3, 9, 2))
+"""
+	) == "9\n"
+
+	assert output_from_code("println(...5)\n", expected_return_code=1) == \
+		"Error (RUNTIME-19): A non-tuple was spread into a call's arguments\n"
+
+def test_invalid_rest_parameters() -> None:
+	assert output_from_code(
+		"""\
+fn describe(first, ...rest):
+
+describe()
+""",
+		expected_return_code=1
+	) == "Error (RUNTIME-1): A function accepting at least 1 argument was called with 0 arguments\n"
+
+	assert output_from_code("fn describe(...rest, last):\n", expected_return_code=1) == """\
+Error (PARSER-11): A rest parameter must be the last parameter
+
+  1  │ fn describe(...rest, last):
+     │             ^^^^^^^
+
+A rest parameter collects all remaining arguments, so no parameter can follow it.
+"""
+
+	assert output_from_code("println(((...rest, last): last)(1, 2))\n", expected_return_code=1) == """\
+Error (PARSER-11): A rest parameter must be the last parameter
+
+  1  │ println(((...rest, last): last)(1, 2))
+     │           ^^^^^^^
+
+A rest parameter collects all remaining arguments, so no parameter can follow it.
+"""
+
+	assert output_from_code("struct Bag(self, ...items, label):\n", expected_return_code=1) == """\
+Error (PARSER-11): A rest parameter must be the last parameter
+
+  1  │ struct Bag(self, ...items, label):
+     │                  ^^^^^^^^
+
+A rest parameter collects all remaining arguments, so no parameter can follow it.
+"""
@@ -1,5 +1,5 @@
 import os
-from tests import output_from_code
+from tests import output_from_code, output_from_commands
 
 TEST_LIBRARY_DIRECTORY = os.path.join("tests", "foreign_function_interface", "test_libraries")
 
@@ -32,10 +32,34 @@ def test_loading_nonexistent_library() -> None:
 	) == "Error (RUNTIME-15): The library \"test_library_nonexistent\" wasn't found\n"
 
 def test_loading_invalid_library() -> None:
-	_output_from_code_loading_library(
+	assert _output_from_code_loading_library(
 		'import_library("test_library_invalid")\n',
-		expected_return_code=2
-	)
+		expected_return_code=1
+	).startswith("""\
+Error (RUNTIME-33): Couldn't open the library at "tests/foreign_function_interface/test_libraries/test_library_invalid.so"
+
+The library isn't a shared object built for this platform.
+""")
+
+def test_library_introspection() -> None:
+	assert _output_from_code_loading_library(
+		"""\
+library = import_library("test_library_valid")
+
+println(
+	library.has("Square"),
+	library.has("InvalidSymbol"),
+	library.has("NonexistentSymbol"),
+	library.symbols
+)
+"""
+	) == """\
+true true false \
+(Channel, CyclicList, Divide, ImportConcurrently, InvalidSymbol, Join, MeaningOfLife, Origin, Primes, \
+RandomInteger, Rendezvous, Scale, SharedNodes, Square, Squares)
+"""
+
+	assert output_from_code('println(import_library("math").symbols)\n') == "(SquareRoot,)\n"
 
 def test_loading_nonexistent_symbol() -> None:
 	assert _output_from_code_loading_library(
@@ -86,3 +110,147 @@ Error (RUNTIME-2): A built-in function was called with an argument of incorrect
 
 get expected argument #1 to be of a different type.
 """
+
+def test_registered_libraries() -> None:
+	assert output_from_code('println(import_library("math").get("SquareRoot")(16))\n') == "4\n"
+
+	assert output_from_code(
+		'import_library("math").get("NonexistentSymbol")\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-17): Couldn't fetch the symbol "NonexistentSymbol" from the library at "<built-in>/math"
+
+"NonexistentSymbol" doesn't exist.
+"""
+
+def test_extensions() -> None:
+	assert _output_from_code_loading_library(
+		"""\
+extension = import_library("test_extension")
+add = extension.get("Add")
+
+println(
+	extension.get("Greeting"),
+	add(1, 2, 3),
+	add(1, 0.5),
+	extension.get("Reverse")((1, "two", (true, unit)))
+)
+"""
+	) == "Hello, world! 6 1.5 ((true, (unit)), two, 1)\n"
+
+def test_extension_errors() -> None:
+	assert _output_from_code_loading_library(
+		'import_library("test_extension").get("Fail")()\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-30): Calling "Fail" in the extension at "tests/foreign_function_interface/test_libraries/test_extension.extension" failed
+
+Something went wrong.
+"""
+
+	assert _output_from_code_loading_library(
+		'import_library("test_extension").get("Add")(println)\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-31): An extension function was called with an argument that can't be sent to it
+
+Argument #1 to Add isn't an integer, float, string, boolean, unit or tuple thereof.
+"""
+
+	assert _output_from_code_loading_library(
+		'import_library("test_extension").get("Exit")()\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-29): The extension at "tests/foreign_function_interface/test_libraries/test_extension.extension" exited unexpectedly
+"""
+
+def test_unresponsive_extension() -> None:
+	assert _output_from_code_loading_library(
+		'import_library("test_extension_unresponsive")\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-28): The extension at "tests/foreign_function_interface/test_libraries/test_extension_unresponsive.extension" violated the extension protocol
+
+It didn't respond to `initialize` within 10s.
+"""
+
+def test_loading_go_values() -> None:
+	assert _output_from_code_loading_library(
+		"""\
+library = import_library("test_library_valid")
+origin = library.get("Origin")
+
+println(library.get("Primes"), library.get("Squares"), origin.x, origin.display_name, origin.tag)
+"""
+	) == "(2, 3, 5, 7) ((1, 1), (2, 4), (10, 100)) 0 origin \n"
+
+def test_loading_go_functions() -> None:
+	assert _output_from_code_loading_library(
+		"""\
+library = import_library("test_library_valid")
+divide = library.get("Divide")
+
+println(
+	divide(7, 2),
+	divide(1, 0),
+	library.get("Join")(", ", "a", "b", "c"),
+	library.get("Join")("-"),
+	library.get("Scale")(2, (1.5, 3))
+)
+"""
+	) == "Right(3) Left(division by zero) a, b, c  (3, 6)\n"
+
+	# A project's own `either` module doesn't replace the standard library's in returned errors.
+	assert output_from_commands(
+		{
+			"application/krait.toml": '[package]\nname = "application"\nversion = "0.1.0"\n',
+			"application/either.krait": "Left = 1\nRight = 2\n",
+			"application/main.krait":
+				'println(import_library("test_library_valid").get("Divide")(1, 0))\n'
+		},
+
+		[["main.krait"]],
+		working_directory="application",
+		krait_path_directories=
+			[TEST_LIBRARY_DIRECTORY, os.path.join(TEST_LIBRARY_DIRECTORY, "test_library_valid_")]
+	) == "Left(division by zero)\n"
+
+	assert _output_from_code_loading_library(
+		'import_library("test_library_valid").get("Divide")(1.5, 2)\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-2): A built-in function was called with an argument of incorrect type
+
+Divide expected argument #1 to be of a different type.
+"""
+
+	assert _output_from_code_loading_library(
+		'import_library("test_library_valid").get("Divide")(1)\n',
+		expected_return_code=1
+	) == "Error (RUNTIME-1): A function accepting 2 arguments was called with 1 arguments\n"
+
+	assert _output_from_code_loading_library(
+		'import_library("test_library_valid").get("Channel")()\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-32): A library function returned a value with no Krait equivalent
+
+"Channel" in the library at "tests/foreign_function_interface/test_libraries/test_library_valid_/test_library_valid.so" returned a value of type chan int.
+"""
+
+	assert _output_from_code_loading_library(
+		"""\
+(first, second) = import_library("test_library_valid").get("SharedNodes")()
+
+println(first.value, second.value, first.next)
+"""
+	) == "1 1 (unit)\n"
+
+	assert _output_from_code_loading_library(
+		'import_library("test_library_valid").get("CyclicList")()\n',
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-32): A library function returned a value with no Krait equivalent
+
+"CyclicList" in the library at "tests/foreign_function_interface/test_libraries/test_library_valid_/test_library_valid.so" returned a value of type *main.Node.
+"""
@@ -2,10 +2,16 @@ package main
 
 import (
 	"crypto/rand" // We use `crypto/rand` to ensure `RandomFloat` is different for every invocation
+	"errors"
 	"math"
 	"math/big"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
+	krait_errors "project_umbrella/interpreter/errors"
+	"project_umbrella/interpreter/loader"
 	"project_umbrella/interpreter/parser/parser_types"
 	"project_umbrella/interpreter/runtime"
 	"project_umbrella/interpreter/runtime/value"
@@ -29,7 +35,50 @@ var Square = function.NewBuiltInFunction(
 	parser_types.NormalFunction,
 )
 
-var InvalidSymbol = 0
+// Import every module named at once, each from its own goroutine, returning them in a tuple
+var ImportConcurrently = function.NewBuiltInFunction(
+	function.NewVariadicFunctionArgumentValidator(
+		"import_concurrently",
+		reflect.TypeOf(*new(value_types.StringValue)),
+	),
+
+	func(runtime_ *runtime.Runtime, arguments ...value.Value) value.Value {
+		modules := make([]value.Value, len(arguments))
+		errs := make([]*krait_errors.Error, len(arguments))
+		waitGroup := sync.WaitGroup{}
+
+		for i, argument := range arguments {
+			waitGroup.Add(1)
+
+			go func(i int, argument value.Value) {
+				defer waitGroup.Done()
+
+				errs[i] = krait_errors.Catch(func() {
+					modules[i] = runtime_.LoaderChannel.Load(
+						loader.ModuleRequest,
+						string(argument.(value_types.StringValue)),
+					)
+				})
+			}(i, argument)
+		}
+
+		waitGroup.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				krait_errors.RaiseError(err)
+			}
+		}
+
+		return &value_types.TupleValue{
+			Elements: modules,
+		}
+	},
+
+	parser_types.NormalFunction,
+)
+
+var InvalidSymbol = make(chan int)
 var randomBigInteger = func() *big.Int {
 	result, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
 
@@ -41,3 +90,80 @@ var randomBigInteger = func() *big.Int {
 }()
 
 var RandomInteger = value_types.IntegerValue(randomBigInteger.Int64())
+
+// Plain Go values, converted to Krait values when they're fetched
+var Primes = []int{2, 3, 5, 7}
+var Squares = map[int]int{10: 100, 2: 4, 1: 1}
+var Origin = Point{X: 0, Y: 0, DisplayName: "origin"}
+
+type Point struct {
+	X           int
+	Y           int
+	DisplayName string
+	Label       string `krait:"tag"`
+}
+
+type Node struct {
+	Value int
+	Next  *Node
+}
+
+func CyclicList() *Node {
+	first := &Node{Value: 1, Next: nil}
+	first.Next = &Node{Value: 2, Next: first}
+
+	return first
+}
+
+// The same node twice, which isn't a cycle
+func SharedNodes() []*Node {
+	node := &Node{Value: 1, Next: nil}
+
+	return []*Node{node, node}
+}
+
+func Divide(dividend int64, divisor int64) (int64, error) {
+	if divisor == 0 {
+		return 0, errors.New("division by zero")
+	}
+
+	return dividend / divisor, nil
+}
+
+func Join(separator string, parts ...string) string {
+	return strings.Join(parts, separator)
+}
+
+func Scale(factor float64, vector []float64) (float64, float64) {
+	return factor * vector[0], factor * vector[1]
+}
+
+func Channel() chan int {
+	return nil
+}
+
+var rendezvous = func() *sync.WaitGroup {
+	result := &sync.WaitGroup{}
+	result.Add(2)
+
+	return result
+}()
+
+// Rendezvous waits for a second call, returning whether one was made within 10 seconds. Modules
+// calling it can only both finish if they're loaded in parallel.
+func Rendezvous() bool {
+	done := make(chan struct{})
+	rendezvous.Done()
+
+	go func() {
+		rendezvous.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(10 * time.Second):
+		return false
+	}
+}
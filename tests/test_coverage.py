@@ -0,0 +1,105 @@
+from tests import output_from_commands
+
+CODE = """\
+fn describe(n):
+	if n > 2:
+		"big"
+	else if n > 1:
+		"medium"
+	else:
+		"small"
+
+fn unused():
+	println("never")
+
+println(describe(1), describe(2))
+"""
+
+# Files are named by their absolute paths.
+def file_lcov_records(output: str, filename: str) -> list[str]:
+	lines = output.splitlines()
+	start = next(i for i, line in enumerate(lines) if line.endswith(f"/{filename}"))
+
+	return lines[start + 1:lines.index("end_of_record", start)]
+
+def test_lcov() -> None:
+	output = output_from_commands(
+		{"main.krait": CODE},
+		[
+			["run", "--coverage", "coverage.json", "main.krait"],
+			["coverage", "lcov", "coverage.json"]
+		]
+	)
+
+	assert output.startswith("small medium\n")
+
+	# Each `if` and `else if` is a block of two branches: its condition being true and false.
+	assert file_lcov_records(output, "main.krait") == [
+		"BRDA:2,0,0,0",
+		"BRDA:2,0,1,2",
+		"BRDA:4,1,0,1",
+		"BRDA:4,1,1,1",
+		"BRF:4",
+		"BRH:3",
+		"DA:2,2",
+		"DA:3,0",
+		"DA:4,2",
+		"DA:5,1",
+		"DA:7,1",
+		"DA:10,0",
+		"DA:12,1",
+		"LF:7",
+		"LH:5"
+	]
+
+def test_merging_runs() -> None:
+	output = output_from_commands(
+		{
+			"main.krait": CODE,
+			"big.krait": 'main = import("main")\n\nprintln(main.describe(3))\n'
+		},
+
+		[
+			["run", "--coverage", "coverage.json", "main.krait"],
+			["run", "--coverage", "other.json", "big.krait"],
+			["coverage", "lcov", "coverage.json", "other.json"]
+		]
+	)
+
+	records = file_lcov_records(output, "main.krait")
+
+	assert "BRDA:2,0,0,1" in records
+	assert "BRH:4" in records
+	assert "DA:3,1" in records
+
+def test_annotated_source() -> None:
+	output = output_from_commands(
+		{"main.krait": CODE},
+		[
+			["run", "--coverage", "coverage.json", "main.krait"],
+			["run", "--coverage", "coverage.json", "main.krait"],
+			["coverage", "annotate", "coverage.json"]
+		]
+	)
+
+	lines = output.splitlines()
+	start = next(i for i, line in enumerate(lines) if line.endswith("/main.krait"))
+
+	assert lines[start + 1:start + 17] == [
+		"        -:    1:fn describe(n):",
+		"        4:    2:\tif n > 2:",
+		"branch  0 taken 0",
+		"branch  1 taken 4",
+		"    #####:    3:\t\t\"big\"",
+		"        4:    4:\telse if n > 1:",
+		"branch  0 taken 2",
+		"branch  1 taken 2",
+		"        2:    5:\t\t\"medium\"",
+		"        -:    6:\telse:",
+		"        2:    7:\t\t\"small\"",
+		"        -:    8:",
+		"        -:    9:fn unused():",
+		"    #####:   10:\tprintln(\"never\")",
+		"        -:   11:",
+		"        2:   12:println(describe(1), describe(2))"
+	]
@@ -0,0 +1,148 @@
+from tests import DebugAdapterSession, debugger_output
+
+CODE = """\
+fn double(x):
+	y = x * 2
+	y
+
+fn describe(n):
+	if n > 2:
+		"big"
+	else:
+		"small"
+
+a = 1
+b = double(a)
+c = describe(b)
+println(a, b, c)
+"""
+
+def test_breakpoints_and_stepping() -> None:
+	assert debugger_output(
+		CODE,
+		["break 12", "run", "print a", "step", "backtrace", "next", "print y", "next", "continue"]
+	) == """\
+(krait) Breakpoint set at $DIRECTORY/main.krait:12
+(krait) Stopped at $DIRECTORY/main.krait:12 in main (breakpoint)
+12	b = double(a)
+(krait) a = 1
+(krait) Stopped at $DIRECTORY/main.krait:2 in main.double (step)
+2		y = x * 2
+(krait) *#0 main.double at $DIRECTORY/main.krait:2
+ #1 main at $DIRECTORY/main.krait:12
+(krait) Stopped at $DIRECTORY/main.krait:3 in main.double (step)
+3		y
+(krait) y = 2
+(krait) Stopped at $DIRECTORY/main.krait:13 in main (step)
+13	c = describe(b)
+(krait) 1 2 small
+Program exited
+"""
+
+def test_branches() -> None:
+	# The branches of `if` expressions are part of the function containing them.
+	output = debugger_output(CODE, ["break 9", "run", "backtrace", "locals", "finish", "quit"])
+
+	assert output == """\
+(krait) Breakpoint set at $DIRECTORY/main.krait:9
+(krait) Stopped at $DIRECTORY/main.krait:9 in main.describe (breakpoint)
+9			"small"
+(krait) *#0 main.describe at $DIRECTORY/main.krait:9
+ #1 main at $DIRECTORY/main.krait:13
+(krait) n = 2
+(krait) Stopped at $DIRECTORY/main.krait:14 in main (step)
+14	println(a, b, c)
+(krait) """
+
+def test_failing_program() -> None:
+	output = debugger_output(
+		'fn check(): fail("Failed")\n\ncheck()\n',
+		["run"],
+		expected_return_code=1
+	)
+
+	assert "Program failed" in output
+	assert "Failed" in output
+
+def test_debug_adapter() -> None:
+	with DebugAdapterSession(CODE) as session:
+		assert session.request("initialize")["body"]["supportsConfigurationDoneRequest"]
+		session.wait_for_event("initialized")
+
+		assert session.request("launch", {"program": session.path})["success"]
+		assert session.request(
+			"setBreakpoints",
+			{"source": {"path": session.path}, "breakpoints": [{"line": 2}]}
+		)["body"]["breakpoints"] == [{"verified": True, "line": 2}]
+
+		assert session.request("configurationDone")["success"]
+
+		stopped = session.wait_for_event("stopped")["body"]
+		assert stopped["reason"] == "breakpoint"
+
+		frames = session.request("stackTrace", {"threadId": stopped["threadId"]})["body"]
+
+		assert [(frame["name"], frame["line"]) for frame in frames["stackFrames"]] == [
+			("main.double", 2),
+			("main", 12)
+		]
+
+		assert frames["stackFrames"][0]["source"]["path"] == session.path
+
+		scopes = session.request("scopes", {"frameId": frames["stackFrames"][0]["id"]})["body"]
+		assert [scope["name"] for scope in scopes["scopes"]] == ["main.double", "main"]
+
+		variables = session.request(
+			"variables",
+			{"variablesReference": scopes["scopes"][0]["variablesReference"]}
+		)["body"]["variables"]
+
+		assert [(variable["name"], variable["value"]) for variable in variables] == [("x", "1")]
+
+		evaluated = session.request(
+			"evaluate",
+			{"expression": "a", "frameId": frames["stackFrames"][0]["id"]}
+		)
+
+		assert evaluated["body"]["result"] == "1"
+
+		assert not session.request(
+			"evaluate",
+			{"expression": "z", "frameId": frames["stackFrames"][0]["id"]}
+		)["success"]
+
+		assert session.request("stepOut", {"threadId": stopped["threadId"]})["success"]
+		assert session.wait_for_event("stopped")["body"]["reason"] == "step"
+
+		frames = session.request("stackTrace", {"threadId": stopped["threadId"]})["body"]
+		assert [frame["line"] for frame in frames["stackFrames"]] == [13]
+
+		assert session.request("continue", {"threadId": stopped["threadId"]})["success"]
+
+		# The program's output is sent as events, since stdout carries the protocol.
+		assert session.wait_for_event("output")["body"] == {
+			"category": "stdout",
+			"output": "1 2 small\n"
+		}
+
+		assert session.wait_for_event("exited")["body"] == {"exitCode": 0}
+		session.wait_for_event("terminated")
+		assert session.request("disconnect")["success"]
+
+def test_debug_adapter_stop_on_entry() -> None:
+	with DebugAdapterSession(CODE) as session:
+		session.request("initialize")
+		session.request("launch", {"program": session.path, "stopOnEntry": True})
+		session.request("configurationDone")
+
+		stopped = session.wait_for_event("stopped")["body"]
+		assert stopped["reason"] == "entry"
+
+		threads = session.request("threads")["body"]["threads"]
+		assert threads == [{"id": stopped["threadId"], "name": "main"}]
+
+		frames = session.request("stackTrace", {"threadId": stopped["threadId"]})["body"]
+		assert [frame["name"] for frame in frames["stackFrames"]] == ["main"]
+
+		session.request("continue", {"threadId": stopped["threadId"]})
+		assert session.wait_for_event("exited")["body"] == {"exitCode": 0}
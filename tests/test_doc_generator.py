@@ -0,0 +1,125 @@
+from tests import documentation_pages
+
+SHAPES_SOURCE = """\
+from option import Some
+
+# Something with an area.
+trait Shape(self, area):
+	# Whether the shape is larger than `other`.
+	fn is_larger_than(other): area > other.area
+
+# A rectangle of a given `width` and `height`.
+struct Rectangle(self, width, height) implements Shape:
+	# The product of `width` and `height`.
+	area = width * height
+
+	# A `Rectangle` whose sides are `factor` times as long, or `Some.value` of nothing.
+	fn scaled(factor): Rectangle(width * factor, height * factor)
+
+	_cached = 1
+
+# A unit square.
+unit = Rectangle(1, 1)
+
+fn _helper(): 1
+
+# Add the areas of `shapes`.
+fn total_area(...shapes): 0
+"""
+
+def test_markdown() -> None:
+	pages = documentation_pages(
+		{
+			"shapes.krait": SHAPES_SOURCE,
+			"option.krait": "struct Some(self, value): 1\n"
+		}
+	)
+
+	assert sorted(pages) == ["index.md", "option.md", "shapes.md"]
+	assert pages["index.md"] == """\
+# Modules
+
+- [`option`](option.md)
+- [`shapes`](shapes.md)
+"""
+
+	assert pages["shapes.md"] == """\
+# `shapes`
+
+Imports: [`option`](option.md)
+
+## Structs
+
+### <a id="Rectangle"></a>`struct Rectangle(self, width, height)`
+
+A rectangle of a given `width` and `height`.
+
+Constructor parameters: `width`, `height`
+
+Implements: [`Shape`](#Shape)
+
+#### <a id="Rectangle.area"></a>`area`
+
+The product of `width` and `height`.
+
+#### <a id="Rectangle.scaled"></a>`fn scaled(factor)`
+
+A [`Rectangle`](#Rectangle) whose sides are `factor` times as long, or `Some.value` of nothing.
+
+## Traits
+
+### <a id="Shape"></a>`trait Shape(self, area)`
+
+Something with an area.
+
+Required fields: `area`
+
+#### <a id="Shape.is_larger_than"></a>`fn is_larger_than(other)`
+
+Whether the shape is larger than `other`.
+
+## Functions
+
+### <a id="total_area"></a>`fn total_area(...shapes)`
+
+Add the areas of `shapes`.
+
+## Values
+
+### <a id="unit"></a>`unit`
+
+A unit square.
+"""
+
+def test_html() -> None:
+	pages = documentation_pages(
+		{
+			"main.krait": "# Returns `x` & more.\nfn identity(x): x\n"
+		},
+
+		["--html"]
+	)
+
+	assert sorted(pages) == ["index.html", "main.html"]
+	assert pages["main.html"] == """\
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>main</title>
+</head>
+<body>
+<h1><code>main</code></h1>
+<h2>Functions</h2>
+<h3 id="identity"><code>fn identity(x)</code></h3>
+<p>Returns <code>x</code> &amp; more.</p>
+</body>
+</html>
+"""
+
+def test_standard_library() -> None:
+	pages = documentation_pages({}, ["option", "range", "iterator"])
+
+	assert sorted(pages) == ["index.md", "iterator.md", "option.md", "range.md"]
+	assert "An [`iterator.Iterator`](iterator.md#Iterator) over the integers in the range." in pages["range.md"]
+	assert "[`None`](option.md#None)" in pages["iterator.md"]
@@ -0,0 +1,69 @@
+import re
+
+from tests import formatted_code
+
+def test_canonical_style() -> None:
+	assert formatted_code(
+		"""\
+fn   add( a,b ) :   a+b
+struct Range(self ,start,end):
+	length=end-start
+range=Range(1,2)
+
+
+
+println(add(range.start,-range.end))
+"""
+	) == """\
+fn add(a, b): a + b
+struct Range(self, start, end):
+	length = end - start
+
+range = Range(1, 2)
+
+println(add(range.start, -range.end))
+"""
+
+def test_comments() -> None:
+	assert formatted_code(
+		"""\
+# Leading comment
+fn double(x):
+	result=x*2 # trailing comment
+
+	# Dangling comment
+
+println(double(2))  # another trailing comment
+"""
+	) == """\
+# Leading comment
+fn double(x):
+	result = x * 2 # trailing comment
+
+	# Dangling comment
+
+println(double(2)) # another trailing comment
+"""
+
+def test_line_wrapping() -> None:
+	assert formatted_code(
+		"println(some_function_with_a_long_name(first_argument, second_argument), "
+			"another_function(third_argument, fourth_argument))\n"
+	) == """\
+println(
+	some_function_with_a_long_name(first_argument, second_argument),
+	another_function(third_argument, fourth_argument)
+)
+"""
+
+def test_check() -> None:
+	assert formatted_code("x = 1\n") == "x = 1\n"
+	assert re.match(
+		r"""Error \(ENTRY-8\): Some files aren't formatted
+
+.*main\.krait
+
+Run `interpreter fmt` to format them\.
+$""",
+		formatted_code("x=1\n", expected_return_code=1, arguments=["--check"])
+	)
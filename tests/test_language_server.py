@@ -0,0 +1,285 @@
+from tests import language_server_messages
+
+SHAPES_SOURCE = """\
+struct Point(self, x, y):
+	length = x + y
+
+	fn scaled(factor): Point(x * factor, y * factor)
+
+origin = Point(0, 0)
+"""
+
+MAIN_SOURCE = """\
+from shapes import Point
+
+fn add(a, (b, c), ...rest): a + b
+
+point = Point(1, 2)
+println(add(point.x, (2, 3)))
+"""
+
+def did_open(path: str, source: str) -> dict:
+	return {
+		"jsonrpc": "2.0",
+		"method": "textDocument/didOpen",
+		"params": {
+			"textDocument": {
+				"uri": f"file://$DIRECTORY/{path}",
+				"languageId": "krait",
+				"version": 1,
+				"text": source
+			}
+		}
+	}
+
+def position_request(id_: int, method: str, path: str, line: int, character: int) -> dict:
+	return {
+		"jsonrpc": "2.0",
+		"id": id_,
+		"method": method,
+		"params": {
+			"textDocument": {"uri": f"file://$DIRECTORY/{path}"},
+			"position": {"line": line, "character": character}
+		}
+	}
+
+def span(line: int, start: int, end: int) -> dict:
+	return {
+		"start": {"line": line, "character": start},
+		"end": {"line": line, "character": end}
+	}
+
+def test_diagnostics() -> None:
+	assert language_server_messages({}, [
+		did_open("main.krait", "x = 1\nprintln(y)\n"),
+		did_open("other.krait", "x = 1\nx = 2\n"),
+		did_open("valid.krait", "println(1)\n")
+	]) == [
+		{
+			"jsonrpc": "2.0",
+			"method": "textDocument/publishDiagnostics",
+			"params": {
+				"uri": "file://$DIRECTORY/main.krait",
+				"diagnostics": [
+					{
+						"range": span(1, 8, 9),
+						"severity": 1,
+						"code": "PARSER-6",
+						"source": "krait",
+						"message": "Unknown value: `y`"
+					}
+				]
+			}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"method": "textDocument/publishDiagnostics",
+			"params": {
+				"uri": "file://$DIRECTORY/other.krait",
+				"diagnostics": [
+					{
+						"range": span(1, 0, 5),
+						"severity": 1,
+						"code": "PARSER-5",
+						"source": "krait",
+						"message": "Reassigning to an already declared value is impossible"
+					}
+				]
+			}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"method": "textDocument/publishDiagnostics",
+			"params": {
+				"uri": "file://$DIRECTORY/valid.krait",
+				"diagnostics": []
+			}
+		}
+	]
+
+def test_definitions() -> None:
+	messages = language_server_messages(
+		{
+			"shapes.krait": SHAPES_SOURCE
+		},
+
+		[
+			did_open("main.krait", MAIN_SOURCE),
+			position_request(1, "textDocument/definition", "main.krait", 5, 9),
+			position_request(2, "textDocument/definition", "main.krait", 2, 32),
+			position_request(3, "textDocument/definition", "main.krait", 0, 21),
+			position_request(4, "textDocument/definition", "main.krait", 5, 18),
+			position_request(5, "textDocument/definition", "main.krait", 3, 0)
+		]
+	)
+
+	assert messages[1:] == [
+		{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {"uri": "file://$DIRECTORY/main.krait", "range": span(2, 3, 6)}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"result": {"uri": "file://$DIRECTORY/main.krait", "range": span(2, 11, 12)}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {"uri": "file://$DIRECTORY/shapes.krait", "range": span(0, 7, 12)}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {"uri": "file://$DIRECTORY/shapes.krait", "range": span(0, 19, 20)}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": None
+		}
+	]
+
+def test_hover() -> None:
+	messages = language_server_messages(
+		{
+			"shapes.krait": SHAPES_SOURCE
+		},
+
+		[
+			did_open("main.krait", MAIN_SOURCE),
+			position_request(1, "textDocument/hover", "main.krait", 5, 9),
+			position_request(2, "textDocument/hover", "main.krait", 4, 10),
+			position_request(3, "textDocument/hover", "main.krait", 4, 1)
+		]
+	)
+
+	assert messages[1:] == [
+		{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"contents": {"kind": "markdown", "value": "```krait\nfn add(a, (b, c), ...rest)\n```"},
+				"range": span(5, 8, 11)
+			}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"result": {
+				"contents": {"kind": "markdown", "value": "```krait\nstruct Point(self, x, y)\n```"},
+				"range": span(4, 8, 13)
+			}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": None
+		}
+	]
+
+def test_hover_doc_comments() -> None:
+	messages = language_server_messages({}, [
+		did_open("main.krait", "# Double `x`.\nfn double(x): x * 2\n\n# The answer.\nanswer = double(21)\nprintln(answer)\n"),
+		position_request(1, "textDocument/hover", "main.krait", 5, 9),
+		position_request(2, "textDocument/hover", "main.krait", 4, 10)
+	])
+
+	assert messages[1:] == [
+		{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"contents": {"kind": "markdown", "value": "```krait\nanswer\n```\n\nThe answer."},
+				"range": span(5, 8, 14)
+			}
+		},
+
+		{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"result": {
+				"contents": {"kind": "markdown", "value": "```krait\nfn double(x)\n```\n\nDouble `x`."},
+				"range": span(4, 9, 15)
+			}
+		}
+	]
+
+def test_document_symbols() -> None:
+	messages = language_server_messages({}, [
+		did_open("shapes.krait", SHAPES_SOURCE),
+		{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "textDocument/documentSymbol",
+			"params": {"textDocument": {"uri": "file://$DIRECTORY/shapes.krait"}}
+		}
+	])
+
+	def symbol(name: str, kind: int, range_: dict, selection_range: dict, children=[]) -> dict:
+		return {
+			"name": name,
+			"kind": kind,
+			"range": range_,
+			"selectionRange": selection_range,
+			"children": children
+		}
+
+	assert messages[1]["result"] == [
+		symbol(
+			"Point",
+			23,
+			{"start": {"line": 0, "character": 0}, "end": {"line": 3, "character": 49}},
+			span(0, 7, 12),
+			[
+				symbol("x", 8, span(0, 19, 20), span(0, 19, 20)),
+				symbol("y", 8, span(0, 22, 23), span(0, 22, 23)),
+				symbol("length", 13, span(1, 1, 15), span(1, 1, 7)),
+				symbol("scaled", 12, span(3, 1, 49), span(3, 4, 10))
+			]
+		),
+
+		symbol("origin", 13, span(5, 0, 20), span(5, 0, 6))
+	]
+
+def test_completion() -> None:
+	messages = language_server_messages(
+		{
+			"shapes.krait": SHAPES_SOURCE
+		},
+
+		[
+			did_open("main.krait", "from shapes import Point\n\npoint = Point(1, 2)\npoint.le\n"),
+			position_request(1, "textDocument/completion", "main.krait", 3, 8),
+			did_open("module.krait", 'shapes = import("shapes")\nshapes.\n'),
+			position_request(2, "textDocument/completion", "module.krait", 1, 7),
+			did_open("self.krait", "struct Box(self, value):\n\tdoubled = self.\n"),
+			position_request(3, "textDocument/completion", "self.krait", 1, 16)
+		]
+	)
+
+	assert messages[1]["result"] == [
+		{"label": "x", "kind": 5},
+		{"label": "y", "kind": 5},
+		{"label": "length", "kind": 5},
+		{"label": "scaled", "kind": 2, "detail": "fn scaled(factor)"}
+	]
+
+	assert messages[3]["result"] == [
+		{"label": "Point", "kind": 22, "detail": "struct Point(self, x, y)"},
+		{"label": "origin", "kind": 6}
+	]
+
+	assert messages[5]["result"] == [
+		{"label": "value", "kind": 5},
+		{"label": "doubled", "kind": 5}
+	]
@@ -0,0 +1,103 @@
+from tests import lint_output
+
+def test_clean_code() -> None:
+	assert lint_output(
+		"""\
+from math import sqrt
+
+fn hypotenuse(a, b, _unused):
+	sqrt(a * a + b * b)
+
+println(hypotenuse(3, 4, 5))
+""",
+		expected_return_code=0
+	) == ""
+
+def test_unused_values() -> None:
+	output = lint_output(
+		"""\
+from math import sqrt, floor
+
+_private = 1
+exported = 2
+
+fn add(a, b):
+	unused = 3
+	a
+
+struct Point(self, x, y):
+	length = x + y
+
+println(sqrt(4))
+"""
+	)
+
+	assert "Error (LINT-6): Unused import: `floor`" in output
+	assert "Error (LINT-1): Unused value: `_private`" in output
+	assert "Error (LINT-2): Unused parameter: `b`" in output
+	assert "Error (LINT-1): Unused value: `unused`" in output
+	assert "`sqrt`" not in output
+	assert "`exported`" not in output
+	assert "`length`" not in output
+	assert "`y`" not in output
+	assert output.endswith("Error (ENTRY-10): Found 4 problems\n")
+
+def test_shadowing() -> None:
+	output = lint_output(
+		"""\
+fn log(print, Some):
+	print(Some)
+
+log(println, "Hello")
+"""
+	)
+
+	assert "Error (LINT-3): Built-in or standard library value shadowed: `print`" in output
+	assert "Error (LINT-3): Built-in or standard library value shadowed: `Some`" in output
+	assert output.endswith("Error (ENTRY-10): Found 2 problems\n")
+
+def test_unreachable_branches() -> None:
+	output = lint_output(
+		"""\
+fn describe(x):
+	if x:
+		"x"
+	else if false:
+		"never"
+	else if true:
+		"always"
+	else if x:
+		"unreachable"
+	else:
+		"also unreachable"
+
+println(describe(true))
+"""
+	)
+
+	assert output.count("Error (LINT-4): Unreachable branch") == 2
+	assert "Its condition is always false." in output
+	assert "A previous condition is always true." in output
+
+def test_value_cycles() -> None:
+	output = lint_output(
+		"""\
+table = compute(0)
+
+fn compute(n):
+	if n == 0:
+		0
+	else:
+		table
+
+origin = Point(0, 0)
+
+struct Point(self, x, y):
+	fn is_origin(): x == origin.x
+
+println(table, origin.is_origin())
+"""
+	)
+
+	assert "Error (LINT-5): Function refers to itself through a value: `compute` through `table`" in output
+	assert output.endswith("Error (ENTRY-10): Found 1 problem\n")
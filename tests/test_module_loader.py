@@ -1,6 +1,13 @@
 import os
 import re
-from tests import output_from_code, output_from_multiple_files
+from tests import output_from_code, output_from_commands, output_from_multiple_files
+
+TEST_LIBRARY_DIRECTORY = os.path.join(
+	"tests",
+	"foreign_function_interface",
+	"test_libraries",
+	"test_library_valid_"
+)
 
 def test_imports() -> None:
 	assert output_from_multiple_files(
@@ -139,3 +146,234 @@ fn print_greeting(subject):
 
 		"main.krait"
 	) == "Hello, user!\n"
+
+def test_private_values() -> None:
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+greeting_printer = import("greeting_printer")
+greeting_printer.print_greeting()
+""",
+
+			"greeting_printer.krait": """\
+_user = "user"
+
+fn print_greeting():
+	println("Hello, " + _user + "!")
+"""
+		},
+
+		"main.krait"
+	) == "Hello, user!\n"
+
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+greeting_printer = import("greeting_printer")
+println(greeting_printer("_user"))
+""",
+
+			"greeting_printer.krait": '_user = "user"\n'
+		},
+
+		"main.krait",
+		expected_return_code=1
+	) == """\
+Error (RUNTIME-26): Private field selected: `_user`
+
+Fields whose names begin with an underscore can only be referenced by name from within the code declaring them.
+"""
+
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+io = import("io")
+println(io._library)
+""",
+		},
+
+		"main.krait",
+		expected_return_code=1
+	).startswith("Error (PARSER-9): Private field selected: `_library`\n")
+
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+greeting_printer = import("greeting_printer")
+
+struct Greeter(self):
+	user = greeting_printer._user
+
+println(Greeter().user)
+""",
+
+			"greeting_printer.krait": '_user = "user"\n'
+		},
+
+		"main.krait",
+		expected_return_code=1
+	).startswith("Error (PARSER-9): Private field selected: `_user`\n")
+
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+struct Greeter(self):
+	from greeting_printer import _user
+
+	user = _user
+
+println(Greeter().user)
+""",
+
+			"greeting_printer.krait": '_user = "user"\n'
+		},
+
+		"main.krait",
+		expected_return_code=1
+	).startswith("Error (PARSER-9): Private field selected: `_user`\n")
+
+def test_relative_imports() -> None:
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+util = import("./lib/util")
+
+println(util.double(2))
+""",
+
+			os.path.join("lib", "util.krait"): """\
+shared = import("../shared")
+
+fn double(number): number * shared.factor
+""",
+
+			"shared.krait": "factor = 2\n"
+		},
+
+		"main.krait"
+	) == "4\n"
+
+	assert output_from_code('import("./foo")\n', expected_return_code=1) == \
+		"Error (RUNTIME-13): The module \"./foo\" wasn't found\n"
+
+def test_selective_imports() -> None:
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+from shapes.square import area, perimeter
+from "./shapes/square" import side
+
+println((area(2), perimeter(2), side))
+""",
+
+			os.path.join("shapes", "square.krait"): """\
+side = 1
+
+fn area(side): side * side
+fn perimeter(side): side * 4
+"""
+		},
+
+		"main.krait"
+	) == "(4, 8, 1)\n"
+
+	assert output_from_multiple_files(
+		{
+			"main.krait": "from message import _message\n",
+			"message.krait": '_message = "Hello, world!"\n'
+		},
+
+		"main.krait",
+		expected_return_code=1
+	).startswith("Error (PARSER-9): Private field selected: `_message`\n")
+
+def test_embedded_startup_file() -> None:
+	assert output_from_commands(
+		{
+			"main.krait": "println(Some(1).map((x): x + 1))\n"
+		},
+
+		[["main.krait"]],
+		environment={"KRAIT_STARTUP": None}
+	) == "Some(2)\n"
+
+def test_catching_import_errors() -> None:
+	assert output_from_multiple_files(
+		{
+			"main.krait": 'try((): import("nonexistent"), (error): println(error.code, error.name))\n'
+		},
+
+		"main.krait"
+	) == "13 The module \"nonexistent\" wasn't found\n"
+
+	assert output_from_multiple_files(
+		{
+			"main.krait": 'try((): import("foo"), (error): println(error.code, error.name))\n',
+			"foo.krait": "value = 1 / 0\n"
+		},
+
+		"main.krait"
+	) == "7 Cannot divide by zero\n"
+
+def test_parallel_imports() -> None:
+	# Each module waits for the other to start loading, so both only arrive if they load in parallel
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+(b, c) = import_library("test_library_valid").get("ImportConcurrently")("b", "c")
+
+println(b.arrived, c.arrived)
+""",
+
+			"b.krait": 'arrived = import_library("test_library_valid").get("Rendezvous")()\n',
+			"c.krait": 'arrived = import_library("test_library_valid").get("Rendezvous")()\n'
+		},
+
+		"main.krait",
+		krait_path_directories=[TEST_LIBRARY_DIRECTORY]
+	) == "true true\n"
+
+def test_diamond_imports() -> None:
+	# `d` is imported by both `b` and `c` while they load in parallel, but only loaded once
+	assert output_from_multiple_files(
+		{
+			"main.krait": """\
+(b, c) = import_library("test_library_valid").get("ImportConcurrently")("b", "c")
+
+println(b.value, c.value)
+""",
+
+			"b.krait": 'import("d")\nvalue = "b"\n',
+			"c.krait": 'import("d")\nvalue = "c"\n',
+			"d.krait": 'println("d")\n'
+		},
+
+		"main.krait",
+		krait_path_directories=[TEST_LIBRARY_DIRECTORY]
+	) == "d\nb c\n"
+
+def test_concurrent_import_cycles() -> None:
+	# `b` and `c` are imported concurrently, each by a stack of its own, and whichever stack imports
+	# the other module second closes the cycle. This must raise an error rather than deadlock.
+	for _ in range(5):
+		assert re.search(
+			r"""Error \(RUNTIME-13\): Encountered an import cycle
+
+".*/(b|c)\.krait" couldn't be imported\. See the following import stack\.
+
+.*/main\.krait
+↳ .*/(b|c)\.krait
+↳ .*/\1\.krait$""",
+
+			output_from_multiple_files(
+				{
+					"main.krait": 'import_library("test_library_valid").get("ImportConcurrently")("b", "c")\n',
+					"b.krait": 'c = import("c")\n',
+					"c.krait": 'b = import("b")\n'
+				},
+
+				"main.krait",
+				expected_return_code=1,
+				krait_path_directories=[TEST_LIBRARY_DIRECTORY]
+			)
+		) is not None
@@ -0,0 +1,144 @@
+import gzip
+
+from tests import instrumented_output
+
+FIBONACCI_SOURCE = """\
+fn fibonacci(n):
+	if n < 2:
+		n
+	else:
+		fibonacci(n - 1) + fibonacci(n - 2)
+
+struct Counter(self, count):
+	fn increment(): Counter(count + 1)
+
+double = (x): x * 2
+
+println(fibonacci(5))
+println(Counter(double(1)).increment().count)
+"""
+
+def folded_stacks(code: str, expected_return_code=0) -> dict[str, int]:
+	result = {}
+
+	output = instrumented_output(code, ["run", "--folded", "--profile"], expected_return_code)
+
+	for line in output.decode().splitlines():
+		stack, wall_time = line.rsplit(" ", 1)
+		result[stack] = int(wall_time)
+
+	return result
+
+def varint(encoded: bytes, offset: int) -> tuple[int, int]:
+	"""
+	Decode the varint at `offset`, returning it and the offset following it.
+	"""
+
+	value = shift = 0
+
+	while True:
+		byte = encoded[offset]
+		value |= (byte & 0x7f) << shift
+		shift += 7
+		offset += 1
+
+		if byte < 0x80:
+			return value, offset
+
+def protobuf_fields(encoded: bytes) -> list[tuple[int, int | bytes]]:
+	"""
+	Decode the fields of a protocol buffer message using only varint and length-delimited values.
+	"""
+
+	result = []
+	offset = 0
+
+	while offset < len(encoded):
+		key, offset = varint(encoded, offset)
+		field, wire_type = key >> 3, key & 7
+
+		if wire_type == 0:
+			value, offset = varint(encoded, offset)
+		else:
+			assert wire_type == 2
+			length, offset = varint(encoded, offset)
+			value = encoded[offset:offset + length]
+			offset += length
+
+		result.append((field, value))
+
+	return result
+
+def packed_integers(encoded: bytes) -> list[int]:
+	result = []
+	offset = 0
+
+	while offset < len(encoded):
+		value, offset = varint(encoded, offset)
+		result.append(value)
+
+	return result
+
+def test_folded_stacks() -> None:
+	stacks = folded_stacks(FIBONACCI_SOURCE)
+
+	# `fibonacci(5)` recurses four times, without the branches of `if` appearing as functions.
+	assert "main" + ";main.fibonacci" * 5 in stacks
+	assert "main" + ";main.fibonacci" * 6 not in stacks
+	assert "main;main.Counter" in stacks
+	assert "main;main.Counter.increment;main.Counter" in stacks
+	assert "main;main.double" in stacks
+	assert all(wall_time >= 0 for wall_time in stacks.values())
+
+def test_failing_program() -> None:
+	stacks = folded_stacks(
+		"""\
+fn check(n):
+	if n > 2:
+		fail("Too large")
+	else:
+		check(n + 1)
+
+check(0)
+""",
+		expected_return_code=1
+	)
+
+	assert "main;main.check;main.check;main.check;main.check" in stacks
+
+def test_pprof() -> None:
+	profile = instrumented_output(FIBONACCI_SOURCE, ["run", "--profile"])
+	fields = protobuf_fields(gzip.decompress(profile))
+	strings = [value.decode() for field, value in fields if field == 6]
+	functions = [dict(protobuf_fields(value)) for field, value in fields if field == 5]
+	samples = [protobuf_fields(value) for field, value in fields if field == 2]
+
+	assert strings[0] == ""
+	assert [strings[dict(protobuf_fields(value))[1]] for field, value in fields if field == 1] == [
+		"calls",
+		"wall",
+		"alloc_space",
+		"alloc_objects"
+	]
+
+	lines = {strings[function[2]]: function[5] for function in functions}
+
+	assert lines["main.fibonacci"] == 1
+	assert lines["main.Counter"] == 7
+	assert lines["main.Counter.increment"] == 8
+	assert lines["main.double"] == 10
+
+	fibonacci_id = next(
+		function[1] for function in functions if strings[function[2]] == "main.fibonacci"
+	)
+
+	fibonacci_calls = 0
+
+	for sample in samples:
+		location_ids, values = (packed_integers(value) for _, value in sample)
+
+		# Samples' first locations are those of the functions called last.
+		if location_ids[0] == fibonacci_id:
+			fibonacci_calls += values[0]
+
+	assert fibonacci_calls == 15
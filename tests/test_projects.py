@@ -0,0 +1,165 @@
+from tests import output_from_commands
+
+UTILITIES_FILES = {
+	"utilities/krait.toml": """\
+[package]
+name = "utilities"
+version = "1.0.0"
+sources = ["source"]
+""",
+
+	"utilities/source/math.krait": "fn double(x): x * 2\n"
+}
+
+def test_vendored_dependencies() -> None:
+	assert output_from_commands(
+		{
+			**UTILITIES_FILES,
+			"application/krait.toml": """\
+[package]
+name = "application"
+version = "0.1.0"
+sources = ["source"]
+
+[dependencies]
+utilities = { path = "../utilities", version = "1.0.0" }
+""",
+
+			"application/source/main.krait": """\
+from utilities.math import double
+
+from constants import answer
+
+println(double(answer))
+""",
+
+			"application/source/constants.krait": "answer = 21\n"
+		},
+
+		[["deps", "install"], ["source/main.krait"]],
+		working_directory="application"
+	) == """\
+Locked 1 package in krait.lock
+Vendored utilities 1.0.0
+42
+"""
+
+def test_vendored_dependencies_importing_their_own_modules() -> None:
+	assert output_from_commands(
+		{
+			"counters/krait.toml": """\
+[package]
+name = "counters"
+version = "1.0.0"
+sources = ["source"]
+""",
+
+			"counters/source/helpers.krait": "fn inc(x): x + 1\n",
+			"counters/source/counter.krait": """\
+from helpers import inc
+
+fn twice_incremented(x): inc(inc(x))
+""",
+
+			"application/krait.toml": """\
+[package]
+name = "application"
+version = "0.1.0"
+
+[dependencies]
+counters = { path = "../counters" }
+""",
+
+			"application/main.krait": """\
+from counters.counter import twice_incremented
+
+println(twice_incremented(40))
+"""
+		},
+
+		[["deps", "install"], ["main.krait"]],
+		working_directory="application"
+	) == """\
+Locked 1 package in krait.lock
+Vendored counters 1.0.0
+42
+"""
+
+def test_missing_lockfile() -> None:
+	assert output_from_commands(
+		{
+			"krait.toml": """\
+[package]
+name = "application"
+version = "0.1.0"
+"""
+		},
+
+		[["deps", "vendor"]],
+		expected_return_code=1
+	) == """\
+Error (PROJECT-8): Couldn't find a krait.lock lockfile
+
+Run `interpreter deps lock` to create one.
+"""
+
+def test_version_conflicts() -> None:
+	assert output_from_commands(
+		{
+			**UTILITIES_FILES,
+			"legacy_utilities/krait.toml": """\
+[package]
+name = "utilities"
+version = "0.9.0"
+""",
+
+			"library/krait.toml": """\
+[package]
+name = "library"
+version = "2.0.0"
+
+[dependencies]
+utilities = { path = "../legacy_utilities" }
+""",
+
+			"application/krait.toml": """\
+[package]
+name = "application"
+version = "0.1.0"
+
+[dependencies]
+library = { path = "../library" }
+utilities = { path = "../utilities" }
+"""
+		},
+
+		[["deps", "lock"]],
+		working_directory="application",
+		expected_return_code=1
+	) == """\
+Error (PROJECT-6): Conflicting versions of "utilities" are required
+
+Only one version of each package can be vendored:
+  application requires utilities 1.0.0
+  library requires utilities 0.9.0
+"""
+
+	assert output_from_commands(
+		{
+			**UTILITIES_FILES,
+			"application/krait.toml": """\
+[package]
+name = "application"
+version = "0.1.0"
+
+[dependencies]
+utilities = { path = "../utilities", version = "2.0.0" }
+"""
+		},
+
+		[["deps", "lock"]],
+		working_directory="application",
+		expected_return_code=1
+	) == """\
+Error (PROJECT-5): Version 2.0.0 of "utilities" was required, but version 1.0.0 was found
+"""
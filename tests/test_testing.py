@@ -0,0 +1,73 @@
+from tests import output_from_commands
+
+def test_passing_tests() -> None:
+	output = output_from_commands(
+		{
+			"math_test.krait": """\
+from testing import test, assert, assert_not_equal, assert_fails
+
+fn test_addition():
+	assert(1 + 1 == 2)
+
+fn helper(x):
+	fail("Not a test")
+
+cases = (test("subtraction", (): assert_not_equal(2 - 1, 2)), test("failure", (): assert_fails((): fail(1))))
+""",
+			"math.krait": "fn test_ignored():\n\tfail(\"Not a test file\")\n"
+		},
+
+		[["test"]]
+	)
+
+	assert output == """\
+PASS math_test.krait:3: test_addition
+PASS math_test.krait:9: subtraction
+PASS math_test.krait:9: failure
+
+3 passed, 0 failed
+"""
+
+def test_failing_tests() -> None:
+	output = output_from_commands(
+		{
+			"list_test.krait": """\
+from testing import assert_equal
+
+fn test_passes():
+	assert_equal("a", "a")
+
+fn test_fails():
+	x = 3
+	assert_equal((1, 2, x), (1, 2, 4))
+"""
+		},
+
+		[["test", "list_test.krait"]],
+		expected_return_code=1
+	)
+
+	assert output.startswith("""\
+PASS list_test.krait:3: test_passes
+FAIL list_test.krait:6: test_fails
+    at list_test.krait:8
+    Error (RUNTIME-25): A failure was raised
+
+    Expected values to be equal (- expected, + actual):
+    -(1, 2, 4)
+    +(1, 2, 3)
+
+1 passed, 1 failed
+""")
+
+	assert output.endswith("Error (ENTRY-20): 1 test failed\n")
+
+def test_unloadable_file() -> None:
+	output = output_from_commands(
+		{"broken_test.krait": "fn test_nothing():\n\tunit\n\nfail(\"Broken\")\n"},
+		[["test", "broken_test.krait"]],
+		expected_return_code=1
+	)
+
+	assert output.startswith("FAIL broken_test.krait\n    at broken_test.krait:4\n")
+	assert output.endswith("0 passed, 1 failed\nError (ENTRY-20): 1 test failed\n")
@@ -0,0 +1,61 @@
+import json
+
+from tests import instrumented_output
+
+def trace_events(code: str, expected_return_code=0) -> list[dict]:
+	output = instrumented_output(code, ["run", "--trace"], expected_return_code)
+
+	return json.loads(output)["traceEvents"]
+
+def test_blocks() -> None:
+	events = trace_events(
+		"""\
+fn add(a, b): a + b
+
+println(add(1, 2))
+"""
+	)
+
+	blocks = [event for event in events if event["ph"] == "X"]
+	add_blocks = [block for block in blocks if block["name"] == "main.add"]
+
+	# `a + b` looks up `a.+` before calling it with `b`.
+	assert [
+		[instruction.split(" ")[0] for instruction in block["args"]["instructions"]]
+		for block in add_blocks
+	] == [
+		["VAL_FROM_STRUCT_VAL"],
+		["PUSH_ARG", "VAL_FROM_CALL"],
+		["VAL_COPY"]
+	]
+
+	for block in blocks:
+		assert block["args"]["ready"] <= block["ts"]
+		assert block["dur"] >= 0
+
+	# Every block is evaluated on a goroutine named in the trace.
+	thread_names = {event["tid"]: event["args"]["name"] for event in events if event["ph"] == "M"}
+
+	assert all(thread_names[block["tid"]].startswith("Goroutine ") for block in blocks)
+
+def test_ready_blocks() -> None:
+	events = trace_events(
+		"""\
+a = 1 + 2
+b = 3 + 4
+c = 5 + 6
+
+println(a + b + c)
+"""
+	)
+
+	ready_counts = [event["args"]["ready"] for event in events if event["ph"] == "C"]
+
+	# The three independent additions are ready at once.
+	assert max(ready_counts) >= 3
+	assert ready_counts[-1] == 0
+
+def test_failing_program() -> None:
+	events = trace_events('fn check(): fail("Failed")\n\ncheck()\n', expected_return_code=1)
+
+	assert any(event["name"] == "main.check" for event in events)
@@ -0,0 +1,36 @@
+from tests import WatchSession
+
+def test_changed_module() -> None:
+	with WatchSession(
+		{
+			"main.krait": 'helper = import("./helper")\nother = import("./other")\n\n'
+				+ 'println(helper.value, other.value)\n',
+			"helper.krait": 'println("Loading helper")\n\nvalue = 1\n',
+			"other.krait": 'println("Loading other")\n\nvalue = 2\n'
+		}
+	) as session:
+		# Imports are loaded concurrently, so the modules may be loaded in either order.
+		assert sorted(session.read_until("1 2")) == ["1 2", "Loading helper", "Loading other"]
+
+		# Only the changed module and those importing it are loaded again.
+		session.write("helper.krait", 'println("Loading helper")\n\nvalue = 3\n')
+
+		assert session.read_until("3 2") == [
+			"",
+			"Running again, since helper.krait changed",
+			"",
+			"Loading helper",
+			"3 2"
+		]
+
+def test_errors() -> None:
+	with WatchSession({"main.krait": "println(1 +)\n"}) as session:
+		assert session.read_until("  1  │ println(1 +)")[0].startswith("Error (PARSER-1)")
+
+		session.write("main.krait", 'println("Fixed")\n')
+
+		assert session.read_until("Fixed")[-3:] == [
+			"Running again, since main.krait changed",
+			"",
+			"Fixed"
+		]